@@ -25,7 +25,7 @@ import (
 
 // Action performs a client-side operation.
 type Action interface {
-	action(ctx context.Context, core core.Core, gz bool) (action.Action, action.CallParams, error)
+	action(ctx context.Context, core core.Core, c common.Compression) (action.Action, action.CallParams, error)
 }
 
 // Actions is a composable list of client-side operations.
@@ -46,7 +46,7 @@ func intoActions(ctx context.Context, actions []Action) action.Actions {
 	core := ctx.Value(common.KeyCore).(core.Core)
 	arr := make(action.Actions, 0)
 	for _, action := range actions {
-		a, _, err := action.action(ctx, core, false)
+		a, _, err := action.action(ctx, core, common.Compression{})
 		if err != nil {
 			core.App().Logger().Error("Action preparation error", "error", err)
 			continue
@@ -93,8 +93,8 @@ func (ae ActionEmit) And(a Actions) Actions {
 	return actions([]Actions{ae, a})
 }
 
-func (ae ActionEmit) action(ctx context.Context, core core.Core, gz bool) (action.Action, action.CallParams, error) {
-	payload, err := action.IntoPayload(ae.Arg, gz)
+func (ae ActionEmit) action(ctx context.Context, core core.Core, c common.Compression) (action.Action, action.CallParams, error) {
+	payload, err := action.IntoPayload(ae.Arg, c)
 	if err != nil {
 		return nil, action.CallParams{}, err
 	}
@@ -119,7 +119,7 @@ func (ar ActionLocationReload) And(a Actions) Actions {
 	return actions([]Actions{ar, a})
 }
 
-func (ar ActionLocationReload) action(ctx context.Context, core core.Core, _ bool) (action.Action, action.CallParams, error) {
+func (ar ActionLocationReload) action(ctx context.Context, core core.Core, _ common.Compression) (action.Action, action.CallParams, error) {
 	return &action.LocationReload{}, action.CallParams{Timeout: core.App().Conf().InstanceTTL, Optimistic: true}, nil
 }
 
@@ -137,13 +137,13 @@ func (ar ActionLocationReplace) And(a Actions) Actions {
 	return actions([]Actions{ar, a})
 }
 
-func (a ActionLocationReplace) action(ctx context.Context, core core.Core, _ bool) (action.Action, action.CallParams, error) {
+func (a ActionLocationReplace) action(ctx context.Context, core core.Core, _ common.Compression) (action.Action, action.CallParams, error) {
 	l, err := NewLocation(a.Model)
 	if err != nil {
 		return nil, action.CallParams{}, err
 	}
 	return &action.LocationReplace{
-		URL:    l.String(),
+		URL:    core.App().PathMaker().URL(l),
 		Origin: true,
 	}, action.CallParams{Timeout: core.App().Conf().InstanceTTL, Optimistic: true}, nil
 }
@@ -161,13 +161,13 @@ func (aa ActionLocationAssign) And(a Actions) Actions {
 	return actions([]Actions{aa, a})
 }
 
-func (aa ActionLocationAssign) action(ctx context.Context, core core.Core, _ bool) (action.Action, action.CallParams, error) {
+func (aa ActionLocationAssign) action(ctx context.Context, core core.Core, _ common.Compression) (action.Action, action.CallParams, error) {
 	l, err := NewLocation(aa.Model)
 	if err != nil {
 		return nil, action.CallParams{}, err
 	}
 	return &action.LocationAssign{
-		URL:    l.String(),
+		URL:    core.App().PathMaker().URL(l),
 		Origin: true,
 	}, action.CallParams{Timeout: core.App().Conf().InstanceTTL, Optimistic: true}, nil
 }
@@ -185,7 +185,7 @@ func (aa ActionLocationRawAssign) And(a Actions) Actions {
 	return actions([]Actions{aa, a})
 }
 
-func (a ActionLocationRawAssign) action(ctx context.Context, core core.Core, _ bool) (action.Action, action.CallParams, error) {
+func (a ActionLocationRawAssign) action(ctx context.Context, core core.Core, _ common.Compression) (action.Action, action.CallParams, error) {
 	return &action.LocationAssign{
 		URL:    a.URL,
 		Origin: false,
@@ -212,7 +212,7 @@ func (aa ActionScroll) And(a Actions) Actions {
 	return actions([]Actions{aa, a})
 }
 
-func (a ActionScroll) action(ctx context.Context, core core.Core, _ bool) (action.Action, action.CallParams, error) {
+func (a ActionScroll) action(ctx context.Context, core core.Core, _ common.Compression) (action.Action, action.CallParams, error) {
 	return action.Scroll{
 		Selector: a.Selector,
 		Options:  a.Options,
@@ -233,7 +233,7 @@ func (ai ActionIndicate) And(a Actions) Actions {
 	return actions([]Actions{ai, a})
 }
 
-func (ai ActionIndicate) action(ctx context.Context, core core.Core, _ bool) (action.Action, action.CallParams, error) {
+func (ai ActionIndicate) action(ctx context.Context, core core.Core, _ common.Compression) (action.Action, action.CallParams, error) {
 	return action.Indicate{
 		Indicate: indicatorsOrNil(ai.Indicator),
 		Duration: ai.Duration,
@@ -2,9 +2,11 @@ package doors
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/doors-dev/doors/internal/app"
 	"github.com/doors-dev/doors/internal/common"
@@ -33,9 +35,25 @@ func WithConf(conf Conf) With {
 	})
 }
 
+// Compression configures one content encoding in [Conf].ServerCompression.
+type Compression = common.Compression
+
+// Encoding is an HTTP content coding.
+type Encoding = common.Encoding
+
+const (
+	EncodingGzip   = common.EncodingGzip
+	EncodingBrotli = common.EncodingBrotli
+	EncodingZstd   = common.EncodingZstd
+)
+
 // CSP is the Content-Security-Policy configuration.
 type CSP = common.CSP
 
+// CSPReport is a Content-Security-Policy violation report collected by the
+// built-in endpoint. See [CSP].CollectReports.
+type CSPReport = common.CSPReport
+
 // WithCSP enables Content-Security-Policy header generation for an app.
 func WithCSP(csp CSP) With {
 	return withFunc(func(o *app.Options) {
@@ -43,6 +61,97 @@ func WithCSP(csp CSP) With {
 	})
 }
 
+// RequestPolicy configures the Origin, fetch metadata, and token checks on
+// hook, sync, and undo requests.
+type RequestPolicy = common.RequestPolicy
+
+// WithRequestPolicy rejects hook, sync, and undo requests that come from
+// other sites with status 403. Rejections are logged and counted in the
+// doors_requests_rejected_total metric.
+//
+// Example:
+//
+//	doors.WithRequestPolicy(doors.RequestPolicy{
+//		Origins:      []string{"https://admin.example.com"},
+//		RequireToken: true,
+//	})
+func WithRequestPolicy(p RequestPolicy) With {
+	return withFunc(func(o *app.Options) {
+		o.RequestPolicy = &p
+	})
+}
+
+// SecurityHeaders configures HSTS, Referrer-Policy, X-Content-Type-Options,
+// Cross-Origin-Opener-Policy, Cross-Origin-Resource-Policy, and
+// Permissions-Policy. Empty fields use sane defaults; HSTS is only sent
+// when set.
+type SecurityHeaders = common.SecurityHeaders
+
+// SecurityHeaderOmit as a [SecurityHeaders] field value drops the header.
+const SecurityHeaderOmit = common.SecurityHeaderOmit
+
+// WithSecurityHeaders sends security headers on every response of the app:
+// pages, hooks, sync and undo requests, resources, files served with Use
+// helpers, and error pages. Middleware and handlers can still change them.
+// Use [OverrideSecurityHeaders] to change them for a route.
+//
+// Example:
+//
+//	doors.WithSecurityHeaders(doors.SecurityHeaders{
+//		CrossOriginResourcePolicy: "cross-origin",
+//		PermissionsPolicy:         doors.SecurityHeaderOmit,
+//	})
+func WithSecurityHeaders(h SecurityHeaders) With {
+	return withFunc(func(o *app.Options) {
+		o.Headers = &h
+	})
+}
+
+// RateLimit is a token bucket limit on hook requests: up to Burst requests
+// at once, with one more allowed every Every. Set it on event, form, and hook
+// attrs, or for all of them and per session in [Conf]. Requests over the
+// limit get status 429, which runs the attr's OnError actions.
+//
+// Example:
+//
+//	doors.AClick{
+//		RateLimit: doors.RateLimit{Every: 200 * time.Millisecond, Burst: 5},
+//		On:        onClick,
+//	}
+type RateLimit = common.RateLimit
+
+// RateLimitScope tells which limit rejected a hook request.
+type RateLimitScope = common.RateLimitScope
+
+const (
+	// RateLimitSession is the [Conf] SessionHookRateLimit shared by all hooks
+	// of a session.
+	RateLimitSession = common.RateLimitSession
+	// RateLimitHook is the limit of a single hook.
+	RateLimitHook = common.RateLimitHook
+)
+
+// HookAbuse describes a hook request rejected by a [RateLimit].
+type HookAbuse = common.HookAbuse
+
+// WithHookAbuse sets a callback for hook requests rejected by a [RateLimit].
+// ctx is the session context, so [SessionEnd] or [SessionTag] can act on the
+// offending session. Rejections are logged and counted in the
+// doors_hooks_limited_total metric either way.
+//
+// Example:
+//
+//	doors.WithHookAbuse(func(ctx context.Context, a doors.HookAbuse) {
+//		if a.Rejected >= 50 {
+//			doors.SessionEnd(ctx)
+//		}
+//	})
+func WithHookAbuse(f func(ctx context.Context, abuse HookAbuse)) With {
+	return withFunc(func(o *app.Options) {
+		o.OnHookAbuse = f
+	})
+}
+
 // WithID sets the stable app id used for generated names and session cookies.
 func WithID(id string) With {
 	if id != url.PathEscape(id) {
@@ -61,6 +170,25 @@ func WithIDCookie(name string) With {
 	})
 }
 
+// WithBasePath mounts the app under base, such as "/admin", so it can be
+// registered on a sub-path of a larger [http.ServeMux] without stripping the
+// prefix. Path models stay relative to base: links, navigation, and the
+// Doors system routes are prefixed with it, and requests outside it get 404.
+//
+// A [Location] never includes base. [NewLocation] and the locations it
+// returns have no app context and don't add it: use [Href] for a URL within
+// the app.
+func WithBasePath(base string) With {
+	for segment := range strings.SplitSeq(strings.Trim(base, "/"), "/") {
+		if segment != url.PathEscape(segment) {
+			panic("base path must be URL compatible without escaping")
+		}
+	}
+	return withFunc(func(o *app.Options) {
+		o.BasePath = base
+	})
+}
+
 // WithESProfiles sets the esbuild options provider used for script resources.
 func WithESProfiles(profile func(p string) api.BuildOptions) With {
 	return withFunc(func(o *app.Options) {
@@ -78,6 +206,49 @@ func WithSessionTracker(t SessionTracker) With {
 	})
 }
 
+// SessionBackend persists sessions so they survive process restarts and
+// rolling deploys.
+//
+// Sessions are saved when created, renewed, or expired, when a [SessionKey]
+// value changes, and when the app starts draining. A request carrying the
+// cookie of a session unknown to this process rehydrates it from the backend.
+type SessionBackend = app.SessionBackend
+
+// SessionInfo is a snapshot of a live session returned by [App.Sessions].
+type SessionInfo = app.SessionInfo
+
+// InstanceInfo is a snapshot of a live page instance: its ID, when the
+// browser was last seen, and the current [Location].
+type InstanceInfo = app.InstanceInfo
+
+// Tag is a session label set with [SessionTag] and matched by
+// [App.EndSessions].
+type Tag = app.Tag
+
+// SessionRecord is the state a [SessionBackend] persists for one session.
+type SessionRecord = app.SessionRecord
+
+// WithSessionBackend sets the session persistence backend.
+// If unset, sessions are kept in memory only.
+func WithSessionBackend(b SessionBackend) With {
+	return withFunc(func(o *app.Options) {
+		o.SessionBackend = b
+	})
+}
+
+// NewMemorySessionBackend returns a [SessionBackend] that keeps sessions in
+// memory. It can be shared by several apps in the same process.
+func NewMemorySessionBackend() SessionBackend {
+	return app.NewMemoryBackend()
+}
+
+// NewFileSessionBackend returns a [SessionBackend] that stores one JSON file
+// per session in dir, creating dir if needed. Share dir between the old and
+// new process to keep sessions across a restart.
+func NewFileSessionBackend(dir string) (SessionBackend, error) {
+	return app.NewFileBackend(dir)
+}
+
 // ErrorPage renders an app-level error response.
 type ErrorPage = app.ErrorPage
 
@@ -96,6 +267,39 @@ func WithLogger(l *slog.Logger) With {
 	})
 }
 
+// Tracer starts spans around runtime work. Doors traces hook handlers,
+// door renders, beam propagation, and flushes of the sync stream. Spans
+// carry attributes such as the session, instance, door, and hook IDs and
+// the number of payload bytes.
+//
+// See [TraceRecorder] for an in-memory implementation, and the
+// github.com/doors-dev/doors/doorsotel module for an OpenTelemetry adapter.
+type Tracer = common.Tracer
+
+// Span is a timed unit of work started by a [Tracer].
+type Span = common.Span
+
+// Span names used by Doors.
+const (
+	// SpanHook covers the handling of a hook request.
+	SpanHook = common.SpanHook
+	// SpanDoorRender covers a door update, replacement, or splice, from
+	// rendering the content until its payload is ready to be sent.
+	SpanDoorRender = common.SpanDoorRender
+	// SpanBeamSync covers committing a beam value to one derived view.
+	SpanBeamSync = common.SpanBeamSync
+	// SpanSyncFlush covers writing a frame to the sync stream.
+	SpanSyncFlush = common.SpanSyncFlush
+)
+
+// WithTracer installs the tracer used by Doors internals.
+// If t is nil, nothing is traced.
+func WithTracer(t Tracer) With {
+	return withFunc(func(o *app.Options) {
+		o.Tracer = t
+	})
+}
+
 // NewApp creates a Doors HTTP handler from the root page function.
 //
 // The page function receives the Doors runtime context and request helpers, and
@@ -125,10 +329,23 @@ type App interface {
 	InstanceCount() int
 	// SessionCount returns the number of active sessions.
 	SessionCount() int
+	// ResourceStats returns a snapshot of the resource registry cache.
+	ResourceStats() ResourceStats
+	// Sessions returns snapshots of the live sessions, ordered by ID.
+	Sessions() []SessionInfo
+	// EndSessions ends the sessions labeled with tag (see [SessionTag]) and
+	// returns how many were ended. Their open pages are ended too, and
+	// matching sessions saved in the [SessionBackend] are deleted from it.
+	EndSessions(tag Tag) int
+	// WriteMetrics writes the runtime metrics in the Prometheus text
+	// format. See [UseMetrics] to serve them.
+	WriteMetrics(w io.Writer) error
 	// Drain switches the app into drain mode. Already-started instances are
 	// hard-reloaded on the next navigation. The callback runs at most once,
 	// fired when the final live instance cleans up (or immediately if none
-	// are live). Drain is one-way for the lifetime of the app.
+	// are live). Live sessions are saved to the [SessionBackend] so the next
+	// deployment can rehydrate them. Drain is one-way for the lifetime of
+	// the app.
 	Drain(callback func())
 	http.Handler
 }
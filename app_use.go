@@ -98,6 +98,42 @@ func UseResource(path string, resource ResourceStatic, contentType string) Use {
 	}
 }
 
+// UseMetrics serves runtime metrics at path in the Prometheus text format.
+//
+// It reports sessions, instances by state, instance end causes,
+// synchronization errors, sync queues and round trip times, sync stream
+// frames, hook latency, the resource cache, and runtime worker usage.
+// Scrapers don't keep cookies, so a session created for a metrics request
+// is dropped right away. path must not be "/" or empty. Protect it, for
+// example with a middleware registered before it, if metrics must not be
+// public.
+func UseMetrics(path string) Use {
+	if path == "/" || path == "" {
+		panic(errors.New("UseMetrics cannot serve the root path"))
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != path {
+				next.ServeHTTP(w, r)
+				return
+			}
+			sess := r.Context().Value(common.KeySession).(instance.Session)
+			if c, err := r.Cookie(sess.App().PathMaker().SessionCookie()); err != nil || c.Value != sess.ID() {
+				w.Header().Del("Set-Cookie")
+				sess.Kill()
+			}
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			w.Header().Set("Cache-Control", CacheControlNoStore)
+			if err := sess.App().WriteMetrics(w); err != nil {
+				sess.Logger().Warn("UseMetrics failed to write metrics", "error", err)
+			}
+		})
+	}
+}
+
 // UseFS serves files from fsys under prefix.
 //
 // prefix must not be "/" or empty. cacheControl is written on successful file
@@ -0,0 +1,99 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"io/fs"
+
+	"github.com/doors-dev/doors/internal/app"
+	"github.com/doors-dev/doors/internal/printer"
+	"github.com/doors-dev/doors/internal/resources"
+)
+
+// Asset is a script or stylesheet built ahead of time by [BuildAssets] or at
+// startup by [WithAssets]. Create one with [ScriptAsset] or [StyleAsset].
+type Asset = resources.Asset
+
+// ScriptAssetOptions mirror the attributes of the <script> that uses the
+// asset. They must match for the prebuilt output to be picked up.
+type ScriptAssetOptions struct {
+	// Module matches type="module".
+	Module bool
+	// Bundle matches the bundle attribute.
+	Bundle bool
+	// Inline matches the inline attribute.
+	Inline bool
+	// TypeScript matches type="text/typescript" on in-memory sources.
+	TypeScript bool
+	// Profile matches the profile attribute.
+	Profile string
+}
+
+// ScriptAsset declares the script a <script src=(src)> with matching
+// attributes builds. It panics if Module and Inline are both set.
+func ScriptAsset(src ResourceStatic, o ScriptAssetOptions) Asset {
+	asset, err := printer.ScriptAsset(src, o.Module, o.Bundle, o.Inline, o.TypeScript, o.Profile)
+	if err != nil {
+		panic(err)
+	}
+	return asset
+}
+
+// StyleAsset declares the minified stylesheet a <link rel="stylesheet"> or
+// <style src> with src builds.
+func StyleAsset(src ResourceStatic) Asset {
+	return printer.StyleAsset(src)
+}
+
+// AssetOptions configures [WithAssets].
+type AssetOptions struct {
+	// Manifest is a directory written by [BuildAssets]. Resources found in it
+	// are served as-is instead of being built on first use. Optional.
+	Manifest fs.FS
+	// Build lists assets to build while creating the app, so build errors
+	// surface at startup rather than on first request. Assets present in
+	// Manifest are read from it instead.
+	Build []Asset
+	// Strict makes [NewApp] panic when the manifest cannot be read or any
+	// build fails. Otherwise errors are logged and affected resources fall
+	// back to lazy building.
+	Strict bool
+}
+
+// WithAssets loads prebuilt assets and precompiles the listed ones at
+// startup. Scripts and styles not covered are still built lazily.
+func WithAssets(o AssetOptions) With {
+	return withFunc(func(opt *app.Options) {
+		opt.Assets = app.Assets{
+			Manifest: o.Manifest,
+			Build:    o.Build,
+			Strict:   o.Strict,
+		}
+	})
+}
+
+// BuildAssets builds the Doors client and assets with the esbuild profiles
+// from options, and writes them to dir with a manifest for
+// [AssetOptions.Manifest]. Output files are named by content hash. The app
+// serving the manifest must use the same esbuild profiles and refer to
+// sources by the same paths.
+func BuildAssets(dir string, assets []Asset, options ...With) error {
+	opts := app.Options{}
+	for _, o := range options {
+		o.apply(&opts)
+	}
+	opts.Assets = app.Assets{}
+	return app.NewApp(nil, opts).ResourceRegistry().Export(dir, assets)
+}
@@ -48,6 +48,10 @@ type focusIOEventHook struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p *focusIOEventHook) apply(event string, ctx context.Context, attrs gox.Attrs) error {
@@ -60,6 +64,7 @@ func (p *focusIOEventHook) apply(event string, ctx context.Context, attrs gox.At
 		scope:     p.Scope,
 		before:    p.Before,
 		onError:   p.OnError,
+		rateLimit: p.RateLimit,
 		indicator: p.Indicator,
 		on:        p.On,
 	}.apply(ctx, attrs)
@@ -83,6 +88,10 @@ type focusEventHook struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p *focusEventHook) apply(event string, ctx context.Context, attrs gox.Attrs) error {
@@ -93,6 +102,7 @@ func (p *focusEventHook) apply(event string, ctx context.Context, attrs gox.Attr
 		scope:     p.Scope,
 		before:    p.Before,
 		onError:   p.OnError,
+		rateLimit: p.RateLimit,
 		indicator: p.Indicator,
 		on:        p.On,
 	}.apply(ctx, attrs)
@@ -118,6 +128,10 @@ type AFocus struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (f AFocus) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -148,6 +162,10 @@ type ABlur struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (b ABlur) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -184,6 +202,10 @@ type AFocusIn struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (f AFocusIn) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -220,6 +242,10 @@ type AFocusOut struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (f AFocusOut) Proxy(cur gox.Cursor, elem gox.Elem) error {
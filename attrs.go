@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/doors-dev/doors/internal/core"
@@ -69,12 +71,13 @@ type eventAttr[E any] struct {
 	before    Actions
 	scope     Scopes
 	indicator Indicators
+	rateLimit RateLimit
 	on        func(context.Context, RequestEvent[E]) bool
 }
 
 func (p eventAttr[E]) apply(ctx context.Context, attrs gox.Attrs) error {
 	core := ctx.Value(common.KeyCore).(core.Core)
-	hook, ok := core.Door().RegisterHook(p.handle(core), nil)
+	hook, ok := registerLimitedHook(core, p.rateLimit, p.handle(core))
 	if !ok {
 		return errors.New("door: hook registration failed")
 	}
@@ -109,3 +112,35 @@ func (p *eventAttr[E]) handle(core core.Core) func(ctx context.Context, w http.R
 		})
 	}
 }
+
+// registerLimitedHook registers a hook that answers 429 once limit, or the
+// [Conf] HookRateLimit when limit is zero, is exceeded.
+func registerLimitedHook(core core.Core, limit RateLimit, handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool) (core.Hook, bool) {
+	if !limit.Enabled() {
+		limit = core.App().Conf().HookRateLimit
+	}
+	if !limit.Enabled() {
+		return core.Door().RegisterHook(handler, nil)
+	}
+	var hookID atomic.Uint64
+	bucket := &common.RateBucket{}
+	hook, ok := core.Door().RegisterHook(func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+		ok, retry, rejected := bucket.Take(limit, time.Now())
+		if ok {
+			return handler(ctx, w, r)
+		}
+		common.WriteRateLimited(w, retry)
+		inst := core.Instance()
+		core.App().HookLimited(inst.Session().Context(), common.HookAbuse{
+			Scope:      common.RateLimitHook,
+			InstanceID: inst.ID(),
+			HookID:     hookID.Load(),
+			Rejected:   rejected,
+			RetryAfter: retry,
+			Request:    r,
+		})
+		return false
+	}, nil)
+	hookID.Store(hook.HookID)
+	return hook, ok
+}
@@ -46,6 +46,10 @@ type AHook[T any] struct {
 	// Should return true when the hook is complete and can be removed.
 	// Required.
 	On func(ctx context.Context, r RequestHook[T]) (any, bool)
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (h AHook[T]) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -54,7 +58,7 @@ func (h AHook[T]) Proxy(cur gox.Cursor, elem gox.Elem) error {
 
 func (h AHook[T]) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 	core := ctx.Value(common.KeyCore).(core.Core)
-	hook, ok := core.Door().RegisterHook(h.handle(core), nil)
+	hook, ok := registerLimitedHook(core, h.RateLimit, h.handle(core))
 	if !ok {
 		return errors.New("door: hook registration failed")
 	}
@@ -116,6 +120,13 @@ type ARawHook struct {
 	// Should return true when the hook is complete and can be removed.
 	// Required.
 	On func(ctx context.Context, r RequestRawHook) bool
+	// Receives the progress of the request body as the server reads it.
+	// Optional.
+	Progress Source[UploadProgress]
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (h ARawHook) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -124,7 +135,7 @@ func (h ARawHook) Proxy(cur gox.Cursor, elem gox.Elem) error {
 
 func (h ARawHook) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 	core := ctx.Value(common.KeyCore).(core.Core)
-	hook, ok := core.Door().RegisterHook(h.handle(core), nil)
+	hook, ok := registerLimitedHook(core, h.RateLimit, h.handle(core))
 	if !ok {
 		return errors.New("door: hook registration failed")
 	}
@@ -138,6 +149,7 @@ func (h ARawHook) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 
 func (h *ARawHook) handle(core core.Core) func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+		defer trackUpload(ctx, r, h.Progress)()
 		return h.On(ctx, &request{
 			r:     r,
 			w:     w,
@@ -168,3 +180,18 @@ func (a AData) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 	front.AttrsSetData(attrs, a.Name, a.Value)
 	return nil
 }
+
+// APreserve keeps the element's attributes and form state, such as the value
+// of an input, while the element or one of its descendants has focus and a
+// surrounding [Door] with Morph set is updated. Hooks of the new render are
+// still applied.
+type APreserve struct{}
+
+func (a APreserve) Proxy(cur gox.Cursor, elem gox.Elem) error {
+	return proxyMod(a, cur, elem)
+}
+
+func (a APreserve) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
+	front.AttrsSetPreserve(attrs)
+	return nil
+}
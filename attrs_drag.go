@@ -0,0 +1,331 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/doors-dev/doors/internal/core"
+	"github.com/doors-dev/doors/internal/front"
+	"github.com/doors-dev/gox"
+)
+
+// RequestDrag is the typed request passed to drag event handlers.
+type RequestDrag = RequestEvent[DragEvent]
+
+type dragEventHook struct {
+	// If true, stops the event from bubbling up the DOM.
+	// Optional.
+	StopPropagation bool
+	// If true, prevents the browser's default action for the event.
+	// Optional.
+	PreventDefault bool
+	// If true, only fires when the event occurs on this element itself.
+	// Optional.
+	ExactTarget bool
+	// Defines how the hook is scheduled (e.g. blocking, debounce).
+	// Optional.
+	Scope Scopes
+	// Visual indicators while the hook is running.
+	// Optional.
+	Indicator Indicators
+	// Actions to run before the hook request.
+	// Optional.
+	Before Actions
+	// Backend event handler.
+	// Receives a typed RequestEvent[DragEvent].
+	// Should return true when the hook is complete and can be removed.
+	// Required.
+	On func(context.Context, RequestDrag) bool
+	// Actions to run on error.
+	// Optional.
+	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
+}
+
+func (p *dragEventHook) apply(event string, ctx context.Context, attrs gox.Attrs) error {
+	return eventAttr[DragEvent]{
+		capture: &front.DragCapture{
+			Event:           event,
+			StopPropagation: p.StopPropagation,
+			PreventDefault:  p.PreventDefault,
+			ExactTarget:     p.ExactTarget,
+		},
+		scope:     p.Scope,
+		before:    p.Before,
+		onError:   p.OnError,
+		rateLimit: p.RateLimit,
+		indicator: p.Indicator,
+		on:        p.On,
+	}.apply(ctx, attrs)
+}
+
+// ADragStart prepares a drag start event hook for draggable elements,
+// configuring propagation, scheduling, indicators, and handlers.
+type ADragStart struct {
+	// If true, stops the event from bubbling up the DOM.
+	// Optional.
+	StopPropagation bool
+	// If true, prevents the browser's default action for the event,
+	// which cancels the drag.
+	// Optional.
+	PreventDefault bool
+	// If true, only fires when the event occurs on this element itself.
+	// Optional.
+	ExactTarget bool
+	// Defines how the hook is scheduled (e.g. blocking, debounce).
+	// Optional.
+	Scope Scopes
+	// Visual indicators while the hook is running.
+	// Optional.
+	Indicator Indicators
+	// Actions to run before the hook request.
+	// Optional.
+	Before Actions
+	// Backend event handler.
+	// Receives a typed RequestEvent[DragEvent].
+	// Should return true when the hook is complete and can be removed.
+	// Required.
+	On func(context.Context, RequestDrag) bool
+	// Actions to run on error.
+	// Optional.
+	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
+}
+
+func (p ADragStart) Proxy(cur gox.Cursor, elem gox.Elem) error {
+	return proxyMod(p, cur, elem)
+}
+
+func (p ADragStart) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
+	return (*dragEventHook)(&p).apply("dragstart", ctx, attrs)
+}
+
+// ADragOver prepares a drag over event hook for DOM elements,
+// configuring propagation, scheduling, indicators, and handlers.
+//
+// Browsers fire dragover repeatedly while the pointer is over the element;
+// use a debounce [Scope] to limit requests.
+type ADragOver struct {
+	// If true, stops the event from bubbling up the DOM.
+	// Optional.
+	StopPropagation bool
+	// If true, prevents the browser's default action for the event.
+	// [ADrop] already does it for its element.
+	// Optional.
+	PreventDefault bool
+	// If true, only fires when the event occurs on this element itself.
+	// Optional.
+	ExactTarget bool
+	// Defines how the hook is scheduled (e.g. blocking, debounce).
+	// Optional.
+	Scope Scopes
+	// Visual indicators while the hook is running.
+	// Optional.
+	Indicator Indicators
+	// Actions to run before the hook request.
+	// Optional.
+	Before Actions
+	// Backend event handler.
+	// Receives a typed RequestEvent[DragEvent].
+	// Should return true when the hook is complete and can be removed.
+	// Required.
+	On func(context.Context, RequestDrag) bool
+	// Actions to run on error.
+	// Optional.
+	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
+}
+
+func (p ADragOver) Proxy(cur gox.Cursor, elem gox.Elem) error {
+	return proxyMod(p, cur, elem)
+}
+
+func (p ADragOver) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
+	return (*dragEventHook)(&p).apply("dragover", ctx, attrs)
+}
+
+// ADragEnd prepares a drag end event hook for draggable elements,
+// configuring propagation, scheduling, indicators, and handlers.
+type ADragEnd struct {
+	// If true, stops the event from bubbling up the DOM.
+	// Optional.
+	StopPropagation bool
+	// If true, prevents the browser's default action for the event.
+	// Optional.
+	PreventDefault bool
+	// If true, only fires when the event occurs on this element itself.
+	// Optional.
+	ExactTarget bool
+	// Defines how the hook is scheduled (e.g. blocking, debounce).
+	// Optional.
+	Scope Scopes
+	// Visual indicators while the hook is running.
+	// Optional.
+	Indicator Indicators
+	// Actions to run before the hook request.
+	// Optional.
+	Before Actions
+	// Backend event handler.
+	// Receives a typed RequestEvent[DragEvent].
+	// Should return true when the hook is complete and can be removed.
+	// Required.
+	On func(context.Context, RequestDrag) bool
+	// Actions to run on error.
+	// Optional.
+	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
+}
+
+func (p ADragEnd) Proxy(cur gox.Cursor, elem gox.Elem) error {
+	return proxyMod(p, cur, elem)
+}
+
+func (p ADragEnd) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
+	return (*dragEventHook)(&p).apply("dragend", ctx, attrs)
+}
+
+// ADrop prepares a drop event hook that makes the element a drop target.
+// Dropped files are streamed to the handler as a multipart body; see
+// [RequestDrop].
+type ADrop struct {
+	// If true, stops the event from bubbling up the DOM.
+	// Optional.
+	StopPropagation bool
+	// If true, only fires when the event occurs on this element itself.
+	// Optional.
+	ExactTarget bool
+	// Defines how the hook is scheduled (e.g. blocking, debounce).
+	// Optional.
+	Scope Scopes
+	// Visual indicators while the hook is running.
+	// Optional.
+	Indicator Indicators
+	// Actions to run before the hook request.
+	// Optional.
+	Before Actions
+	// Backend drop handler.
+	// Should return true when the hook is complete and can be removed.
+	// Required.
+	On func(context.Context, RequestDrop) bool
+	// Receives the progress of the request body as the server reads it.
+	// Optional.
+	Progress Source[UploadProgress]
+	// Max request body size in bytes, dropped files included. Zero uses the
+	// [Conf] ServerRequestBodyLimit, negative values disable the limit.
+	// Optional.
+	BodyLimit int
+	// Actions to run on error.
+	// Optional.
+	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
+}
+
+func (d ADrop) Proxy(cur gox.Cursor, elem gox.Elem) error {
+	return proxyMod(d, cur, elem)
+}
+
+func (d ADrop) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
+	core := ctx.Value(common.KeyCore).(core.Core)
+	hook, ok := registerLimitedHook(core, d.RateLimit, d.handle(core))
+	if !ok {
+		return errors.New("door: hook registration failed")
+	}
+	front.AttrsAppendCapture(attrs, front.DropCapture{
+		StopPropagation: d.StopPropagation,
+		ExactTarget:     d.ExactTarget,
+	}, front.Hook{
+		OnError:  intoActions(ctx, actionsOrNil(d.OnError)),
+		Before:   intoActions(ctx, actionsOrNil(d.Before)),
+		Scope:    scopesOrNil(core, d.Scope),
+		Indicate: indicatorsOrNil(d.Indicator),
+		Hook:     hook,
+	})
+	return nil
+}
+
+// dropEventLimit bounds the event part of a drop request.
+const dropEventLimit = 1 << 20
+
+func (d *ADrop) handle(core core.Core) func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+		defer trackUpload(ctx, r, d.Progress)()
+		limit := d.BodyLimit
+		if limit == 0 {
+			limit = core.App().Conf().ServerRequestBodyLimit
+		}
+		if limit > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, int64(limit))
+		}
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return false
+		}
+		part, err := reader.NextPart()
+		if err != nil || part.FormName() != "event" {
+			w.WriteHeader(http.StatusBadRequest)
+			return false
+		}
+		var e DragEvent
+		if err := json.NewDecoder(io.LimitReader(part, dropEventLimit)).Decode(&e); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return false
+		}
+		return d.On(ctx, &dropRequest{
+			eventRequest: eventRequest[DragEvent]{
+				request: request{
+					r:   r,
+					w:   w,
+					ctx: ctx,
+				},
+				e: &e,
+			},
+			stream: MultipartStream{
+				reader: reader,
+			},
+		})
+	}
+}
+
+type dropRequest struct {
+	eventRequest[DragEvent]
+	stream MultipartStream
+}
+
+func (d *dropRequest) Files(partLimit int) *MultipartStream {
+	d.stream.limit = int64(partLimit)
+	return &d.stream
+}
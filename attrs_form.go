@@ -26,7 +26,8 @@ import (
 	"github.com/go-playground/form/v4"
 )
 
-// ARawSubmit handles a form submission with raw multipart access.
+// ARawSubmit handles a form submission with raw multipart access. Use it
+// for large uploads: [RequestRawForm] Stream reads the parts as they arrive.
 type ARawSubmit struct {
 	// Defines how the hook is scheduled (e.g. blocking, debounce).
 	// Optional.
@@ -41,9 +42,16 @@ type ARawSubmit struct {
 	// Should return true when the hook is complete and can be removed.
 	// Required.
 	On func(context.Context, RequestRawForm) bool
+	// Receives the progress of the request body as the server reads it.
+	// Optional.
+	Progress Source[UploadProgress]
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (s ARawSubmit) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -52,7 +60,7 @@ func (s ARawSubmit) Proxy(cur gox.Cursor, elem gox.Elem) error {
 
 func (s ARawSubmit) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 	core := ctx.Value(common.KeyCore).(core.Core)
-	hook, ok := core.Door().RegisterHook(s.handle(core), nil)
+	hook, ok := registerLimitedHook(core, s.RateLimit, s.handle(core))
 	if !ok {
 		return errors.New("door: hook registration failed")
 	}
@@ -68,6 +76,7 @@ func (s ARawSubmit) Modify(ctx context.Context, _ string, attrs gox.Attrs) error
 
 func (s *ARawSubmit) handle(core core.Core) func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+		defer trackUpload(ctx, r, s.Progress)()
 		return s.On(ctx, &request{
 			w:     w,
 			r:     r,
@@ -85,6 +94,11 @@ func init() {
 
 // ASubmit handles a form submission by decoding it into T with
 // go-playground/form.
+//
+// It does not stream: the whole form, files included, is read into memory
+// before On runs, bounded by the [Conf] ServerRequestBodyLimit. For large
+// uploads use [ARawSubmit] and [RequestRawForm] Stream, which reads parts as
+// they arrive with a limit per part.
 type ASubmit[T any] struct {
 	// Defines how the hook is scheduled (e.g. blocking, debounce).
 	// Optional.
@@ -99,9 +113,26 @@ type ASubmit[T any] struct {
 	// Should return true when the hook is complete and can be removed.
 	// Required.
 	On func(context.Context, RequestForm[T]) bool
+	// Maps decoding errors to fields and checks `validate` struct tags and
+	// [FormValidator] on the decoded data. Invalid submissions still reach On,
+	// with field errors in [RequestForm.Errors], instead of failing with 400.
+	// Optional.
+	Validate bool
+	// Receives the field errors of each validated submission, and nil once
+	// the submission is valid. Setting it enables Validate. See
+	// [NewFormErrors].
+	// Optional.
+	Errors Source[FormErrors]
+	// Receives the progress of the request body as the server reads it.
+	// Optional.
+	Progress Source[UploadProgress]
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (s ASubmit[V]) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -110,7 +141,7 @@ func (s ASubmit[V]) Proxy(cur gox.Cursor, elem gox.Elem) error {
 
 func (s ASubmit[V]) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 	core := ctx.Value(common.KeyCore).(core.Core)
-	hook, ok := core.Door().RegisterHook(s.handle(core), nil)
+	hook, ok := registerLimitedHook(core, s.RateLimit, s.handle(core))
 	if !ok {
 		return errors.New("door: hook registration failed")
 	}
@@ -127,22 +158,38 @@ func (s ASubmit[V]) Modify(ctx context.Context, _ string, attrs gox.Attrs) error
 func (s *ASubmit[V]) handle(core core.Core) func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
 		limit := core.App().Conf().ServerRequestBodyLimit
+		finish := trackUpload(ctx, r, s.Progress)
 		r.Body = http.MaxBytesReader(w, r.Body, int64(limit))
 		err := r.ParseMultipartForm(int64(limit))
+		finish()
 		if err != nil {
 			w.WriteHeader(400)
 			w.Write([]byte("Multipart form parsing error"))
 			return false
 		}
 		var v V
+		var errs FormErrors
 		err = formDecoder.Decode(&v, r.Form)
+		if s.Validate || s.Errors != nil {
+			errs, err = validateForm(&v, err)
+			if errors.Is(err, errFormRule) {
+				core.App().Logger().Error("form validation error", "error", err)
+				w.WriteHeader(500)
+				w.Write([]byte("Form validation error"))
+				return false
+			}
+		}
 		if err != nil {
 			w.WriteHeader(400)
 			w.Write([]byte("Form decoding error"))
 			return false
 		}
+		if s.Errors != nil {
+			s.Errors.Update(ctx, errs)
+		}
 		return s.On(ctx, &formHookRequest[V]{
-			data: &v,
+			data:   &v,
+			errors: errs,
 			request: request{
 				w:   w,
 				r:   r,
@@ -177,6 +224,10 @@ type AChange struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p AChange) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -189,6 +240,7 @@ func (p AChange) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 		scope:     p.Scope,
 		before:    p.Before,
 		onError:   p.OnError,
+		rateLimit: p.RateLimit,
 		indicator: p.Indicator,
 		on:        p.On,
 	}.apply(ctx, attrs)
@@ -221,6 +273,10 @@ type AInput struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p AInput) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -235,6 +291,7 @@ func (p AInput) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 		scope:     p.Scope,
 		before:    p.Before,
 		onError:   p.OnError,
+		rateLimit: p.RateLimit,
 		indicator: p.Indicator,
 		on:        p.On,
 	}.apply(ctx, attrs)
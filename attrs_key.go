@@ -87,6 +87,10 @@ type keyEventHook struct {
 	// Actions to run before the hook request.
 	// Optional.
 	Before Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (k *keyEventHook) apply(event string, ctx context.Context, attrs gox.Attrs) error {
@@ -114,6 +118,7 @@ func (k *keyEventHook) apply(event string, ctx context.Context, attrs gox.Attrs)
 		before:    k.Before,
 		scope:     k.Scope,
 		onError:   k.OnError,
+		rateLimit: k.RateLimit,
 		indicator: k.Indicator,
 		on:        k.On,
 	}.apply(ctx, attrs)
@@ -158,6 +163,10 @@ type AKeyDown struct {
 	// Actions to run before the hook request.
 	// Optional.
 	Before Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (k AKeyDown) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -207,6 +216,10 @@ type AKeyUp struct {
 	// Actions to run before the hook request.
 	// Optional.
 	Before Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (k AKeyUp) Proxy(cur gox.Cursor, elem gox.Elem) error {
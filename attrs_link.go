@@ -17,6 +17,7 @@ package doors
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/doors-dev/doors/internal/core"
@@ -47,6 +48,19 @@ func (q pathMatch) pathMatch() pathMatch {
 	return q
 }
 
+// offset shifts segment indexes past n leading base path segments.
+func (q pathMatch) offset(n int) pathMatch {
+	if n == 0 || len(q) != 2 || q[0] != "parts" {
+		return q
+	}
+	parts := q[1].([]int)
+	shifted := make([]int, len(parts))
+	for i, part := range parts {
+		shifted[i] = part + n
+	}
+	return pathMatch{"parts", shifted}
+}
+
 // QueryMatcher customizes how query parameters participate in active-link
 // matching. Matchers can be chained with [QueryMatcher.And].
 type QueryMatcher interface {
@@ -153,7 +167,7 @@ type ALink struct {
 	OnError Actions
 }
 
-func (h *ALink) active() []any {
+func (h *ALink) active(base string) []any {
 	indicators := indicatorsOrNil(h.Active.Indicator)
 	if len(indicators) == 0 {
 		return nil
@@ -165,7 +179,8 @@ func (h *ALink) active() []any {
 	if h.Active.PathMatcher == nil {
 		h.Active.PathMatcher = PathMatcherFull()
 	}
-	return []any{h.Active.PathMatcher, h.Active.QueryMatcher.queryMatch(), h.Active.FragmentMatch, indicators}
+	pathMatcher := h.Active.PathMatcher.pathMatch().offset(strings.Count(base, "/"))
+	return []any{pathMatcher, h.Active.QueryMatcher.queryMatch(), h.Active.FragmentMatch, indicators}
 }
 
 func (h ALink) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -220,12 +235,12 @@ func (h ALink) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
 		OnError:  intoActions(ctx, actionsOrNil(h.OnError)),
 		Hook:     hook,
 	})
-	href := loc.String()
+	href := core.App().PathMaker().URL(loc)
 	if h.Fragment != "" {
 		href += "#" + h.Fragment
 	}
 	attrs.Get("href").Set(href)
-	active := h.active()
+	active := h.active(core.App().PathMaker().Base())
 	if active != nil {
 		front.AttrsSetParent(attrs, core.Door().ID())
 		front.AttrsSetActive(attrs, active)
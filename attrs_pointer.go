@@ -50,6 +50,10 @@ type pointerEventHook struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p *pointerEventHook) apply(event string, ctx context.Context, attrs gox.Attrs) error {
@@ -63,6 +67,7 @@ func (p *pointerEventHook) apply(event string, ctx context.Context, attrs gox.At
 		scope:     p.Scope,
 		before:    p.Before,
 		onError:   p.OnError,
+		rateLimit: p.RateLimit,
 		indicator: p.Indicator,
 		on:        p.On,
 	}.apply(ctx, attrs)
@@ -97,6 +102,10 @@ type AClick struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p AClick) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -136,6 +145,10 @@ type APointerDown struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerDown) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -175,6 +188,10 @@ type APointerUp struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerUp) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -214,6 +231,10 @@ type APointerMove struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerMove) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -253,6 +274,10 @@ type APointerOver struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerOver) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -292,6 +317,10 @@ type APointerOut struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerOut) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -331,6 +360,10 @@ type APointerEnter struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerEnter) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -370,6 +403,10 @@ type APointerLeave struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerLeave) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -409,6 +446,10 @@ type APointerCancel struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p APointerCancel) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -448,6 +489,10 @@ type AGotPointerCapture struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p AGotPointerCapture) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -487,6 +532,10 @@ type ALostPointerCapture struct {
 	// Actions to run on error.
 	// Optional.
 	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
 }
 
 func (p ALostPointerCapture) Proxy(cur gox.Cursor, elem gox.Elem) error {
@@ -0,0 +1,107 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+
+	"github.com/doors-dev/doors/internal/front"
+	"github.com/doors-dev/gox"
+)
+
+// RequestVisible is the typed request passed to [AVisible] handlers.
+type RequestVisible = RequestEvent[VisibilityEvent]
+
+// AVisible prepares a hook that fires when the element scrolls into view,
+// backed by the browser IntersectionObserver.
+//
+// It fires each time the element enters the viewport, and again after each
+// completed hook while the element stays visible, so a sentinel below a
+// list rendered with [Each] can load pages until it is pushed out of view.
+// Return true once there is nothing left to load:
+//
+//	doors.AVisible{
+//		RootMargin: "200px",
+//		Scope:      &doors.ScopeBlocking{},
+//		On: func(ctx context.Context, r doors.RequestVisible) bool {
+//			last := false
+//			items.Mutate(ctx, func(items []Item) []Item {
+//				page := nextPage(len(items))
+//				last = len(page) == 0
+//				return append(items, page...)
+//			})
+//			return last
+//		},
+//	}
+type AVisible struct {
+	// Share of the element, from 0 to 1, that must be visible to count as
+	// in view. Default: 0, any visible pixel.
+	// Optional.
+	Threshold float64
+	// Grows (or, when negative, shrinks) the viewport before checking, in
+	// CSS margin syntax such as "200px 0px". Use it to fire before the
+	// element is actually visible.
+	// Optional.
+	RootMargin string
+	// If true, stops observing after the element becomes visible once.
+	// Optional.
+	Once bool
+	// If true, also fires when the element leaves the viewport, with
+	// [VisibilityEvent].Visible set to false.
+	// Optional.
+	Leave bool
+	// Defines how the hook is scheduled (e.g. blocking, debounce).
+	// Optional.
+	Scope Scopes
+	// Visual indicators while the hook is running.
+	// Optional.
+	Indicator Indicators
+	// Actions to run before the hook request.
+	// Optional.
+	Before Actions
+	// Backend event handler.
+	// Receives a typed RequestEvent[VisibilityEvent].
+	// Should return true when the hook is complete and can be removed.
+	// Required.
+	On func(context.Context, RequestVisible) bool
+	// Actions to run on error.
+	// Optional.
+	OnError Actions
+	// Server-side limit on requests to this hook. Zero uses the [Conf]
+	// HookRateLimit.
+	// Optional.
+	RateLimit RateLimit
+}
+
+func (v AVisible) Proxy(cur gox.Cursor, elem gox.Elem) error {
+	return proxyMod(v, cur, elem)
+}
+
+func (v AVisible) Modify(ctx context.Context, _ string, attrs gox.Attrs) error {
+	return eventAttr[VisibilityEvent]{
+		capture: &front.VisibleCapture{
+			Threshold:  min(max(v.Threshold, 0), 1),
+			RootMargin: v.RootMargin,
+			Once:       v.Once,
+			Leave:      v.Leave,
+		},
+		scope:     v.Scope,
+		before:    v.Before,
+		onError:   v.OnError,
+		rateLimit: v.RateLimit,
+		indicator: v.Indicator,
+		on:        v.On,
+	}.apply(ctx, attrs)
+}
@@ -17,9 +17,13 @@ package doors
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"reflect"
+	"slices"
 
 	"github.com/doors-dev/doors/internal/beam"
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/doors-dev/doors/internal/core"
 	"github.com/doors-dev/doors/internal/path"
 	"github.com/doors-dev/gox"
 )
@@ -28,26 +32,48 @@ import (
 // writable [Source] for the decoded model.
 //
 // The route matches when the current [Location] decodes into M. Updating the
-// source re-encodes M back into the current location.
-func RouteModel[M any, C gox.Comp](render func(s Source[M]) C) RouteSource[Location] {
+// source re-encodes M back into the current location. Use [ModelRoute.Guard]
+// to check access or redirect before the route renders.
+func RouteModel[M any, C gox.Comp](render func(s Source[M]) C) ModelRoute[M] {
 	a, err := path.GetModelAdapter[M]()
 	if err != nil {
 		slog.Error("Model adapter error", "error", err)
 	}
-	return modelSource[M, C]{
+	return ModelRoute[M]{
 		err:     err,
 		adapter: a,
-		render:  render,
+		render: func(s Source[M]) gox.Comp {
+			return render(s)
+		},
 	}
 }
 
-type modelSource[M any, C gox.Comp] struct {
+// ModelRoute is the [RouteSource] created by [RouteModel].
+type ModelRoute[M any] struct {
 	err     error
 	adapter path.ModelAdapter[M]
-	render  func(Source[M]) C
+	render  func(Source[M]) gox.Comp
+	guards  []func(ctx context.Context, m M) (any, bool)
+}
+
+// Guard returns a copy of the route that calls guard with the decoded model
+// each time the route is about to render or its model changes.
+//
+// When guard returns ok, the route renders. Otherwise, if redirect is not
+// nil, the location is redirected to it: a model accepted by [NewLocation]
+// or a [RouteRedirect]. On the initial page load this produces an HTTP
+// redirect response; afterwards it replaces the current history entry. When
+// guard returns neither ok nor a redirect, the route does not match and the
+// next route is tried.
+//
+// Guards run in the order they were added, and the first one that fails
+// decides.
+func (ml ModelRoute[M]) Guard(guard func(ctx context.Context, m M) (redirect any, ok bool)) ModelRoute[M] {
+	ml.guards = append(slices.Clone(ml.guards), guard)
+	return ml
 }
 
-func (ml modelSource[M, C]) sourceRender(l Source[Location]) gox.Editor {
+func (ml ModelRoute[M]) sourceRender(l Source[Location]) gox.Editor {
 	return gox.EditorFunc(func(cur gox.Cursor) error {
 		nl := DeriveSourceEqual(l, func(l Location) M {
 			m, ok := ml.adapter.Decode(l)
@@ -69,7 +95,7 @@ func (ml modelSource[M, C]) sourceRender(l Source[Location]) gox.Editor {
 	})
 }
 
-func (ml modelSource[M, C]) match(l Location) routeMatch {
+func (ml ModelRoute[M]) match(l Location) routeMatch {
 	if ml.err != nil {
 		return routeMatchFalse
 	}
@@ -79,7 +105,23 @@ func (ml modelSource[M, C]) match(l Location) routeMatch {
 	return routeMatchFalse
 }
 
-var _ RouteSource[Location] = modelSource[any, gox.Comp]{}
+func (ml ModelRoute[M]) guard(ctx context.Context, l Location) (any, bool) {
+	if len(ml.guards) == 0 {
+		return nil, true
+	}
+	m, ok := ml.adapter.Decode(l)
+	if !ok {
+		return nil, false
+	}
+	for _, guard := range ml.guards {
+		if redirect, ok := guard(ctx, *m); !ok {
+			return redirect, false
+		}
+	}
+	return nil, true
+}
+
+var _ RouteSource[Location] = ModelRoute[any]{}
 
 // RouteModelBeam creates a URL route for path model M that calls render with
 // a read-only [Beam] for the decoded model.
@@ -330,8 +372,65 @@ const (
 	routeMatchFalse routeMatch = iota
 	routeMatchTrue
 	routeMatchDefault
+	routeMatchRedirect
 )
 
+// RouteRedirect is a guard redirect with an explicit status for the initial
+// page response. See [ModelRoute.Guard].
+type RouteRedirect struct {
+	// Model is the redirect target, anything accepted by [NewLocation].
+	Model any
+	// Status is the HTTP status of the initial page response.
+	// Default: 302 Found.
+	Status int
+}
+
+type guardedRoute[T any] interface {
+	guard(ctx context.Context, v T) (any, bool)
+}
+
+func routeCheck[T any, R RouteSource[T]](ctx context.Context, r R, v T) routeMatch {
+	m := r.match(v)
+	if m != routeMatchTrue {
+		return m
+	}
+	g, ok := any(r).(guardedRoute[T])
+	if !ok {
+		return m
+	}
+	redirect, ok := g.guard(ctx, v)
+	if ok {
+		return routeMatchTrue
+	}
+	if redirect == nil {
+		return routeMatchFalse
+	}
+	routeRedirect(ctx, redirect)
+	return routeMatchRedirect
+}
+
+func routeRedirect(ctx context.Context, redirect any) {
+	core := ctx.Value(common.KeyCore).(core.Core)
+	status := http.StatusFound
+	if r, ok := redirect.(RouteRedirect); ok {
+		redirect = r.Model
+		if r.Status != 0 {
+			status = r.Status
+		}
+	}
+	loc, err := NewLocation(redirect)
+	if err != nil {
+		core.App().Logger().Error("route guard redirect encoding error", "error", err)
+		return
+	}
+	if core.Instance().Redirect(core.App().PathMaker().URL(loc), status) {
+		return
+	}
+	core.Instance().Runtime().Go(context.WithoutCancel(ctx), func(ctx context.Context) {
+		core.Instance().Location().Update(HistoryReplaceContext(ctx), loc)
+	})
+}
+
 // RouteSource is a writable route branch for values of type T1.
 type RouteSource[T1 any] interface {
 	match(v T1) routeMatch
@@ -373,7 +472,10 @@ func routeRender[T any, R RouteSource[T]](
 			checked := -1
 			if index != -1 && !defaultActive {
 				checked = index
-				m := routes[index].match(v)
+				m := routeCheck(ctx, routes[index], v)
+				if m == routeMatchRedirect {
+					return false
+				}
 				if m == routeMatchDefault {
 					defaultActive = true
 					return false
@@ -388,7 +490,11 @@ func routeRender[T any, R RouteSource[T]](
 				if checked == i {
 					continue
 				}
-				m := routes[i].match(v)
+				m := routeCheck(ctx, routes[i], v)
+				if m == routeMatchRedirect {
+					index = prevIndex
+					return false
+				}
 				if m == routeMatchDefault {
 					index = i
 					defaultActive = true
@@ -56,7 +56,11 @@ func XCall[T any](ctx context.Context, action Action) <-chan CallResult[T] {
 func call[T any](ctx context.Context, action Action) <-chan CallResult[T] {
 	core := ctx.Value(common.KeyCore).(core.Core)
 	ch := make(chan CallResult[T], 1)
-	a, params, err := action.action(ctx, core, !core.App().Conf().SolitaireDisableGzip)
+	var compression common.Compression
+	if !core.App().Conf().SolitaireDisableGzip {
+		compression = core.Instance().PayloadCompression()
+	}
+	a, params, err := action.action(ctx, core, compression)
 	res := CallResult[T]{}
 	if err != nil {
 		core.App().Logger().Error("Action preparation error", "error", err)
@@ -0,0 +1,153 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+
+	"github.com/doors-dev/doors/internal/beam"
+	"github.com/doors-dev/doors/internal/cluster"
+	"github.com/doors-dev/gox"
+)
+
+// Broker moves [ClusterSource] updates between nodes.
+//
+// Publish must deliver data to every subscriber of the topic, subscribers
+// on the publishing node included, and keep the publish order of each
+// node. Subscribe handlers run one at a time and must not modify data.
+// Adapters for Redis, NATS, or Postgres LISTEN/NOTIFY only need these two
+// methods. A broker that can drop messages while disconnected may also
+// have an OnReconnect(f func()) func() method, like [NetBroker];
+// [ClusterSource] values resync when f runs.
+type Broker = cluster.Broker
+
+// MemoryBroker is a [Broker] within one process. Use it in tests or to
+// share a [ClusterSource] topic between apps in the same binary.
+type MemoryBroker = cluster.MemoryBroker
+
+// NewMemoryBroker creates an empty [MemoryBroker].
+func NewMemoryBroker() *MemoryBroker {
+	return cluster.NewMemoryBroker()
+}
+
+// BrokerServer is the reference relay for [NetBroker] clients. Run one per
+// cluster, in its own process or next to one of the nodes.
+//
+//	srv := doors.NewBrokerServer()
+//	l, _ := net.Listen("tcp", ":7070")
+//	go srv.Serve(l)
+type BrokerServer = cluster.BrokerServer
+
+// NewBrokerServer creates a [BrokerServer]. Call Serve to accept clients.
+func NewBrokerServer() *BrokerServer {
+	return cluster.NewBrokerServer()
+}
+
+// NetBroker is a [Broker] connected to a [BrokerServer] over TCP or a
+// Unix socket. It reconnects on its own; updates published while it is
+// disconnected are lost and logged, and cluster sources resync after the
+// reconnect.
+type NetBroker = cluster.NetBroker
+
+// DialBroker connects to a [BrokerServer].
+//
+//	broker, err := doors.DialBroker("tcp", "broker.internal:7070")
+func DialBroker(network string, address string) (*NetBroker, error) {
+	return cluster.DialBroker(network, address)
+}
+
+var (
+	// ErrBrokerClosed is returned by a closed [NetBroker] or [BrokerServer].
+	ErrBrokerClosed = cluster.ErrBrokerClosed
+	// ErrBrokerDisconnected is returned by [NetBroker] publishes while it
+	// reconnects.
+	ErrBrokerDisconnected = cluster.ErrBrokerDisconnected
+)
+
+// ClusterSource is a [Source] whose updates reach the sources with the same
+// topic on every node sharing the broker.
+//
+// Values travel as JSON, so T must round-trip through encoding/json.
+// Concurrent updates on different nodes resolve to the same value
+// everywhere: the last update by a logical clock wins. Updates a node
+// receives for its own writes are skipped. A new node starts with init and
+// picks up the current value from the nodes already running.
+//
+// Update, Mutate, and their X variants propagate locally first, exactly
+// like a plain [Source], and then publish the new value. Sources derived
+// with [DeriveSource] replicate through their ClusterSource.
+type ClusterSource[T any] interface {
+	Source[T]
+
+	// Close stops replication. The source keeps its value and keeps working
+	// locally.
+	Close()
+}
+
+// NewClusterSource creates a [ClusterSource] on topic that uses `==` to
+// suppress equal updates.
+//
+// Example:
+//
+//	orders, err := doors.NewClusterSource(broker, "orders.count", 0)
+func NewClusterSource[T comparable](broker Broker, topic string, init T) (ClusterSource[T], error) {
+	return newClusterSource(broker, topic, beam.NewSource(init, beam.DefaultEqual, false))
+}
+
+// NewClusterSourceEqual creates a [ClusterSource] on topic with a custom
+// equality function. See [NewSourceEqual].
+func NewClusterSourceEqual[T any](broker Broker, topic string, init T, equal func(new T, old T) bool) (ClusterSource[T], error) {
+	return newClusterSource(broker, topic, beam.NewSource(init, equal, false))
+}
+
+func newClusterSource[T any](broker Broker, topic string, s beam.Source[T]) (ClusterSource[T], error) {
+	r, err := cluster.NewReplica(broker, topic, s)
+	if err != nil {
+		return nil, err
+	}
+	return clusterSource[T]{r}, nil
+}
+
+type clusterSource[T any] struct {
+	cluster.Replica[T]
+}
+
+func (s clusterSource[T]) Route(routes ...RouteSource[T]) gox.EditorComp {
+	return routeSource(s, routes)
+}
+
+func (s clusterSource[T]) RouteSource(routes ...RouteSource[T]) gox.EditorComp {
+	return s.Route(routes...)
+}
+
+func (s clusterSource[T]) RouteBeam(routes ...RouteBeam[T]) gox.EditorComp {
+	return routeBeam(s, routes)
+}
+
+func (s clusterSource[T]) Effect(ctx context.Context) (T, bool) {
+	return effect(s, ctx)
+}
+
+func (s clusterSource[T]) Bind(f func(v T) gox.Elem) gox.EditorComp {
+	return bind(s, f)
+}
+
+func (s clusterSource[T]) innerBeam() beam.Beamer[T] {
+	return s.Replica
+}
+
+func (s clusterSource[T]) innerLens() beam.Lenser[T] {
+	return s.Replica
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type clusterSettings struct {
+	Units string
+	Theme string
+}
+
+func TestClusterSourceReplicatesDerivedUpdates(t *testing.T) {
+	broker := NewMemoryBroker()
+	a, err := NewClusterSource(broker, "settings", clusterSettings{Units: "metric"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewClusterSource(broker, "settings", clusterSettings{Units: "metric"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	units := DeriveSource(a,
+		func(s clusterSettings) string { return s.Units },
+		func(s clusterSettings, units string) clusterSettings {
+			s.Units = units
+			return s
+		},
+	)
+	units.Update(context.Background(), "imperial")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for b.Get().Units != "imperial" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected derived update to reach other node, got %+v", b.Get())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	a.Close()
+	a.Update(context.Background(), clusterSettings{Units: "metric", Theme: "dark"})
+	time.Sleep(20 * time.Millisecond)
+	if b.Get().Theme != "" {
+		t.Fatalf("expected closed source to stop replicating, got %+v", b.Get())
+	}
+}
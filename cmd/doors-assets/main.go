@@ -0,0 +1,66 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command doors-assets prebuilds the Doors client and local script and style
+// files with the default esbuild profile, and writes them with a manifest
+// for doors.WithAssets.
+//
+// Usage:
+//
+//	doors-assets [-o dir] [-module] [-bundle] [-profile name] file...
+//
+// Files ending in .css are built as stylesheets, everything else as scripts.
+// Paths must be spelled exactly as passed to doors.ResourceLocalFS by the
+// app. Apps with custom esbuild profiles should call doors.BuildAssets
+// instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/doors-dev/doors"
+)
+
+func main() {
+	out := flag.String("o", "assets", "output directory")
+	module := flag.Bool("module", false, "build scripts as ES modules")
+	bundle := flag.Bool("bundle", false, "bundle script imports")
+	profile := flag.String("profile", "", "esbuild profile name")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: doors-assets [flags] file...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	var assets []doors.Asset
+	for _, file := range flag.Args() {
+		src := doors.ResourceLocalFS(file)
+		if strings.EqualFold(filepath.Ext(file), ".css") {
+			assets = append(assets, doors.StyleAsset(src))
+			continue
+		}
+		assets = append(assets, doors.ScriptAsset(src, doors.ScriptAssetOptions{
+			Module:  *module,
+			Bundle:  *bundle,
+			Profile: *profile,
+		}))
+	}
+	if err := doors.BuildAssets(*out, assets); err != nil {
+		fmt.Fprintln(os.Stderr, "doors-assets:", err)
+		os.Exit(1)
+	}
+}
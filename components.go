@@ -103,6 +103,39 @@ func Go(f func(ctx context.Context)) gox.Editor {
 	})
 }
 
+// OverrideSecurityHeaders changes the security headers of the current page
+// response and its hook responses. Non-empty fields replace the values set
+// with [WithSecurityHeaders]; [SecurityHeaderOmit] drops a header.
+//
+// Example:
+//
+//	~(doors.OverrideSecurityHeaders(doors.SecurityHeaders{
+//		PermissionsPolicy: "camera=(self)",
+//	}))
+func OverrideSecurityHeaders(h SecurityHeaders) gox.Editor {
+	return gox.EditorFunc(func(cur gox.Cursor) error {
+		core := cur.Context().Value(common.KeyCore).(core.Core)
+		core.Instance().SetSecurityHeaders(h)
+		return nil
+	})
+}
+
+// Lang sets the lang attribute of the page's <html> element. Like <title>,
+// it can be rendered anywhere; the latest mounted value wins and is synced
+// to the client.
+//
+// Example:
+//
+//	~(doors.Lang("de"))
+func Lang(lang string) gox.Editor {
+	return gox.EditorFunc(func(cur gox.Cursor) error {
+		core := cur.Context().Value(common.KeyCore).(core.Core)
+		cancel := core.Instance().TitleMeta().UpdateLang(lang)
+		core.Door().Clean(cancel)
+		return nil
+	})
+}
+
 // Status sets the initial HTTP status code for the current page render.
 //
 // Example:
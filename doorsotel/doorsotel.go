@@ -0,0 +1,97 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doorsotel reports Doors spans to OpenTelemetry.
+//
+//	app := doors.NewApp(page,
+//		doors.WithTracer(doorsotel.New(otel.Tracer("doors"))),
+//	)
+//
+// It lives in its own module so that apps that don't trace don't depend on
+// OpenTelemetry.
+package doorsotel
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/doors-dev/doors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New returns a [doors.Tracer] that starts spans with t.
+func New(t trace.Tracer) doors.Tracer {
+	return tracer{tracer: t}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func (t tracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, doors.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(convert(nil, "", attrs)...))
+	return ctx, span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s span) SetAttrs(attrs ...slog.Attr) {
+	s.span.SetAttributes(convert(nil, "", attrs)...)
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// convert maps slog attributes to OpenTelemetry ones. Group members are
+// flattened into dotted keys.
+func convert(kvs []attribute.KeyValue, prefix string, attrs []slog.Attr) []attribute.KeyValue {
+	for _, attr := range attrs {
+		key := prefix + attr.Key
+		value := attr.Value.Resolve()
+		switch value.Kind() {
+		case slog.KindString:
+			kvs = append(kvs, attribute.String(key, value.String()))
+		case slog.KindInt64:
+			kvs = append(kvs, attribute.Int64(key, value.Int64()))
+		case slog.KindUint64:
+			kvs = append(kvs, attribute.Int64(key, int64(value.Uint64())))
+		case slog.KindFloat64:
+			kvs = append(kvs, attribute.Float64(key, value.Float64()))
+		case slog.KindBool:
+			kvs = append(kvs, attribute.Bool(key, value.Bool()))
+		case slog.KindDuration:
+			kvs = append(kvs, attribute.Int64(key, value.Duration().Nanoseconds()))
+		case slog.KindTime:
+			kvs = append(kvs, attribute.String(key, value.Time().Format(time.RFC3339Nano)))
+		case slog.KindGroup:
+			if attr.Key != "" {
+				key += "."
+			}
+			kvs = convert(kvs, key, value.Group())
+		default:
+			kvs = append(kvs, attribute.String(key, value.String()))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorsotel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	tracer := New(provider.Tracer("doors"))
+
+	ctx, parent := tracer.Start(context.Background(), "doors.hook",
+		slog.String("instance", "abc"),
+		slog.Uint64("hook", 7),
+		slog.Group("req", slog.Bool("blocking", true), slog.Duration("wait", time.Millisecond)),
+	)
+	_, child := tracer.Start(ctx, "doors.render")
+	child.SetAttrs(slog.Int("bytes", 512), slog.Float64("ratio", 0.5))
+	child.End(nil)
+	parent.End(errors.New("hook failed"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	render, hook := spans[0], spans[1]
+	if hook.Name() != "doors.hook" || render.Name() != "doors.render" {
+		t.Fatalf("unexpected span names %q, %q", hook.Name(), render.Name())
+	}
+	if render.Parent().SpanID() != hook.SpanContext().SpanID() {
+		t.Fatal("expected render span to be a child of the hook span")
+	}
+	for span, want := range map[sdktrace.ReadOnlySpan][]attribute.KeyValue{
+		hook: {
+			attribute.String("instance", "abc"),
+			attribute.Int64("hook", 7),
+			attribute.Bool("req.blocking", true),
+			attribute.Int64("req.wait", time.Millisecond.Nanoseconds()),
+		},
+		render: {
+			attribute.Int64("bytes", 512),
+			attribute.Float64("ratio", 0.5),
+		},
+	} {
+		got := attribute.NewSet(span.Attributes()...)
+		for _, kv := range want {
+			if value, ok := got.Value(kv.Key); !ok || value != kv.Value {
+				t.Fatalf("span %s: expected %s=%v, got %v", span.Name(), kv.Key, kv.Value.Emit(), value.Emit())
+			}
+		}
+	}
+	if status := hook.Status(); status.Code != codes.Error || status.Description != "hook failed" {
+		t.Fatalf("unexpected hook status %+v", status)
+	}
+	if events := hook.Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("expected the error to be recorded, got %+v", events)
+	}
+	if status := render.Status(); status.Code != codes.Unset {
+		t.Fatalf("unexpected render status %+v", status)
+	}
+}
@@ -0,0 +1,469 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorstest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+type action func(p *Page, args []json.RawMessage, payload []byte) (any, error)
+
+// actions mirror the calls of the browser client. They run with p.mu held.
+var actions = map[string]action{
+	"location_reload": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		p.location = p.url
+		return nil, nil
+	},
+	"location_assign":  locationChange,
+	"location_replace": locationChange,
+	"indicate": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		return nil, nil
+	},
+	"scroll": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var sel string
+		if err := decodeArgs(args, &sel); err != nil {
+			return nil, err
+		}
+		s, err := parseSelector(sel)
+		if err != nil || s.find(p.doc) == nil {
+			return nil, errors.New("element to scroll into not found")
+		}
+		return nil, nil
+	},
+	"update_title": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var content string
+		var attrs map[string]string
+		if err := decodeArgs(args, &content, &attrs); err != nil {
+			return nil, err
+		}
+		title := p.doc.find(func(n *node) bool {
+			return n.tag == "title"
+		})
+		if title == nil {
+			title = &node{kind: elementNode, tag: "title"}
+			p.head().appendChild(title)
+		}
+		title.setText(html.UnescapeString(content))
+		syncAttrs(title, attrs)
+		return nil, nil
+	},
+	"update_meta": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var name string
+		var property bool
+		var attrs map[string]string
+		if err := decodeArgs(args, &name, &property, &attrs); err != nil {
+			return nil, err
+		}
+		key := metaKey(property)
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		attrs[key] = name
+		meta := p.meta(key, name)
+		if meta == nil {
+			meta = &node{kind: elementNode, tag: "meta"}
+			p.head().appendChild(meta)
+		}
+		syncAttrs(meta, attrs)
+		return nil, nil
+	},
+	"update_lang": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var lang string
+		if err := decodeArgs(args, &lang); err != nil {
+			return nil, err
+		}
+		html := p.doc.find(func(n *node) bool {
+			return n.tag == "html"
+		})
+		if html == nil {
+			return nil, nil
+		}
+		if lang == "" {
+			html.removeAttr("lang")
+		} else {
+			html.setAttr("lang", lang)
+		}
+		return nil, nil
+	},
+	"update_alternate": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var hreflang string
+		var attrs map[string]string
+		if err := decodeArgs(args, &hreflang, &attrs); err != nil {
+			return nil, err
+		}
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		attrs["rel"] = "alternate"
+		attrs["hreflang"] = hreflang
+		link := p.alternate(hreflang)
+		if link == nil {
+			link = &node{kind: elementNode, tag: "link"}
+			p.head().appendChild(link)
+		}
+		syncAttrs(link, attrs)
+		return nil, nil
+	},
+	"remove_alternate": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var hreflang string
+		if err := decodeArgs(args, &hreflang); err != nil {
+			return nil, err
+		}
+		if link := p.alternate(hreflang); link != nil {
+			link.remove()
+		}
+		return nil, nil
+	},
+	"remove_meta": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var name string
+		var property bool
+		if err := decodeArgs(args, &name, &property); err != nil {
+			return nil, err
+		}
+		if meta := p.meta(metaKey(property), name); meta != nil {
+			meta.remove()
+		}
+		return nil, nil
+	},
+	"report_hook": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var track uint64
+		if err := decodeArgs(args, &track); err != nil {
+			return nil, err
+		}
+		p.reported[track] = true
+		return nil, nil
+	},
+	"emit": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var name string
+		if err := decodeArgs(args, &name); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("handler %s not found", name)
+	},
+	"dyna_set": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var id uint64
+		var value string
+		if err := decodeArgs(args, &id, &value); err != nil {
+			return nil, err
+		}
+		return p.dyna(id, func(n *node, name string) {
+			n.setAttr(name, value)
+		}), nil
+	},
+	"dyna_remove": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var id uint64
+		if err := decodeArgs(args, &id); err != nil {
+			return nil, err
+		}
+		return p.dyna(id, func(n *node, name string) {
+			n.removeAttr(name)
+		}), nil
+	},
+	"set_path": func(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+		var path string
+		if err := decodeArgs(args, &path); err != nil {
+			return nil, err
+		}
+		p.url = path
+		return nil, nil
+	},
+	"door_replace": func(p *Page, args []json.RawMessage, payload []byte) (any, error) {
+		door, err := p.doorArg(args)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := parseHTML(string(payload))
+		if err != nil {
+			return nil, err
+		}
+		door.replaceWith(nodes)
+		return nil, nil
+	},
+	"door_update": func(p *Page, args []json.RawMessage, payload []byte) (any, error) {
+		door, err := p.doorArg(args)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := parseHTML(string(payload))
+		if err != nil {
+			return nil, err
+		}
+		door.setChildren(nodes)
+		return nil, nil
+	},
+	"door_splice": func(p *Page, args []json.RawMessage, payload []byte) (any, error) {
+		door, err := p.doorArg(args)
+		if err != nil {
+			return nil, err
+		}
+		var order []*uint64
+		if len(args) > 1 {
+			if err := json.Unmarshal(args[1], &order); err != nil {
+				return nil, err
+			}
+		}
+		nodes, err := parseHTML(string(payload))
+		if err != nil {
+			return nil, err
+		}
+		fragment := &node{kind: documentNode}
+		fragment.setChildren(nodes)
+		var fresh []*node
+		for _, n := range nodes {
+			if isDoor(n) {
+				fresh = append(fresh, n)
+			}
+		}
+		var children []*node
+		wanted := make(map[*node]bool)
+		for _, entry := range order {
+			var el *node
+			if entry == nil && len(fresh) > 0 {
+				el, fresh = fresh[0], fresh[1:]
+			} else if entry != nil {
+				el = p.door(*entry)
+			}
+			if el != nil && (el.parent == door || el.parent == fragment) {
+				children = append(children, el)
+				wanted[el] = true
+			}
+		}
+		ref := firstElement(door)
+		for _, el := range children {
+			for ref != nil && !wanted[ref] {
+				ref = ref.nextElement()
+			}
+			if el == ref {
+				ref = ref.nextElement()
+				continue
+			}
+			door.insertBefore(el, ref)
+		}
+		for len(fragment.children) > 0 {
+			door.insertBefore(fragment.children[0], nil)
+		}
+		return nil, nil
+	},
+}
+
+// call runs the named action like the browser client does.
+func (p *Page) call(name string, args []json.RawMessage, payload []byte) (any, error) {
+	a, ok := actions[name]
+	if !ok {
+		return nil, fmt.Errorf("action [%s] not found", name)
+	}
+	return a(p, args, payload)
+}
+
+// callEncoded runs an action as encoded in the D0-After header of a hook
+// response: name, arguments, and an optional [type, content] payload.
+func (p *Page) callEncoded(encoded []json.RawMessage) error {
+	if len(encoded) < 2 {
+		return errors.New("doorstest: malformed action")
+	}
+	var name string
+	var args []json.RawMessage
+	if err := json.Unmarshal(encoded[0], &name); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded[1], &args); err != nil {
+		return err
+	}
+	var payload []byte
+	if len(encoded) > 2 {
+		var err error
+		if payload, err = decodePayload(encoded[2]); err != nil {
+			return err
+		}
+	}
+	_, err := p.call(name, args, payload)
+	return err
+}
+
+func decodePayload(data json.RawMessage) ([]byte, error) {
+	var encoded []json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil || len(encoded) != 2 {
+		return nil, errors.New("doorstest: malformed payload")
+	}
+	var t byte
+	if err := json.Unmarshal(encoded[0], &t); err != nil {
+		return nil, err
+	}
+	if t == payloadNone {
+		return nil, nil
+	}
+	if t&payloadCoding == 0 {
+		switch t {
+		case payloadJSON:
+			return encoded[1], nil
+		case payloadText:
+			var text string
+			err := json.Unmarshal(encoded[1], &text)
+			return []byte(text), err
+		}
+	}
+	var b64 string
+	if err := json.Unmarshal(encoded[1], &b64); err != nil {
+		return nil, err
+	}
+	content, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(t, content)
+}
+
+// decodeArgs decodes leading arguments into dst. Missing trailing arguments
+// leave their destinations untouched.
+func decodeArgs(args []json.RawMessage, dst ...any) error {
+	for i, d := range dst {
+		if i >= len(args) {
+			return nil
+		}
+		if err := json.Unmarshal(args[i], d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func locationChange(p *Page, args []json.RawMessage, _ []byte) (any, error) {
+	var href string
+	var origin bool
+	if err := decodeArgs(args, &href, &origin); err != nil {
+		return nil, err
+	}
+	if _, err := url.Parse(href); err != nil {
+		return nil, err
+	}
+	p.location = href
+	return nil, nil
+}
+
+func (p *Page) doorArg(args []json.RawMessage) (*node, error) {
+	var id uint64
+	if err := decodeArgs(args, &id); err != nil {
+		return nil, err
+	}
+	door := p.door(id)
+	if door == nil {
+		return nil, fmt.Errorf("door %d not found", id)
+	}
+	return door, nil
+}
+
+// door returns the <d0-r> element or the proxied element of a door.
+func (p *Page) door(id uint64) *node {
+	elementID := "d0r" + strconv.FormatUint(id, 10)
+	proxyID := strconv.FormatUint(id, 10)
+	return p.doc.find(func(n *node) bool {
+		if n.tag == "d0-r" {
+			v, _ := n.attr("id")
+			return v == elementID
+		}
+		v, ok := n.attr("data-d0r")
+		return ok && v == proxyID
+	})
+}
+
+func isDoor(n *node) bool {
+	if n.kind != elementNode {
+		return false
+	}
+	_, ok := n.attr("data-d0r")
+	return ok || n.tag == "d0-r"
+}
+
+func firstElement(n *node) *node {
+	for _, c := range n.children {
+		if c.kind == elementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+// dyna applies f to the elements bound to the dynamic attribute id and
+// returns their count.
+func (p *Page) dyna(id uint64, f func(n *node, name string)) int {
+	count := 0
+	p.doc.walk(func(n *node) bool {
+		value, ok := n.attr("data-d0y")
+		if !ok {
+			return true
+		}
+		var list [][2]json.RawMessage
+		if json.Unmarshal([]byte(value), &list) != nil {
+			return true
+		}
+		for _, entry := range list {
+			var entryID uint64
+			var name string
+			if json.Unmarshal(entry[0], &entryID) != nil || json.Unmarshal(entry[1], &name) != nil {
+				continue
+			}
+			if entryID == id {
+				f(n, name)
+				count += 1
+			}
+		}
+		return true
+	})
+	return count
+}
+
+func (p *Page) head() *node {
+	if head := p.doc.find(func(n *node) bool { return n.tag == "head" }); head != nil {
+		return head
+	}
+	return p.doc
+}
+
+func (p *Page) meta(key string, name string) *node {
+	return p.head().find(func(n *node) bool {
+		v, ok := n.attr(key)
+		return n.tag == "meta" && ok && v == name
+	})
+}
+
+func (p *Page) alternate(hreflang string) *node {
+	return p.head().find(func(n *node) bool {
+		rel, _ := n.attr("rel")
+		v, ok := n.attr("hreflang")
+		return n.tag == "link" && rel == "alternate" && ok && v == hreflang
+	})
+}
+
+func metaKey(property bool) string {
+	if property {
+		return "property"
+	}
+	return "name"
+}
+
+func syncAttrs(n *node, attrs map[string]string) {
+	n.attrs = n.attrs[:0]
+	for _, name := range slices.Sorted(maps.Keys(attrs)) {
+		n.setAttr(name, attrs[name])
+	}
+}
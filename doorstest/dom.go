@@ -0,0 +1,575 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorstest
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+type nodeKind int
+
+const (
+	elementNode nodeKind = iota
+	textNode
+	commentNode
+	doctypeNode
+	documentNode
+)
+
+type attr struct {
+	name  string
+	value string
+}
+
+// node is an element, text, comment, or doctype of the in-memory DOM.
+type node struct {
+	kind     nodeKind
+	tag      string
+	attrs    []attr
+	data     string
+	parent   *node
+	children []*node
+}
+
+var voidTags = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+
+var rawTags = []string{"script", "style", "textarea", "title"}
+
+func (n *node) attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.name == name {
+			return a.value, true
+		}
+	}
+	return "", false
+}
+
+func (n *node) setAttr(name string, value string) {
+	for i, a := range n.attrs {
+		if a.name == name {
+			n.attrs[i].value = value
+			return
+		}
+	}
+	n.attrs = append(n.attrs, attr{name: name, value: value})
+}
+
+func (n *node) removeAttr(name string) {
+	n.attrs = slices.DeleteFunc(n.attrs, func(a attr) bool {
+		return a.name == name
+	})
+}
+
+func (n *node) index() int {
+	return slices.Index(n.parent.children, n)
+}
+
+func (n *node) setChildren(children []*node) {
+	for _, c := range children {
+		c.parent = n
+	}
+	n.children = children
+}
+
+// replaceWith puts nodes in place of n.
+func (n *node) replaceWith(nodes []*node) {
+	parent := n.parent
+	i := n.index()
+	for _, c := range nodes {
+		c.parent = parent
+	}
+	parent.children = slices.Replace(parent.children, i, i+1, nodes...)
+	n.parent = nil
+}
+
+func (n *node) remove() {
+	n.replaceWith(nil)
+}
+
+// in reports whether n is root or one of its descendants.
+func (n *node) in(root *node) bool {
+	for el := n; el != nil; el = el.parent {
+		if el == root {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) appendChild(c *node) {
+	c.parent = n
+	n.children = append(n.children, c)
+}
+
+func (n *node) walk(f func(*node) bool) bool {
+	for _, c := range n.children {
+		if c.kind != elementNode {
+			continue
+		}
+		if !f(c) || !c.walk(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) closest(f func(*node) bool) *node {
+	for el := n; el != nil && el.kind == elementNode; el = el.parent {
+		if f(el) {
+			return el
+		}
+	}
+	return nil
+}
+
+// find returns the first descendant element for which f reports true.
+func (n *node) find(f func(*node) bool) *node {
+	var found *node
+	n.walk(func(el *node) bool {
+		if f(el) {
+			found = el
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// insertBefore moves c into n before ref, or to the end if ref is nil.
+func (n *node) insertBefore(c *node, ref *node) {
+	if c.parent != nil {
+		c.parent.children = slices.DeleteFunc(c.parent.children, func(el *node) bool {
+			return el == c
+		})
+	}
+	c.parent = n
+	i := len(n.children)
+	if ref != nil {
+		i = ref.index()
+	}
+	n.children = slices.Insert(n.children, i, c)
+}
+
+// nextElement returns the next element sibling of n, or nil.
+func (n *node) nextElement() *node {
+	siblings := n.parent.children
+	for _, c := range siblings[n.index()+1:] {
+		if c.kind == elementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *node) text() string {
+	if n.kind == textNode {
+		return n.data
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		if c.kind == textNode || c.kind == elementNode {
+			b.WriteString(c.text())
+		}
+	}
+	return b.String()
+}
+
+func (n *node) setText(text string) {
+	n.setChildren([]*node{{kind: textNode, data: text}})
+}
+
+func (n *node) outerHTML() string {
+	var b strings.Builder
+	n.render(&b)
+	return b.String()
+}
+
+func (n *node) innerHTML() string {
+	var b strings.Builder
+	for _, c := range n.children {
+		c.render(&b)
+	}
+	return b.String()
+}
+
+func (n *node) render(b *strings.Builder) {
+	switch n.kind {
+	case textNode:
+		if n.parent != nil && n.parent.kind == elementNode && (n.parent.tag == "script" || n.parent.tag == "style") {
+			b.WriteString(n.data)
+			return
+		}
+		b.WriteString(html.EscapeString(n.data))
+	case commentNode:
+		b.WriteString("<!--")
+		b.WriteString(n.data)
+		b.WriteString("-->")
+	case doctypeNode:
+		b.WriteString("<!")
+		b.WriteString(n.data)
+		b.WriteString(">")
+	case elementNode:
+		b.WriteString("<")
+		b.WriteString(n.tag)
+		for _, a := range n.attrs {
+			b.WriteString(" ")
+			b.WriteString(a.name)
+			b.WriteString(`="`)
+			b.WriteString(html.EscapeString(a.value))
+			b.WriteString(`"`)
+		}
+		b.WriteString(">")
+		if slices.Contains(voidTags, n.tag) {
+			return
+		}
+		for _, c := range n.children {
+			c.render(b)
+		}
+		b.WriteString("</")
+		b.WriteString(n.tag)
+		b.WriteString(">")
+	default:
+		for _, c := range n.children {
+			c.render(b)
+		}
+	}
+}
+
+// parseHTML parses markup as rendered by the server into a list of nodes.
+// It handles well-formed markup only: every non-void element is closed
+// explicitly.
+func parseHTML(s string) ([]*node, error) {
+	root := &node{kind: documentNode}
+	cur := root
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				return nil, errors.New("doorstest: unterminated comment")
+			}
+			cur.appendChild(&node{kind: commentNode, data: s[4:end]})
+			s = s[end+3:]
+		case strings.HasPrefix(s, "<!"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return nil, errors.New("doorstest: unterminated doctype")
+			}
+			cur.appendChild(&node{kind: doctypeNode, data: s[2:end]})
+			s = s[end+1:]
+		case strings.HasPrefix(s, "</"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return nil, errors.New("doorstest: unterminated end tag")
+			}
+			tag := strings.ToLower(strings.TrimSpace(s[2:end]))
+			s = s[end+1:]
+			for el := cur; el != root; el = el.parent {
+				if el.tag == tag {
+					cur = el.parent
+					break
+				}
+			}
+		case len(s) > 1 && s[0] == '<' && isLetter(s[1]):
+			el, rest, selfClosing, err := parseTag(s[1:])
+			if err != nil {
+				return nil, err
+			}
+			s = rest
+			cur.appendChild(el)
+			if selfClosing || slices.Contains(voidTags, el.tag) {
+				continue
+			}
+			if slices.Contains(rawTags, el.tag) {
+				end := indexFold(s, "</"+el.tag)
+				if end < 0 {
+					return nil, fmt.Errorf("doorstest: unterminated <%s>", el.tag)
+				}
+				text := s[:end]
+				if el.tag == "textarea" || el.tag == "title" {
+					text = html.UnescapeString(text)
+				}
+				if text != "" {
+					el.appendChild(&node{kind: textNode, data: text})
+				}
+				s = s[end:]
+				continue
+			}
+			cur = el
+		default:
+			end := strings.IndexByte(s[1:], '<')
+			if end < 0 {
+				end = len(s)
+			} else {
+				end += 1
+			}
+			cur.appendChild(&node{kind: textNode, data: html.UnescapeString(s[:end])})
+			s = s[end:]
+		}
+	}
+	children := root.children
+	for _, c := range children {
+		c.parent = nil
+	}
+	return children, nil
+}
+
+func parseTag(s string) (*node, string, bool, error) {
+	i := 0
+	for i < len(s) && !isSpace(s[i]) && s[i] != '>' && s[i] != '/' {
+		i++
+	}
+	el := &node{kind: elementNode, tag: strings.ToLower(s[:i])}
+	s = s[i:]
+	for {
+		s = strings.TrimLeft(s, " \t\n\r\f")
+		if s == "" {
+			return nil, "", false, fmt.Errorf("doorstest: unterminated <%s>", el.tag)
+		}
+		if s[0] == '>' {
+			return el, s[1:], false, nil
+		}
+		if strings.HasPrefix(s, "/>") {
+			return el, s[2:], true, nil
+		}
+		if s[0] == '/' {
+			s = s[1:]
+			continue
+		}
+		i := 0
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && !strings.HasPrefix(s[i:], "/>") {
+			i++
+		}
+		name := strings.ToLower(s[:i])
+		s = strings.TrimLeft(s[i:], " \t\n\r\f")
+		value := ""
+		if strings.HasPrefix(s, "=") {
+			s = strings.TrimLeft(s[1:], " \t\n\r\f")
+			if s != "" && (s[0] == '"' || s[0] == '\'') {
+				end := strings.IndexByte(s[1:], s[0])
+				if end < 0 {
+					return nil, "", false, fmt.Errorf("doorstest: unterminated attribute %s", name)
+				}
+				value = s[1 : end+1]
+				s = s[end+2:]
+			} else {
+				i := 0
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[:i]
+				s = s[i:]
+			}
+		}
+		el.attrs = append(el.attrs, attr{name: name, value: html.UnescapeString(value)})
+	}
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+// indexFold returns the byte offset in s of the first window that equals the
+// ASCII substr ignoring case. Unlike lowering s first, it keeps offsets
+// valid for characters whose lower case has a different byte length.
+func indexFold(s string, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// selector is a parsed CSS selector group.
+type selector [][]compound
+
+type compound struct {
+	// child reports that the compound must match the parent of the next
+	// compound instead of any ancestor.
+	child   bool
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrMatch
+}
+
+type attrMatch struct {
+	name  string
+	value string
+	has   bool
+}
+
+// parseSelector parses a subset of CSS selectors: type, #id, .class,
+// [attr] and [attr=value] compounds, joined by descendant or child
+// combinators, in comma separated groups.
+func parseSelector(s string) (selector, error) {
+	var sel selector
+	for group := range strings.SplitSeq(s, ",") {
+		var chain []compound
+		child := false
+		group = strings.ReplaceAll(group, ">", " > ")
+		for part := range strings.FieldsSeq(group) {
+			if part == ">" {
+				if len(chain) == 0 || child {
+					return nil, fmt.Errorf("doorstest: bad selector %q", s)
+				}
+				child = true
+				continue
+			}
+			c, err := parseCompound(part)
+			if err != nil {
+				return nil, fmt.Errorf("doorstest: bad selector %q: %w", s, err)
+			}
+			c.child = child
+			child = false
+			chain = append(chain, c)
+		}
+		if len(chain) == 0 || child {
+			return nil, fmt.Errorf("doorstest: bad selector %q", s)
+		}
+		sel = append(sel, chain)
+	}
+	return sel, nil
+}
+
+func parseCompound(s string) (compound, error) {
+	var c compound
+	name := func() string {
+		i := 0
+		for i < len(s) && !strings.ContainsRune("#.[", rune(s[i])) {
+			i++
+		}
+		v := s[:i]
+		s = s[i:]
+		return v
+	}
+	c.tag = strings.ToLower(name())
+	if c.tag == "*" {
+		c.tag = ""
+	}
+	for s != "" {
+		switch s[0] {
+		case '#':
+			s = s[1:]
+			c.id = name()
+		case '.':
+			s = s[1:]
+			c.classes = append(c.classes, name())
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return c, errors.New("unterminated attribute")
+			}
+			inner := s[1:end]
+			s = s[end+1:]
+			key, value, has := strings.Cut(inner, "=")
+			value = strings.Trim(value, `"'`)
+			c.attrs = append(c.attrs, attrMatch{name: strings.ToLower(strings.TrimSpace(key)), value: value, has: has})
+		default:
+			return c, fmt.Errorf("unexpected %q", s[0])
+		}
+	}
+	return c, nil
+}
+
+func (c compound) match(n *node) bool {
+	if n.kind != elementNode {
+		return false
+	}
+	if c.tag != "" && c.tag != n.tag {
+		return false
+	}
+	if c.id != "" {
+		if id, _ := n.attr("id"); id != c.id {
+			return false
+		}
+	}
+	if len(c.classes) != 0 {
+		class, _ := n.attr("class")
+		fields := strings.Fields(class)
+		for _, cl := range c.classes {
+			if !slices.Contains(fields, cl) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		v, ok := n.attr(a.name)
+		if !ok || (a.has && v != a.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s selector) match(n *node) bool {
+	for _, chain := range s {
+		if matchChain(chain, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchChain(chain []compound, n *node) bool {
+	last := chain[len(chain)-1]
+	if !last.match(n) {
+		return false
+	}
+	if len(chain) == 1 {
+		return true
+	}
+	for p := n.parent; p != nil && p.kind == elementNode; p = p.parent {
+		if matchChain(chain[:len(chain)-1], p) {
+			return true
+		}
+		if last.child {
+			return false
+		}
+	}
+	return false
+}
+
+func (s selector) find(root *node) *node {
+	var found *node
+	root.walk(func(n *node) bool {
+		if s.match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func (s selector) findAll(root *node) []*node {
+	var found []*node
+	root.walk(func(n *node) bool {
+		if s.match(n) {
+			found = append(found, n)
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doorstest drives Doors apps in tests without a browser.
+//
+// A [Client] serves an app with httptest and loads its pages. Each [Page]
+// keeps an in-memory DOM in sync with the server the way the browser client
+// does: it applies door updates, dynamic attributes, path and title changes,
+// and reports call results back. Tests fire hooks with [Page.Click],
+// [Page.Input], [Page.Submit], and similar methods, which return once the
+// server finished handling the hook and its updates were applied:
+//
+//	client := doorstest.NewClient(app)
+//	defer client.Close()
+//	page, err := client.Open("/")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	if err := page.Click("#save"); err != nil {
+//		t.Fatal(err)
+//	}
+//	if got := page.Text("#status"); got != "Saved" {
+//		t.Fatalf("unexpected status %q", got)
+//	}
+//
+// JavaScript does not run. Calls that need it, such as emitting to a
+// JavaScript handler, fail the same way they do in a browser without the
+// handler. Hook scopes and indication are not simulated.
+package doorstest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the default [Client.Timeout].
+const DefaultTimeout = 5 * time.Second
+
+// Client loads pages of an app served by an httptest server. It keeps
+// cookies between requests, so pages opened by one client share a session.
+type Client struct {
+	// Timeout bounds how long hook methods and [Page.Wait] wait for the page
+	// to settle. Defaults to [DefaultTimeout].
+	// Optional.
+	Timeout time.Duration
+
+	server *httptest.Server
+	http   *http.Client
+	mu     sync.Mutex
+	pages  []*Page
+}
+
+// NewClient starts an httptest server with handler, usually a doors app.
+// Call [Client.Close] when done.
+func NewClient(handler http.Handler) *Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		panic(err)
+	}
+	server := httptest.NewServer(handler)
+	httpClient := server.Client()
+	httpClient.Jar = jar
+	return &Client{
+		server: server,
+		http:   httpClient,
+	}
+}
+
+// URL returns the base URL of the test server.
+func (c *Client) URL() string {
+	return c.server.URL
+}
+
+// Open loads the page at path and connects it to the server. Redirects are
+// followed. Responses that aren't Doors pages, such as a 404 of a static
+// handler, are returned as pages without a connection.
+func (c *Client) Open(path string) (*Page, error) {
+	resp, err := c.http.Get(c.server.URL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newPage(c, resp.StatusCode, resp.Request.URL.RequestURI(), string(body))
+	if err != nil {
+		return nil, fmt.Errorf("doorstest: parse %s: %w", path, err)
+	}
+	c.mu.Lock()
+	c.pages = append(c.pages, p)
+	c.mu.Unlock()
+	p.connect()
+	return p, nil
+}
+
+// Close closes all pages and shuts the server down.
+func (c *Client) Close() {
+	c.mu.Lock()
+	pages := c.pages
+	c.pages = nil
+	c.mu.Unlock()
+	for _, p := range pages {
+		p.Close()
+	}
+	c.server.Close()
+}
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return c.Timeout
+}
@@ -0,0 +1,586 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorstest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/doors-dev/doors"
+	"github.com/doors-dev/gox"
+)
+
+type testForm struct {
+	Name string `form:"name"`
+}
+
+func testElem(tag string, attrs map[string]any, mods []gox.Modify, content ...any) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if err := cur.Init(tag); err != nil {
+			return err
+		}
+		for name, value := range attrs {
+			if err := cur.Set(name, value); err != nil {
+				return err
+			}
+		}
+		if err := cur.Modify(mods...); err != nil {
+			return err
+		}
+		if err := cur.Submit(); err != nil {
+			return err
+		}
+		if err := cur.Many(content...); err != nil {
+			return err
+		}
+		return cur.Close()
+	}
+}
+
+func testInput(attrs map[string]any) gox.Elem {
+	return testVoid("input", attrs)
+}
+
+func testVoid(tag string, attrs map[string]any) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if err := cur.InitVoid(tag); err != nil {
+			return err
+		}
+		for name, value := range attrs {
+			if err := cur.Set(name, value); err != nil {
+				return err
+			}
+		}
+		return cur.Submit()
+	}
+}
+
+func testApp(opts ...doors.With) doors.App {
+	return doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		count := 0
+		counter := &doors.Door{}
+		greeting := &doors.Door{}
+		inc := doors.AClick{
+			On: func(ctx context.Context, r doors.RequestPointer) bool {
+				count += 1
+				counter.Inner(ctx, strconv.Itoa(count))
+				return false
+			},
+		}
+		submit := doors.ASubmit[testForm]{
+			On: func(ctx context.Context, r doors.RequestForm[testForm]) bool {
+				greeting.Inner(ctx, "Hello, "+r.Data().Name)
+				return false
+			},
+		}
+		return testElem("html", nil, nil,
+			testElem("head", nil, nil, testElem("title", nil, nil, "Test")),
+			testElem("body", nil, nil,
+				testElem("p", map[string]any{"id": "count"}, nil, counter),
+				testElem("button", map[string]any{"id": "inc"}, []gox.Modify{inc}, "+"),
+				testElem("p", map[string]any{"id": "greeting"}, nil, greeting),
+				testElem("form", nil, []gox.Modify{submit},
+					testInput(map[string]any{"name": "name", "value": "nobody"}),
+				),
+			),
+		)
+	}, opts...)
+}
+
+func TestClick(t *testing.T) {
+	client := NewClient(testApp())
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Title() != "Test" {
+		t.Fatalf("unexpected title %q", page.Title())
+	}
+	for i := 1; i <= 3; i++ {
+		if err := page.Click("#inc"); err != nil {
+			t.Fatal(err)
+		}
+		if got := page.Text("#count"); got != strconv.Itoa(i) {
+			t.Fatalf("unexpected count %q after %d clicks", got, i)
+		}
+	}
+}
+
+func TestRequestToken(t *testing.T) {
+	client := NewClient(testApp(doors.WithRequestPolicy(doors.RequestPolicy{RequireToken: true})))
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := page.Click("#inc"); err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Text("#count"); got != "1" {
+		t.Fatalf("unexpected count %q", got)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	abuse := make(chan doors.HookAbuse, 2)
+	client := NewClient(testApp(
+		doors.WithConf(doors.Conf{
+			HookRateLimit:        doors.RateLimit{Every: time.Hour, Burst: 2},
+			SessionHookRateLimit: doors.RateLimit{Every: time.Hour, Burst: 3},
+		}),
+		doors.WithHookAbuse(func(ctx context.Context, a doors.HookAbuse) {
+			if doors.SessionId(ctx) == "" {
+				t.Error("expected session context")
+			}
+			abuse <- a
+		}),
+	))
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := page.Click("#inc"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hookErr, ok := page.Click("#inc").(*HookError)
+	if !ok || hookErr.Status != http.StatusTooManyRequests {
+		t.Fatalf("expected hook limit, got %v", hookErr)
+	}
+	if a := <-abuse; a.Scope != doors.RateLimitHook || a.HookID == 0 || a.Rejected != 1 || a.RetryAfter <= 0 {
+		t.Fatalf("unexpected hook abuse %+v", a)
+	}
+	hookErr, ok = page.Submit("form", nil).(*HookError)
+	if !ok || hookErr.Status != http.StatusTooManyRequests {
+		t.Fatalf("expected session limit, got %v", hookErr)
+	}
+	if a := <-abuse; a.Scope != doors.RateLimitSession || a.Rejected != 1 {
+		t.Fatalf("unexpected session abuse %+v", a)
+	}
+	if got := page.Text("#count"); got != "2" {
+		t.Fatalf("unexpected count %q", got)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	client := NewClient(testApp())
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := page.Submit("form", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Text("#greeting"); got != "Hello, nobody" {
+		t.Fatalf("unexpected greeting %q", got)
+	}
+	if err := page.Submit("form", url.Values{"name": {"doors"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Text("#greeting"); got != "Hello, doors" {
+		t.Fatalf("unexpected greeting %q", got)
+	}
+	el, ok := page.Find("input[name=name]")
+	if !ok || el.Attrs["value"] != "doors" {
+		t.Fatalf("unexpected input %v", el)
+	}
+}
+
+func TestClose(t *testing.T) {
+	client := NewClient(testApp())
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := page.Click("#missing"); err == nil {
+		t.Fatal("expected error for missing element")
+	}
+	page.Close()
+	if err := page.Err(); err != ErrClosed {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	recorder := doors.NewTraceRecorder()
+	client := NewClient(testApp(doors.WithTracer(recorder)))
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := page.Click("#inc"); err != nil {
+		t.Fatal(err)
+	}
+	hooks := recorder.Named(doors.SpanHook)
+	if len(hooks) != 1 {
+		t.Fatalf("expected one hook span, got %d", len(hooks))
+	}
+	if v, ok := hooks[0].Attr(doors.TraceInstanceID); !ok || v.String() == "" {
+		t.Fatalf("hook span without instance id: %v", hooks[0].Attrs)
+	}
+	if _, ok := hooks[0].Attr(doors.TraceHookID); !ok {
+		t.Fatalf("hook span without hook id: %v", hooks[0].Attrs)
+	}
+	renders := recorder.Named(doors.SpanDoorRender)
+	if len(renders) != 1 {
+		t.Fatalf("expected one render span, got %d", len(renders))
+	}
+	if v, ok := renders[0].Attr(doors.TracePayloadBytes); !ok || v.Int64() == 0 {
+		t.Fatalf("render span without payload size: %v", renders[0].Attrs)
+	}
+	if renders[0].Err != nil {
+		t.Fatalf("unexpected render error %v", renders[0].Err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(recorder.Named(doors.SpanSyncFlush)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected sync flush spans")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDragAndDrop(t *testing.T) {
+	dropped := make(chan string, 1)
+	client := NewClient(doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		status := &doors.Door{}
+		start := doors.ADragStart{
+			On: func(ctx context.Context, r doors.RequestDrag) bool {
+				status.Inner(ctx, "dragging "+r.Event().TargetData["cardId"])
+				return false
+			},
+		}
+		drop := doors.ADrop{
+			On: func(ctx context.Context, r doors.RequestDrop) bool {
+				e := r.Event()
+				text := "moved " + e.SourceData["cardId"] + " to " + e.TargetData["column"] + " " + e.Text()
+				files := r.Files(-1)
+				for {
+					part, err := files.NextPart()
+					if err != nil {
+						break
+					}
+					content, _ := io.ReadAll(part)
+					text += " " + part.FileName() + "=" + string(content)
+				}
+				status.Inner(ctx, text)
+				dropped <- e.Files[0].Name
+				return false
+			},
+		}
+		return testElem("html", nil, nil,
+			testElem("body", nil, nil,
+				testElem("div", map[string]any{"id": "card", "draggable": "true", "data-card-id": "7"}, []gox.Modify{start}, "Card"),
+				testElem("div", map[string]any{"id": "done", "data-column": "done"}, []gox.Modify{drop}),
+				testElem("p", map[string]any{"id": "status"}, nil, status),
+			),
+		)
+	}))
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := page.Drag("#card", "dragstart"); err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Text("#status"); got != "dragging 7" {
+		t.Fatalf("unexpected status %q", got)
+	}
+	err = page.Drop("#done", map[string]string{"text/plain": "note"}, DropFile{Name: "a.txt", Type: "text/plain", Content: []byte("hello")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Text("#status"); got != "moved 7 to done note a.txt=hello" {
+		t.Fatalf("unexpected status %q", got)
+	}
+	if name := <-dropped; name != "a.txt" {
+		t.Fatalf("unexpected file %q", name)
+	}
+}
+
+func TestVisible(t *testing.T) {
+	var loads atomic.Int64
+	client := NewClient(doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		items := doors.NewSourceEqual([]int{1, 2}, slices.Equal[[]int])
+		more := doors.AVisible{
+			Scope: &doors.ScopeBlocking{},
+			On: func(ctx context.Context, r doors.RequestVisible) bool {
+				if !r.Event().Visible || r.Event().Ratio != 1 {
+					t.Errorf("unexpected event %+v", r.Event())
+				}
+				loads.Add(1)
+				n := 0
+				items.Mutate(ctx, func(items []int) []int {
+					n = len(items) + 2
+					return append(items, n-1, n)
+				})
+				// the last page: stop observing
+				return n >= 6
+			},
+		}
+		list := doors.Each(items, func(i int) int { return i }, func(b doors.Beam[int]) gox.Elem {
+			return testElem("li", nil, nil, strconv.Itoa(b.Get()))
+		})
+		return testElem("html", nil, nil,
+			testElem("body", nil, nil,
+				testElem("ul", nil, nil, list),
+				testElem("div", map[string]any{"id": "more"}, []gox.Modify{more}),
+			),
+		)
+	}))
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the sentinel stays visible, so it keeps loading until the hook is done
+	if err := page.Visible("#more", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(page.FindAll("li")); got != 6 || loads.Load() != 2 {
+		t.Fatalf("expected 6 items after 2 loads, got %d items after %d", got, loads.Load())
+	}
+	if err := page.Visible("#more", true); err != nil {
+		t.Fatal(err)
+	}
+	if loads.Load() != 2 {
+		t.Fatalf("expected no request without a visibility change, got %d loads", loads.Load())
+	}
+	if err := page.Visible("#more", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(page.FindAll("li")); got != 6 || loads.Load() != 2 {
+		t.Fatalf("expected no request on leave, got %d items", got)
+	}
+}
+
+type otherPath struct {
+	Other bool `path:"/other"`
+}
+
+// actionsApp has one button per client action it makes the server call.
+func actionsApp() doors.App {
+	return doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		shared := doors.NewAShared("data-state", "on")
+		box := &doors.Door{}
+		morph := &doors.Door{Morph: true}
+		head := &doors.Door{}
+		result := &doors.Door{}
+		box.Outer(ctx, testElem("div", map[string]any{"id": "box"}, nil, "box"))
+		morph.Inner(ctx, testElem("i", nil, nil, "before"))
+		click := func(f func(ctx context.Context)) []gox.Modify {
+			return []gox.Modify{doors.AClick{
+				On: func(ctx context.Context, r doors.RequestPointer) bool {
+					f(ctx)
+					return false
+				},
+			}}
+		}
+		call := func(action doors.Action) func(ctx context.Context) {
+			return func(ctx context.Context) {
+				ctx = doors.DetachedContext(ctx)
+				go func() {
+					res := <-doors.XCall[json.RawMessage](ctx, action)
+					text := "ok"
+					if res.Err != nil {
+						text = "error"
+					}
+					result.Inner(ctx, text)
+				}()
+			}
+		}
+		button := func(id string, f func(ctx context.Context)) gox.Elem {
+			return testElem("button", map[string]any{"id": id}, click(f), id)
+		}
+		return testElem("html", nil, nil,
+			testElem("head", nil, nil, testElem("title", nil, nil, "Actions")),
+			testElem("body", nil, nil,
+				testElem("p", map[string]any{"id": "shared"}, []gox.Modify{shared}, "shared"),
+				button("disable", shared.Disable),
+				button("enable", shared.Enable),
+				button("update", func(ctx context.Context) {
+					shared.Update(ctx, "two")
+				}),
+				box,
+				button("replace", func(ctx context.Context) {
+					box.Outer(ctx, testElem("section", map[string]any{"id": "box"}, nil, "replaced"))
+				}),
+				testElem("div", map[string]any{"id": "morph"}, nil, morph),
+				button("morph-update", func(ctx context.Context) {
+					morph.Inner(ctx, testElem("b", nil, nil, "after"))
+				}),
+				head,
+				button("head", func(ctx context.Context) {
+					head.Inner(ctx, testElem("div", nil, nil,
+						testElem("title", nil, nil, "Second"),
+						testVoid("meta", map[string]any{"name": "description", "content": "second"}),
+					))
+				}),
+				button("unhead", func(ctx context.Context) {
+					head.Unmount(ctx)
+				}),
+				testElem("a", map[string]any{"id": "link"}, []gox.Modify{doors.ALink{Model: otherPath{Other: true}}}, "link"),
+				button("reload", call(doors.ActionLocationReload{})),
+				button("replace-location", call(doors.ActionLocationReplace{Model: otherPath{Other: true}})),
+				button("assign", call(doors.ActionLocationRawAssign{URL: "/elsewhere"})),
+				button("scroll", call(doors.ActionScroll{Selector: "#box"})),
+				button("scroll-missing", call(doors.ActionScroll{Selector: "#missing"})),
+				button("emit", call(doors.ActionEmit{Name: "missing"})),
+				testElem("p", map[string]any{"id": "result"}, nil, result),
+			),
+		)
+	})
+}
+
+func TestActions(t *testing.T) {
+	client := NewClient(actionsApp())
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := func(button string, cond func(p *Page) bool) {
+		t.Helper()
+		if err := page.Click("#" + button); err != nil {
+			t.Fatal(err)
+		}
+		if err := page.Wait(cond); err != nil {
+			t.Fatalf("after #%s: %v\n%s", button, err, page.HTML())
+		}
+	}
+	state := func(want string) func(p *Page) bool {
+		return func(p *Page) bool {
+			el, _ := p.Find("#shared")
+			got, ok := el.Attrs["data-state"]
+			return ok == (want != "") && got == want
+		}
+	}
+	if !state("on")(page) {
+		t.Fatal("expected shared attribute")
+	}
+	// dyna_remove and dyna_set
+	step("disable", state(""))
+	step("enable", state("on"))
+	step("update", state("two"))
+	// door_replace
+	step("replace", func(p *Page) bool {
+		el, ok := p.Find("#box")
+		return ok && el.Tag == "section" && el.Text == "replaced"
+	})
+	// door_update with morph
+	step("morph-update", func(p *Page) bool {
+		_, before := p.Find("#morph i")
+		return !before && p.Text("#morph b") == "after"
+	})
+	// update_title, update_meta, then remove_meta
+	description := func(p *Page) (string, bool) {
+		el, ok := p.Find("head meta[name=description]")
+		return el.Attrs["content"], ok
+	}
+	step("head", func(p *Page) bool {
+		content, _ := description(p)
+		return p.Title() == "Second" && content == "second"
+	})
+	step("unhead", func(p *Page) bool {
+		_, ok := description(p)
+		return p.Title() == "Actions" && !ok
+	})
+	// location_reload, set_path, location_replace and location_assign
+	step("reload", func(p *Page) bool {
+		return p.Location() == "/"
+	})
+	step("link", func(p *Page) bool {
+		return p.URL() == "/other" && p.Location() == "/"
+	})
+	step("replace-location", func(p *Page) bool {
+		return p.Location() == "/other"
+	})
+	step("assign", func(p *Page) bool {
+		return p.Location() == "/elsewhere"
+	})
+	// scroll, emit
+	result := func(want string) func(p *Page) bool {
+		return func(p *Page) bool {
+			return p.Text("#result") == want
+		}
+	}
+	step("scroll", result("ok"))
+	step("scroll-missing", result("error"))
+	step("scroll", result("ok"))
+	step("emit", result("error"))
+}
+
+func TestTracerHookError(t *testing.T) {
+	recorder := doors.NewTraceRecorder()
+	app := doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		fail := doors.AClick{
+			On: func(ctx context.Context, r doors.RequestPointer) bool {
+				panic("hook failed")
+			},
+		}
+		return testElem("html", nil, nil,
+			testElem("body", nil, nil,
+				testElem("button", map[string]any{"id": "fail"}, []gox.Modify{fail}, "fail"),
+			),
+		)
+	}, doors.WithTracer(recorder))
+	client := NewClient(app)
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := page.Click("#fail").(*HookError); !ok {
+		t.Fatal("expected hook error")
+	}
+	hooks := recorder.Named(doors.SpanHook)
+	if len(hooks) != 1 || hooks[0].Err == nil {
+		t.Fatalf("expected hook span with the handler error, got %+v", hooks)
+	}
+}
+
+func TestParseRawText(t *testing.T) {
+	nodes, err := parseHTML(`<title>İstanbul Ⱥ</TITLE><script>let s = "İİİ Ⱥ"</script><p>after</p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(nodes))
+	}
+	if got := nodes[0].text(); got != "İstanbul Ⱥ" {
+		t.Fatalf("unexpected title %q", got)
+	}
+	if got := nodes[1].text(); got != `let s = "İİİ Ⱥ"` {
+		t.Fatalf("unexpected script %q", got)
+	}
+	if nodes[2].tag != "p" || nodes[2].text() != "after" {
+		t.Fatalf("unexpected sibling %q", nodes[2].outerHTML())
+	}
+}
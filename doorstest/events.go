@@ -0,0 +1,779 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorstest
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HookError is returned when the server answers a hook request with an
+// unexpected status, for example 404 once the hook is done.
+type HookError struct {
+	Status int
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("doorstest: hook status %d", e.Status)
+}
+
+// Click fires a click on the first element matching the CSS selector. Click
+// hooks of the element and its ancestors run, and links made with ALink
+// navigate.
+func (p *Page) Click(sel string) error {
+	return p.Pointer(sel, "click")
+}
+
+// Pointer fires a pointer event of the given type, such as "pointerdown",
+// on the first element matching the CSS selector.
+func (p *Page) Pointer(sel string, event string) error {
+	return p.dispatch(sel, domEvent{kind: event, bubbles: true}, nil)
+}
+
+// Input sets the value of the first form control matching the CSS selector
+// and fires an input event.
+func (p *Page) Input(sel string, value string) error {
+	return p.dispatch(sel, domEvent{kind: "input", bubbles: true, data: value}, func(n *node) {
+		setValue(n, value)
+	})
+}
+
+// Change sets the value of the first form control matching the CSS selector
+// and fires input and change events.
+func (p *Page) Change(sel string, value string) error {
+	return p.edit(sel, func(n *node) {
+		setValue(n, value)
+	})
+}
+
+// Check checks or unchecks the first checkbox or radio button matching the
+// CSS selector and fires input and change events.
+func (p *Page) Check(sel string, checked bool) error {
+	return p.edit(sel, func(n *node) {
+		setChecked(p.form(n), n, checked)
+	})
+}
+
+// Select selects the options with the given values in the first select
+// matching the CSS selector and fires input and change events.
+func (p *Page) Select(sel string, values ...string) error {
+	return p.edit(sel, func(n *node) {
+		selectOptions(n, values)
+	})
+}
+
+// KeyDown fires a keydown event with key, such as "Enter" or "a", on the
+// first element matching the CSS selector.
+func (p *Page) KeyDown(sel string, key string) error {
+	return p.dispatch(sel, domEvent{kind: "keydown", bubbles: true, key: key}, nil)
+}
+
+// KeyUp fires a keyup event with key on the first element matching the CSS
+// selector.
+func (p *Page) KeyUp(sel string, key string) error {
+	return p.dispatch(sel, domEvent{kind: "keyup", bubbles: true, key: key}, nil)
+}
+
+// Focus fires focus and focusin events on the first element matching the
+// CSS selector.
+func (p *Page) Focus(sel string) error {
+	if err := p.dispatch(sel, domEvent{kind: "focus"}, nil); err != nil {
+		return err
+	}
+	return p.dispatch(sel, domEvent{kind: "focusin", bubbles: true}, nil)
+}
+
+// Blur fires blur and focusout events on the first element matching the CSS
+// selector.
+func (p *Page) Blur(sel string) error {
+	if err := p.dispatch(sel, domEvent{kind: "blur"}, nil); err != nil {
+		return err
+	}
+	return p.dispatch(sel, domEvent{kind: "focusout", bubbles: true}, nil)
+}
+
+// Submit fills the first form matching the CSS selector with values and
+// submits it. Controls named in values are set the way a user would set
+// them; values without a control are submitted as extra fields. The form
+// data is built from the controls like a browser does.
+func (p *Page) Submit(sel string, values url.Values) error {
+	return p.dispatch(sel, domEvent{kind: "submit", bubbles: true}, func(form *node) {
+		fillForm(form, values)
+		p.extra = extraFields(form, values)
+	})
+}
+
+// DropFile is a file dropped with [Page.Drop].
+type DropFile struct {
+	Name    string
+	Type    string
+	Content []byte
+}
+
+// Drag fires a drag event of the given type, such as "dragstart",
+// "dragover", or "dragend", on the first element matching the CSS selector.
+// After dragstart, the element is the drag source whose data later drag
+// events report, until dragend.
+func (p *Page) Drag(sel string, event string) error {
+	return p.dispatch(sel, domEvent{kind: event, bubbles: true}, nil)
+}
+
+// Drop fires a drop event on the first element matching the CSS selector,
+// carrying data by format, such as "text/plain", and files.
+func (p *Page) Drop(sel string, data map[string]string, files ...DropFile) error {
+	return p.dispatch(sel, domEvent{kind: "drop", bubbles: true, transfer: data, files: files}, nil)
+}
+
+// maxVisibleRepeats bounds how often [Page.Visible] fires again for an
+// element that stays visible, so a hook that never stops fails the test
+// instead of hanging it.
+const maxVisibleRepeats = 100
+
+// Visible moves the first element matching the CSS selector into the
+// viewport, or out of it when visible is false, and fires the event of
+// [doors.AVisible] like the browser client does: only when the visibility
+// changes. While the element stays visible, the event fires again after
+// each completed hook, until a hook fails or is gone, or the element is
+// removed.
+func (p *Page) Visible(sel string, visible bool) error {
+	s := mustSelector(sel)
+	p.mu.Lock()
+	target, err := p.find(s, sel)
+	if err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	if p.visible[target] == visible {
+		p.mu.Unlock()
+		return nil
+	}
+	if visible {
+		p.visible[target] = true
+	} else {
+		delete(p.visible, target)
+	}
+	ev := domEvent{kind: "d0-visible", hidden: !visible}
+	fired, err := p.fireAt(target, ev)
+	if err != nil || !visible {
+		return err
+	}
+	for range maxVisibleRepeats {
+		if !fired {
+			return nil
+		}
+		p.mu.Lock()
+		if !p.visible[target] || !target.in(p.doc) {
+			p.mu.Unlock()
+			return nil
+		}
+		fired, err = p.fireAt(target, ev)
+		var hookErr *HookError
+		if errors.As(err, &hookErr) && hookErr.Status == http.StatusNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("doorstest: %q stays visible after %d hooks", sel, maxVisibleRepeats)
+}
+
+func (p *Page) edit(sel string, prepare func(*node)) error {
+	if err := p.dispatch(sel, domEvent{kind: "input", bubbles: true}, prepare); err != nil {
+		return err
+	}
+	return p.dispatch(sel, domEvent{kind: "change", bubbles: true}, nil)
+}
+
+type domEvent struct {
+	kind     string
+	bubbles  bool
+	key      string
+	data     string
+	transfer map[string]string
+	files    []DropFile
+	hidden   bool
+}
+
+// matches reports whether the key of e matches m. Modifier keys are never
+// pressed.
+func (e domEvent) matches(m keyMatch) bool {
+	if m.Key != "" && m.Key != e.key {
+		return false
+	}
+	return m.Ctrl != modOn && m.Shift != modOn && m.Alt != modOn && m.Meta != modOn
+}
+
+const modOn = 1
+
+type keyMatch struct {
+	Key   string `json:"k"`
+	Ctrl  uint8  `json:"c"`
+	Shift uint8  `json:"s"`
+	Alt   uint8  `json:"a"`
+	Meta  uint8  `json:"m"`
+}
+
+type captureOpt struct {
+	PreventDefault  bool       `json:"pd"`
+	StopPropagation bool       `json:"sp"`
+	ExactTarget     bool       `json:"et"`
+	Keys            []keyMatch `json:"ks"`
+	HistoryReplace  bool       `json:"hr"`
+	ExcludeValue    bool       `json:"ev"`
+	Leave           bool       `json:"lv"`
+	Once            bool       `json:"oc"`
+}
+
+type capture struct {
+	event string
+	name  string
+	opt   captureOpt
+	hook  uint64
+}
+
+type hookRequest struct {
+	hook        uint64
+	contentType string
+	body        []byte
+}
+
+// captures decodes the event captures rendered on an element.
+func captures(n *node) []capture {
+	value, ok := n.attr("data-d0c")
+	if !ok {
+		return nil
+	}
+	var list [][4]json.RawMessage
+	if json.Unmarshal([]byte(value), &list) != nil {
+		return nil
+	}
+	var result []capture
+	for _, entry := range list {
+		var c capture
+		var hook []json.RawMessage
+		if json.Unmarshal(entry[0], &c.event) != nil || json.Unmarshal(entry[1], &c.name) != nil {
+			continue
+		}
+		json.Unmarshal(entry[2], &c.opt)
+		if json.Unmarshal(entry[3], &hook) != nil || len(hook) == 0 || json.Unmarshal(hook[0], &c.hook) != nil {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// dispatch fires event at the first element matching sel and runs the hooks
+// it reaches, one after another, until the page settles.
+func (p *Page) dispatch(sel string, ev domEvent, prepare func(*node)) error {
+	s := mustSelector(sel)
+	p.mu.Lock()
+	target, err := p.find(s, sel)
+	if err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	if prepare != nil {
+		prepare(target)
+		p.notify()
+	}
+	_, err = p.fireAt(target, ev)
+	return err
+}
+
+// find returns the event target matching s. It runs with p.mu held.
+func (p *Page) find(s selector, sel string) (*node, error) {
+	if p.id == "" {
+		return nil, ErrNotConnected
+	}
+	target := s.find(p.doc)
+	if target == nil {
+		return nil, fmt.Errorf("doorstest: no element matches %q", sel)
+	}
+	return target, nil
+}
+
+// fireAt fires event at target and runs the hooks it reaches. It is called
+// with p.mu held and releases it. It reports whether any hook was fired.
+func (p *Page) fireAt(target *node, ev domEvent) (bool, error) {
+	if ev.kind == "dragstart" {
+		p.drag = target
+	}
+	var requests []hookRequest
+	for el := target; el != nil && el.kind == elementNode; el = el.parent {
+		stop := false
+		for _, c := range captures(el) {
+			if c.event != ev.kind || (c.opt.ExactTarget && el != target) {
+				continue
+			}
+			if len(c.opt.Keys) > 0 && !slices.ContainsFunc(c.opt.Keys, ev.matches) {
+				continue
+			}
+			r, ok := p.request(c, ev, target, el)
+			if !ok {
+				continue
+			}
+			requests = append(requests, r)
+			stop = stop || c.opt.StopPropagation
+		}
+		if stop || !ev.bubbles {
+			break
+		}
+	}
+	if ev.kind == "dragend" {
+		p.drag = nil
+	}
+	p.mu.Unlock()
+	for _, r := range requests {
+		if err := p.fire(r); err != nil {
+			return true, err
+		}
+	}
+	return len(requests) != 0, nil
+}
+
+// request builds the hook request of a capture like the browser client
+// does. It runs with p.mu held.
+func (p *Page) request(c capture, ev domEvent, target *node, current *node) (hookRequest, bool) {
+	r := hookRequest{hook: c.hook}
+	var body map[string]any
+	switch c.name {
+	case "pointer":
+		body = map[string]any{
+			"type":        ev.kind,
+			"pointerId":   1,
+			"pointerType": "mouse",
+			"isPrimary":   true,
+		}
+	case "keyboard":
+		body = map[string]any{
+			"type": ev.kind,
+			"key":  ev.key,
+		}
+	case "focus", "focus_io":
+		body = map[string]any{
+			"type": ev.kind,
+		}
+	case "input":
+		body = map[string]any{
+			"type": ev.kind,
+			"data": ev.data,
+		}
+		if !c.opt.ExcludeValue {
+			inputValues(target, body)
+		}
+	case "change":
+		body = map[string]any{
+			"type": ev.kind,
+		}
+		inputValues(target, body)
+	case "drag":
+		body = p.dragValues(ev, current)
+	case "drop":
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		event, _ := json.Marshal(p.dragValues(ev, current))
+		w.WriteField("event", string(event))
+		for _, file := range ev.files {
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, file.Name))
+			h.Set("Content-Type", cmp.Or(file.Type, "application/octet-stream"))
+			part, _ := w.CreatePart(h)
+			part.Write(file.Content)
+		}
+		w.Close()
+		r.contentType = w.FormDataContentType()
+		r.body = buf.Bytes()
+		return r, true
+	case "visible":
+		if ev.hidden && !c.opt.Leave || p.observed[current] {
+			return r, false
+		}
+		if c.opt.Once && !ev.hidden {
+			p.observed[current] = true
+		}
+		ratio := 1.0
+		if ev.hidden {
+			ratio = 0
+		}
+		body = map[string]any{
+			"visible": !ev.hidden,
+			"ratio":   ratio,
+		}
+	case "link":
+		href, ok := current.attr("href")
+		if !ok {
+			return r, false
+		}
+		base, _ := url.Parse(p.url)
+		if ref, err := base.Parse(href); err == nil {
+			p.url = ref.RequestURI()
+		}
+		return r, true
+	case "submit":
+		if current.tag != "form" {
+			return r, false
+		}
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for _, field := range append(formData(current), p.extra...) {
+			w.WriteField(field[0], field[1])
+		}
+		p.extra = nil
+		w.Close()
+		r.contentType = w.FormDataContentType()
+		r.body = buf.Bytes()
+		return r, true
+	default:
+		return r, false
+	}
+	body["timestamp"] = time.Now()
+	r.contentType = "application/json;charset=UTF-8"
+	r.body, _ = json.Marshal(body)
+	return r, true
+}
+
+// fire sends a hook request and waits until the server reported the hook
+// done, then applies the actions of the response.
+func (p *Page) fire(r hookRequest) error {
+	p.mu.Lock()
+	p.track += 1
+	track := p.track
+	p.mu.Unlock()
+	url := fmt.Sprintf("%s%s/h/%s/%d?t=%d", p.client.server.URL, p.prefix, p.id, r.hook, track)
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, url, bytes.NewReader(r.body))
+	if err != nil {
+		return err
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	p.setToken(req)
+	resp, err := p.client.http.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusGone {
+		p.fail(ErrGone)
+		return ErrGone
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &HookError{Status: resp.StatusCode}
+	}
+	err = p.wait(func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.reported[track]
+	})
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.reported, track)
+	defer p.notify()
+	after := resp.Header.Get("D0-After")
+	if after == "" {
+		return nil
+	}
+	var actions [][]json.RawMessage
+	if err := json.Unmarshal([]byte(after), &actions); err != nil {
+		return err
+	}
+	for _, a := range actions {
+		if err := p.callEncoded(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dragValues builds the drag event payload like the browser client does.
+// It runs with p.mu held.
+func (p *Page) dragValues(ev domEvent, current *node) map[string]any {
+	types := slices.Sorted(maps.Keys(ev.transfer))
+	files := make([]map[string]any, len(ev.files))
+	for i, file := range ev.files {
+		files[i] = map[string]any{
+			"name": file.Name,
+			"size": len(file.Content),
+			"type": file.Type,
+		}
+	}
+	if len(files) != 0 {
+		types = append(types, "Files")
+	}
+	data := ev.transfer
+	if data == nil {
+		data = map[string]string{}
+	}
+	var source map[string]string
+	if p.drag != nil {
+		source = dataset(p.drag)
+	}
+	return map[string]any{
+		"type":       ev.kind,
+		"types":      types,
+		"data":       data,
+		"files":      files,
+		"sourceData": source,
+		"targetData": dataset(current),
+		"timestamp":  time.Now(),
+	}
+}
+
+// dataset returns the data-* attributes of n keyed like the JavaScript
+// dataset: "data-card-id" becomes "cardId". Doors attributes are skipped.
+func dataset(n *node) map[string]string {
+	data := map[string]string{}
+	for _, a := range n.attrs {
+		name, ok := strings.CutPrefix(a.name, "data-")
+		if !ok || strings.HasPrefix(name, "d0") {
+			continue
+		}
+		parts := strings.Split(name, "-")
+		for i := 1; i < len(parts); i++ {
+			if parts[i] != "" {
+				parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+			}
+		}
+		data[strings.Join(parts, "")] = a.value
+	}
+	return data
+}
+
+// form returns the form of a control, or the document if it has none.
+func (p *Page) form(n *node) *node {
+	if form := n.closest(func(n *node) bool { return n.tag == "form" }); form != nil {
+		return form
+	}
+	return p.doc
+}
+
+func inputType(n *node) string {
+	t, _ := n.attr("type")
+	if t == "" {
+		return "text"
+	}
+	return t
+}
+
+func hasAttr(n *node, name string) bool {
+	_, ok := n.attr(name)
+	return ok
+}
+
+// value returns the current value of a form control.
+func value(n *node) string {
+	switch n.tag {
+	case "textarea":
+		return n.text()
+	case "select":
+		selected := selectedOptions(n)
+		if len(selected) == 0 {
+			return ""
+		}
+		return selected[0]
+	}
+	v, ok := n.attr("value")
+	if !ok && (inputType(n) == "checkbox" || inputType(n) == "radio") {
+		return "on"
+	}
+	return v
+}
+
+func setValue(n *node, value string) {
+	switch n.tag {
+	case "textarea":
+		n.setText(value)
+	case "select":
+		selectOptions(n, []string{value})
+	default:
+		n.setAttr("value", value)
+	}
+}
+
+func setChecked(form *node, n *node, checked bool) {
+	if checked && inputType(n) == "radio" {
+		name, _ := n.attr("name")
+		form.walk(func(el *node) bool {
+			if other, _ := el.attr("name"); el.tag == "input" && inputType(el) == "radio" && other == name {
+				el.removeAttr("checked")
+			}
+			return true
+		})
+	}
+	if checked {
+		n.setAttr("checked", "")
+	} else {
+		n.removeAttr("checked")
+	}
+}
+
+func options(n *node) []*node {
+	var list []*node
+	n.walk(func(el *node) bool {
+		if el.tag == "option" {
+			list = append(list, el)
+		}
+		return true
+	})
+	return list
+}
+
+func optionValue(n *node) string {
+	if v, ok := n.attr("value"); ok {
+		return v
+	}
+	return n.text()
+}
+
+func selectOptions(n *node, values []string) {
+	for _, o := range options(n) {
+		if slices.Contains(values, optionValue(o)) {
+			o.setAttr("selected", "")
+		} else {
+			o.removeAttr("selected")
+		}
+	}
+}
+
+func selectedOptions(n *node) []string {
+	list := options(n)
+	var selected []string
+	for _, o := range list {
+		if hasAttr(o, "selected") {
+			selected = append(selected, optionValue(o))
+		}
+	}
+	if len(selected) == 0 && len(list) > 0 && !hasAttr(n, "multiple") {
+		selected = append(selected, optionValue(list[0]))
+	}
+	return selected
+}
+
+// inputValues adds the fields the browser client reports for input and
+// change events of a control.
+func inputValues(n *node, body map[string]any) {
+	v := value(n)
+	name, _ := n.attr("name")
+	body["name"] = name
+	body["value"] = v
+	body["number"] = nil
+	body["date"] = nil
+	body["selected"] = []string{}
+	body["checked"] = n.tag == "input" && hasAttr(n, "checked")
+	switch {
+	case n.tag == "select":
+		body["selected"] = selectedOptions(n)
+	case n.tag == "input" && (inputType(n) == "number" || inputType(n) == "range"):
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			body["number"] = f
+		}
+	case n.tag == "input" && inputType(n) == "date":
+		if d, err := time.Parse(time.DateOnly, v); err == nil {
+			body["number"] = d.UnixMilli()
+			body["date"] = d
+		}
+	}
+}
+
+// fillForm sets the controls of form to values.
+func fillForm(form *node, values url.Values) {
+	for name, list := range values {
+		form.walk(func(el *node) bool {
+			if other, _ := el.attr("name"); other != name {
+				return true
+			}
+			switch {
+			case el.tag == "input" && (inputType(el) == "checkbox" || inputType(el) == "radio"):
+				setChecked(form, el, slices.Contains(list, value(el)))
+			case el.tag == "select":
+				selectOptions(el, list)
+			case el.tag == "input" || el.tag == "textarea":
+				if len(list) > 0 {
+					setValue(el, list[0])
+					list = list[1:]
+				}
+			}
+			return true
+		})
+	}
+}
+
+// extraFields returns the values that have no control in form.
+func extraFields(form *node, values url.Values) [][2]string {
+	var fields [][2]string
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		found := form.find(func(el *node) bool {
+			other, _ := el.attr("name")
+			return other == name && (el.tag == "input" || el.tag == "select" || el.tag == "textarea")
+		})
+		if found != nil {
+			continue
+		}
+		for _, v := range values[name] {
+			fields = append(fields, [2]string{name, v})
+		}
+	}
+	return fields
+}
+
+// formData returns the fields a browser submits for form, in order.
+func formData(form *node) [][2]string {
+	var fields [][2]string
+	form.walk(func(el *node) bool {
+		name, ok := el.attr("name")
+		if !ok || name == "" || hasAttr(el, "disabled") {
+			return true
+		}
+		switch el.tag {
+		case "select":
+			for _, v := range selectedOptions(el) {
+				fields = append(fields, [2]string{name, v})
+			}
+		case "textarea":
+			fields = append(fields, [2]string{name, el.text()})
+		case "input":
+			switch inputType(el) {
+			case "submit", "button", "reset", "image", "file":
+			case "checkbox", "radio":
+				if hasAttr(el, "checked") {
+					fields = append(fields, [2]string{name, value(el)})
+				}
+			default:
+				fields = append(fields, [2]string{name, value(el)})
+			}
+		}
+		return true
+	})
+	return fields
+}
@@ -0,0 +1,284 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorstest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTimeout is returned when the page doesn't settle within
+	// [Client.Timeout].
+	ErrTimeout = errors.New("doorstest: timeout")
+	// ErrGone is returned once the server ended the page instance.
+	ErrGone = errors.New("doorstest: page gone")
+	// ErrClosed is returned once the page was closed by the test.
+	ErrClosed = errors.New("doorstest: page closed")
+	// ErrNotConnected is returned by hook methods of pages that were served
+	// without the Doors client, such as error pages of other handlers.
+	ErrNotConnected = errors.New("doorstest: page not connected")
+)
+
+// Element is a snapshot of an element of the page.
+type Element struct {
+	// Tag is the lowercase tag name.
+	Tag string
+	// Attrs holds the attributes as rendered.
+	Attrs map[string]string
+	// Text is the text content of the element and its descendants.
+	Text string
+	// HTML is the outer HTML of the element.
+	HTML string
+}
+
+// Page is a loaded page connected to the server. Its methods are safe for
+// concurrent use.
+type Page struct {
+	client *Client
+	status int
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	id     string
+	prefix string
+	boot   string
+	token  string
+
+	mu       sync.Mutex
+	doc      *node
+	url      string
+	location string
+	changed  chan struct{}
+	err      error
+	cursor   uint64
+	pending  []*pkg
+	results  map[uint64]result
+	track    uint64
+	reported map[uint64]bool
+	extra    [][2]string
+	drag     *node
+	visible  map[*node]bool
+	observed map[*node]bool
+	report   uint64
+	ts       int64
+}
+
+func newPage(c *Client, status int, url string, body string) (*Page, error) {
+	nodes, err := parseHTML(body)
+	if err != nil {
+		return nil, err
+	}
+	doc := &node{kind: documentNode}
+	doc.setChildren(nodes)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Page{
+		client:   c,
+		status:   status,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		doc:      doc,
+		url:      url,
+		changed:  make(chan struct{}),
+		cursor:   1,
+		results:  make(map[uint64]result),
+		reported: make(map[uint64]bool),
+		visible:  make(map[*node]bool),
+		observed: make(map[*node]bool),
+	}
+	script := doc.find(func(n *node) bool {
+		_, ok := n.attr("data-prefix")
+		return n.tag == "script" && ok
+	})
+	if script != nil {
+		p.id, _ = script.attr("id")
+		p.prefix, _ = script.attr("data-prefix")
+		p.token, _ = script.attr("data-token")
+		b := make([]byte, 8)
+		rand.Read(b)
+		p.boot = hex.EncodeToString(b)
+	}
+	return p, nil
+}
+
+// Status returns the status code of the page response.
+func (p *Page) Status() int {
+	return p.status
+}
+
+// URL returns the path and query of the page. It follows path changes made
+// by the server and by links.
+func (p *Page) URL() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.url
+}
+
+// Location returns the URL the server sent the page to with a reload or a
+// location change, or "" if it didn't. Such navigations load a new page in
+// a browser; open the URL with [Client.Open] to follow them.
+func (p *Page) Location() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.location
+}
+
+// Title returns the text of the document title.
+func (p *Page) Title() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	title := p.doc.find(func(n *node) bool {
+		return n.tag == "title"
+	})
+	if title == nil {
+		return ""
+	}
+	return title.text()
+}
+
+// HTML returns the current markup of the whole document.
+func (p *Page) HTML() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.doc.innerHTML()
+}
+
+// Find returns the first element matching the CSS selector. Tag, id, class,
+// and attribute selectors are supported, combined with descendant and child
+// combinators and comma lists. It panics if the selector is invalid.
+func (p *Page) Find(sel string) (Element, bool) {
+	s := mustSelector(sel)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := s.find(p.doc)
+	if n == nil {
+		return Element{}, false
+	}
+	return snapshot(n), true
+}
+
+// FindAll returns all elements matching the CSS selector in document order.
+// See [Page.Find] for the supported syntax.
+func (p *Page) FindAll(sel string) []Element {
+	s := mustSelector(sel)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	nodes := s.findAll(p.doc)
+	elements := make([]Element, len(nodes))
+	for i, n := range nodes {
+		elements[i] = snapshot(n)
+	}
+	return elements
+}
+
+// Text returns the trimmed text content of the first element matching the
+// CSS selector, or "" if none does.
+func (p *Page) Text(sel string) string {
+	el, ok := p.Find(sel)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(el.Text)
+}
+
+// Wait blocks until cond reports true. It is checked right away and after
+// every update of the page. It returns [ErrTimeout] after [Client.Timeout],
+// or the error that ended the page.
+func (p *Page) Wait(cond func(p *Page) bool) error {
+	return p.wait(func() bool {
+		return cond(p)
+	})
+}
+
+// Err returns the error that ended the connection of the page, or nil
+// while it is alive.
+func (p *Page) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Close disconnects the page.
+func (p *Page) Close() {
+	p.cancel()
+	<-p.done
+	p.fail(ErrClosed)
+}
+
+func (p *Page) wait(cond func() bool) error {
+	timer := time.NewTimer(p.client.timeout())
+	defer timer.Stop()
+	for {
+		p.mu.Lock()
+		changed := p.changed
+		err := p.err
+		p.mu.Unlock()
+		if cond() {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return ErrTimeout
+		}
+	}
+}
+
+// notify wakes up waiters. Must be called with p.mu held.
+func (p *Page) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+func (p *Page) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return
+	}
+	p.err = err
+	p.notify()
+}
+
+func mustSelector(sel string) selector {
+	s, err := parseSelector(sel)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func snapshot(n *node) Element {
+	attrs := make(map[string]string, len(n.attrs))
+	for _, a := range n.attrs {
+		attrs[a.name] = a.value
+	}
+	return Element{
+		Tag:   n.tag,
+		Attrs: attrs,
+		Text:  n.text(),
+		HTML:  n.outerHTML(),
+	}
+}
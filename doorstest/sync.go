@@ -0,0 +1,330 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doorstest
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	signalSync    byte = 0x00
+	signalAction  byte = 0x01
+	signalRoll    byte = 0x02
+	signalSuspend byte = 0x03
+	signalKill    byte = 0x04
+	terminator    byte = 0xFF
+)
+
+const (
+	payloadNone   byte = 0x00
+	payloadBinary byte = 0x01
+	payloadJSON   byte = 0x02
+	payloadText   byte = 0x03
+	payloadGzip   byte = 0x10
+	payloadBrotli byte = 0x20
+	payloadZstd   byte = 0x30
+	payloadCoding byte = 0xF0
+)
+
+// decoders lists the payload encodings the test client can decode besides
+// gzip.
+const decoders = "br,zstd"
+
+const retryDelay = 50 * time.Millisecond
+
+// pkg is a call received from the server, or a filler covering sequence
+// numbers without one.
+type pkg struct {
+	start   uint64
+	end     uint64
+	filler  bool
+	name    string
+	args    []json.RawMessage
+	payload []byte
+}
+
+type result struct {
+	output any
+	err    error
+}
+
+func (r result) MarshalJSON() ([]byte, error) {
+	if r.err != nil {
+		return json.Marshal([]any{nil, r.err.Error()})
+	}
+	return json.Marshal([]any{r.output, nil})
+}
+
+func (p *Page) connect() {
+	if p.id == "" {
+		p.mu.Lock()
+		p.err = ErrNotConnected
+		p.mu.Unlock()
+		close(p.done)
+		return
+	}
+	go p.run()
+}
+
+func (p *Page) run() {
+	defer close(p.done)
+	for t := 1; ; t++ {
+		err := p.receive(t)
+		if p.ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrGone) {
+			p.fail(err)
+			return
+		}
+		if err == nil {
+			continue
+		}
+		select {
+		case <-time.After(retryDelay):
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// setToken adds the instance token the page was rendered with.
+func (p *Page) setToken(req *http.Request) {
+	if p.token != "" {
+		req.Header.Set(common.TokenHeader, p.token)
+	}
+}
+
+// receive reads one stream of the sync connection. It returns nil when the
+// server ended the stream to roll over to the next one.
+func (p *Page) receive(t int) error {
+	url := fmt.Sprintf("%s/s/%s?t=%d&b=%s&e=%s", p.prefix, p.id, t, p.boot, decoders)
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, p.client.server.URL+url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	req.Header.Set("Accept-Encoding", "identity")
+	p.setToken(req)
+	resp, err := p.client.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusGone {
+		return ErrGone
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("doorstest: sync status %d", resp.StatusCode)
+	}
+	r := bufio.NewReader(resp.Body)
+	roll := false
+	for {
+		signal, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch signal {
+		case signalSync:
+			data, err := r.ReadBytes(terminator)
+			if err != nil {
+				return err
+			}
+			var frame []json.RawMessage
+			if err := json.Unmarshal(data[:len(data)-1], &frame); err != nil {
+				return err
+			}
+			if len(frame) > 0 {
+				json.Unmarshal(frame[0], &p.ts)
+			}
+			if err := p.sendReport(); err != nil {
+				return err
+			}
+			if roll {
+				return nil
+			}
+		case signalAction:
+			pk, err := readPackage(r)
+			if err != nil {
+				return err
+			}
+			p.receivePackage(pk)
+		case signalRoll:
+			roll = true
+		case signalSuspend, signalKill:
+			return ErrGone
+		case terminator:
+			return nil
+		default:
+			return fmt.Errorf("doorstest: unsupported signal %d", signal)
+		}
+	}
+}
+
+func readPackage(r *bufio.Reader) (*pkg, error) {
+	data, err := r.ReadBytes(terminator)
+	if err != nil {
+		return nil, err
+	}
+	var header []json.RawMessage
+	if err := json.Unmarshal(data[:len(data)-1], &header); err != nil {
+		return nil, err
+	}
+	if len(header) < 2 {
+		return nil, errors.New("doorstest: malformed package header")
+	}
+	pk := &pkg{}
+	var seq []uint64
+	if err := json.Unmarshal(header[0], &seq); err != nil {
+		var single uint64
+		if err := json.Unmarshal(header[0], &single); err != nil {
+			return nil, err
+		}
+		seq = []uint64{single, single}
+	}
+	pk.start, pk.end = seq[0], seq[len(seq)-1]
+	var info []int
+	if err := json.Unmarshal(header[len(header)-1], &info); err != nil {
+		return nil, err
+	}
+	if len(header) == 2 {
+		pk.filler = true
+		return pk, nil
+	}
+	var call []json.RawMessage
+	if err := json.Unmarshal(header[1], &call); err != nil || len(call) != 2 {
+		return nil, errors.New("doorstest: malformed package call")
+	}
+	if err := json.Unmarshal(call[0], &pk.name); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(call[1], &pk.args); err != nil {
+		return nil, err
+	}
+	if len(info) != 2 {
+		return pk, nil
+	}
+	payload := make([]byte, info[1])
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	if payload, err = decompress(byte(info[0]), payload); err != nil {
+		return nil, err
+	}
+	pk.payload = payload
+	return pk, nil
+}
+
+// receivePackage applies packages in sequence order, as they become
+// contiguous with the ones applied before.
+func (p *Page) receivePackage(pk *pkg) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pk.end < p.cursor {
+		return
+	}
+	p.pending = append(p.pending, pk)
+	slices.SortFunc(p.pending, func(a, b *pkg) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	applied := false
+	for len(p.pending) > 0 && p.pending[0].start <= p.cursor {
+		pk := p.pending[0]
+		p.pending = p.pending[1:]
+		if pk.end < p.cursor {
+			continue
+		}
+		p.cursor = pk.end + 1
+		if pk.filler {
+			continue
+		}
+		output, err := p.call(pk.name, pk.args, pk.payload)
+		p.results[pk.end] = result{output: output, err: err}
+		applied = true
+	}
+	if applied {
+		p.notify()
+	}
+}
+
+func (p *Page) sendReport() error {
+	p.mu.Lock()
+	p.report += 1
+	id := p.report
+	results := p.results
+	p.results = make(map[uint64]result)
+	p.mu.Unlock()
+	body, err := json.Marshal([]any{id, p.ts, results, []any{}})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s%s/s/%s?t=%d&b=%s", p.client.server.URL, p.prefix, p.id, id, p.boot)
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	p.setToken(req)
+	resp, err := p.client.http.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusGone {
+		return ErrGone
+	}
+	return nil
+}
+
+// decompress decodes data by the encoding in the high nibble of payload type t.
+func decompress(t byte, data []byte) ([]byte, error) {
+	switch t & payloadCoding {
+	case 0:
+		return data, nil
+	case payloadGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	case payloadBrotli:
+		return io.ReadAll(brotli.NewReader(bytes.NewReader(data)))
+	case payloadZstd:
+		r, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	default:
+		return nil, fmt.Errorf("doorstest: unknown payload encoding 0x%x", t&payloadCoding)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+	"slices"
+
+	"github.com/doors-dev/doors/internal/door"
+	"github.com/doors-dev/gox"
+)
+
+// EachComp is a keyed list returned by [Each]. Render it directly, or with
+// the proxy syntax to use your own container element.
+type EachComp interface {
+	gox.EditorComp
+	gox.Proxy
+}
+
+// Each renders a keyed list: one [Door] per key, rendered once by render with
+// a [Beam] of its item.
+//
+// When b changes, items keep their Door as long as their key stays in the
+// list, and receive the new value through their beam; items equal to the
+// previous value (by ==) are not updated. Only inserted items are rendered
+// and sent to the client, together with the new order. Items whose key left
+// the list are unmounted. Items with a key already seen in the list are
+// skipped.
+//
+// Rendered directly, the items are placed in a <d0-r> container. With the
+// proxy syntax, they are placed in your element, which must be empty:
+//
+//	~>(doors.Each(rows, rowKey, renderRow)) <tbody></tbody>
+func Each[T comparable, K comparable](b Beam[[]T], key func(T) K, render func(Beam[T]) gox.Elem) EachComp {
+	return EachEqual(b, key, func(new T, old T) bool {
+		return new == old
+	}, render)
+}
+
+// EachEqual is [Each] with a custom equality function for items. Items for
+// which equal reports true are not updated.
+func EachEqual[T any, K comparable](b Beam[[]T], key func(T) K, equal func(new T, old T) bool, render func(Beam[T]) gox.Elem) EachComp {
+	return each[T, K]{
+		beam:   b,
+		key:    key,
+		equal:  equal,
+		render: render,
+	}
+}
+
+type each[T any, K comparable] struct {
+	beam   Beam[[]T]
+	key    func(T) K
+	equal  func(new T, old T) bool
+	render func(Beam[T]) gox.Elem
+}
+
+func (e each[T, K]) Main() gox.Elem {
+	return e.Edit
+}
+
+func (e each[T, K]) Edit(cur gox.Cursor) error {
+	list, ok := e.mount(cur.Context())
+	if !ok {
+		return nil
+	}
+	return cur.Editor(list)
+}
+
+func (e each[T, K]) Proxy(cur gox.Cursor, el gox.Elem) error {
+	list, ok := e.mount(cur.Context())
+	if !ok {
+		return nil
+	}
+	return list.Proxy(cur, el)
+}
+
+func (e each[T, K]) mount(ctx context.Context) (*Door, bool) {
+	l := &eachList[T, K]{
+		each: e,
+		door: &Door{},
+	}
+	ok := e.beam.Sub(ctx, l.update)
+	return l.door, ok
+}
+
+type eachItem[T any] struct {
+	door   *Door
+	source Source[T]
+}
+
+type eachList[T any, K comparable] struct {
+	each[T, K]
+	door    *Door
+	items   map[K]eachItem[T]
+	order   []K
+	started bool
+}
+
+func (l *eachList[T, K]) update(ctx context.Context, values []T) bool {
+	items := make(map[K]eachItem[T], len(values))
+	order := make([]K, 0, len(values))
+	for _, v := range values {
+		k := l.key(v)
+		if _, ok := items[k]; ok {
+			continue
+		}
+		item, ok := l.items[k]
+		if ok {
+			item.source.Update(ctx, v)
+		} else {
+			item = l.newItem(ctx, v)
+		}
+		items[k] = item
+		order = append(order, k)
+	}
+	for k, item := range l.items {
+		if _, ok := items[k]; !ok {
+			item.door.Unmount(ctx)
+		}
+	}
+	changed := !slices.Equal(order, l.order)
+	l.items = items
+	l.order = order
+	if l.started && !changed {
+		return false
+	}
+	doors := make([]*Door, len(order))
+	for i, k := range order {
+		doors[i] = items[k].door
+	}
+	content := gox.Elem(func(cur gox.Cursor) error {
+		for _, d := range doors {
+			if err := cur.Editor(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if !l.started {
+		l.started = true
+		l.door.Inner(ctx, content)
+		return false
+	}
+	door.Splice(ctx, l.door, content, doors)
+	return false
+}
+
+func (l *eachList[T, K]) newItem(ctx context.Context, v T) eachItem[T] {
+	item := eachItem[T]{
+		door:   &Door{},
+		source: NewSourceEqual(v, l.equal),
+	}
+	item.door.Outer(ctx, l.render(item.source))
+	return item
+}
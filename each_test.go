@@ -0,0 +1,105 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/doors-dev/gox"
+)
+
+func eachTestItem(b Beam[string]) gox.Elem {
+	return func(cur gox.Cursor) error {
+		v, _ := b.Read(cur.Context())
+		if err := cur.Init("li"); err != nil {
+			return err
+		}
+		if err := cur.Submit(); err != nil {
+			return err
+		}
+		if err := cur.Text(v); err != nil {
+			return err
+		}
+		return cur.Close()
+	}
+}
+
+func eachTestPage(list EachComp, proxy bool) gox.Elem {
+	return func(cur gox.Cursor) error {
+		for _, tag := range []string{"html", "body"} {
+			if err := cur.Init(tag); err != nil {
+				return err
+			}
+			if err := cur.Submit(); err != nil {
+				return err
+			}
+		}
+		if proxy {
+			container := gox.Elem(func(cur gox.Cursor) error {
+				if err := cur.Init("ul"); err != nil {
+					return err
+				}
+				if err := cur.Submit(); err != nil {
+					return err
+				}
+				return cur.Close()
+			})
+			if err := list.Proxy(cur, container); err != nil {
+				return err
+			}
+		} else if err := cur.Editor(list); err != nil {
+			return err
+		}
+		if err := cur.Close(); err != nil {
+			return err
+		}
+		return cur.Close()
+	}
+}
+
+func TestEachRender(t *testing.T) {
+	key := func(s string) string {
+		return strings.ToLower(s)
+	}
+	item := regexp.MustCompile(`<li[^>]*>(\w+)</li>`)
+	for _, proxy := range []bool{false, true} {
+		app := NewApp(func(ctx context.Context, r Request) gox.Comp {
+			items := NewSourceEqual([]string{"a", "b", "A", "c"}, slices.Equal[[]string])
+			return eachTestPage(Each(items, key, eachTestItem), proxy)
+		})
+		server := httptest.NewServer(app)
+		status, _, body := readURL(t, server, "/")
+		server.Close()
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status: %d", status)
+		}
+		var got []string
+		for _, m := range item.FindAllStringSubmatch(body, -1) {
+			got = append(got, m[1])
+		}
+		if strings.Join(got, ",") != "a,b,c" {
+			t.Fatalf("unexpected items with proxy=%v: %v in %q", proxy, got, body)
+		}
+		if proxy && !strings.Contains(body, "<ul") {
+			t.Fatalf("expected proxy container: %q", body)
+		}
+	}
+}
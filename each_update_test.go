@@ -0,0 +1,149 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/doors-dev/doors"
+	"github.com/doors-dev/doors/doorstest"
+	"github.com/doors-dev/gox"
+)
+
+type eachRow struct {
+	ID    string
+	Label string
+}
+
+func eachElem(tag string, attrs map[string]any, mods []gox.Modify, content ...any) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if err := cur.Init(tag); err != nil {
+			return err
+		}
+		for name, value := range attrs {
+			if err := cur.Set(name, value); err != nil {
+				return err
+			}
+		}
+		if err := cur.Modify(mods...); err != nil {
+			return err
+		}
+		if err := cur.Submit(); err != nil {
+			return err
+		}
+		if err := cur.Many(content...); err != nil {
+			return err
+		}
+		return cur.Close()
+	}
+}
+
+// eachUpdateApp renders states[0] and moves to the next state on every
+// click of #next. Each row records the render that created it in data-gen,
+// so a row that kept its Door keeps its data-gen.
+func eachUpdateApp(states [][]eachRow) doors.App {
+	return doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		var gen atomic.Int64
+		step := 0
+		rows := doors.NewSourceEqual(states[0], slices.Equal[[]eachRow])
+		next := doors.AClick{
+			On: func(ctx context.Context, r doors.RequestPointer) bool {
+				step += 1
+				rows.Update(ctx, states[step])
+				return false
+			},
+		}
+		render := func(b doors.Beam[eachRow]) gox.Elem {
+			row := b.Get()
+			return eachElem("li", map[string]any{
+				"id":       row.ID,
+				"data-gen": strconv.FormatInt(gen.Add(1), 10),
+			}, nil, b.Bind(func(row eachRow) gox.Elem {
+				return eachElem("span", nil, nil, row.Label)
+			}))
+		}
+		list := doors.Each(rows, func(row eachRow) string {
+			return row.ID
+		}, render)
+		return eachElem("html", nil, nil,
+			eachElem("head", nil, nil, eachElem("title", nil, nil, "Each")),
+			eachElem("body", nil, nil,
+				eachElem("ul", map[string]any{"id": "list"}, nil, list),
+				eachElem("button", map[string]any{"id": "next"}, []gox.Modify{next}, "next"),
+			),
+		)
+	})
+}
+
+// eachRows describes the rendered rows as "id:label:gen" in document order.
+func eachRows(p *doorstest.Page) string {
+	var rows []string
+	for _, li := range p.FindAll("#list li") {
+		rows = append(rows, fmt.Sprintf("%s:%s:%s", li.Attrs["id"], li.Text, li.Attrs["data-gen"]))
+	}
+	return strings.Join(rows, ",")
+}
+
+func TestEachUpdate(t *testing.T) {
+	states := [][]eachRow{
+		{{"a", "A"}, {"b", "B"}, {"c", "C"}},
+		// move c to the front, update a, insert d
+		{{"c", "C"}, {"a", "A2"}, {"d", "D"}, {"b", "B"}},
+		// remove a, duplicate b: the first one wins
+		{{"b", "B"}, {"d", "D"}, {"b", "X"}, {"c", "C"}},
+		// reverse
+		{{"c", "C"}, {"d", "D"}, {"b", "B"}},
+		// same order, value changes only
+		{{"c", "C2"}, {"d", "D"}, {"b", "B2"}},
+		// clear, then a key seen before comes back as a new row
+		{},
+		{{"a", "A3"}, {"e", "E"}},
+	}
+	want := []string{
+		"a:A:1,b:B:2,c:C:3",
+		"c:C:3,a:A2:1,d:D:4,b:B:2",
+		"b:B:2,d:D:4,c:C:3",
+		"c:C:3,d:D:4,b:B:2",
+		"c:C2:3,d:D:4,b:B2:2",
+		"",
+		"a:A3:5,e:E:6",
+	}
+	client := doorstest.NewClient(eachUpdateApp(states))
+	defer client.Close()
+	page, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eachRows(page); got != want[0] {
+		t.Fatalf("unexpected initial rows %q", got)
+	}
+	for i := 1; i < len(states); i++ {
+		if err := page.Click("#next"); err != nil {
+			t.Fatal(err)
+		}
+		err := page.Wait(func(p *doorstest.Page) bool {
+			return eachRows(p) == want[i]
+		})
+		if err != nil {
+			t.Fatalf("state %d: expected %q, got %q: %v", i, want[i], eachRows(page), err)
+		}
+	}
+}
@@ -17,7 +17,7 @@ package doors
 import "time"
 
 // Rect describes a rectangle in a coordinate space: origin (X, Y) and
-// dimensions (W, H). Used by [PointerEvent] to carry target,
+// dimensions (W, H). Used by [PointerEvent] and [DragEvent] to carry target,
 // page, and screen geometry needed to derive client, page, and screen
 // coordinates from offset values.
 type Rect struct {
@@ -106,6 +106,111 @@ func (e PointerEvent) ScreenY() float64 {
 	return e.ClientY() + e.Screen.Y
 }
 
+// DragEvent is the browser drag and drop event payload sent to Doors.
+//
+// Pointer carries the pointer position within the element with the attr,
+// and Target, Page, and Screen the geometry used by the coordinate helpers,
+// like in [PointerEvent]. Browsers only expose Data on dragstart and drop,
+// and Files on drop.
+type DragEvent struct {
+	Type string `json:"type"`
+	// Types lists the data transfer formats, such as "text/plain" or
+	// "Files".
+	Types []string `json:"types"`
+	// Data holds the data transfer values by format.
+	Data map[string]string `json:"data"`
+	// Files describes the dropped files. Their content is streamed with
+	// [RequestDrop].Files.
+	Files         []DragFile `json:"files"`
+	DropEffect    string     `json:"dropEffect"`
+	EffectAllowed string     `json:"effectAllowed"`
+	// SourceData holds the data-* attributes of the dragged element, keyed
+	// like the JavaScript dataset, or nil when the drag started outside the
+	// page.
+	SourceData map[string]string `json:"sourceData"`
+	// TargetData holds the data-* attributes of the element with the attr.
+	TargetData map[string]string `json:"targetData"`
+	CtrlKey    bool              `json:"ctrlKey"`
+	ShiftKey   bool              `json:"shiftKey"`
+	AltKey     bool              `json:"altKey"`
+	MetaKey    bool              `json:"metaKey"`
+	Pointer    Rect              `json:"pointer"`
+	Target     Rect              `json:"target"`
+	Page       Rect              `json:"page"`
+	Screen     Rect              `json:"screen"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// DragFile describes a file of a [DragEvent].
+type DragFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Type string `json:"type"`
+}
+
+// Text returns the "text/plain" data.
+func (e DragEvent) Text() string {
+	return e.Data["text/plain"]
+}
+
+// OffsetX returns the horizontal offset relative to the element with the
+// attr.
+func (e DragEvent) OffsetX() float64 {
+	return e.Pointer.X
+}
+
+// OffsetY returns the vertical offset relative to the element with the attr.
+func (e DragEvent) OffsetY() float64 {
+	return e.Pointer.Y
+}
+
+// ClientX returns the horizontal coordinate relative to the viewport.
+func (e DragEvent) ClientX() float64 {
+	return e.Pointer.X + e.Target.X
+}
+
+// ClientY returns the vertical coordinate relative to the viewport.
+func (e DragEvent) ClientY() float64 {
+	return e.Pointer.Y + e.Target.Y
+}
+
+// PageX returns the horizontal coordinate relative to the document.
+func (e DragEvent) PageX() float64 {
+	return e.ClientX() + e.Page.X
+}
+
+// PageY returns the vertical coordinate relative to the document.
+func (e DragEvent) PageY() float64 {
+	return e.ClientY() + e.Page.Y
+}
+
+// ScreenX returns the horizontal coordinate relative to the screen.
+func (e DragEvent) ScreenX() float64 {
+	return e.ClientX() + e.Screen.X
+}
+
+// ScreenY returns the vertical coordinate relative to the screen.
+func (e DragEvent) ScreenY() float64 {
+	return e.ClientY() + e.Screen.Y
+}
+
+// VisibilityEvent is sent by [AVisible] when the element enters or leaves
+// the viewport. Rectangles are relative to the viewport.
+type VisibilityEvent struct {
+	// Visible reports whether the element entered the viewport. It is false
+	// only for events sent with [AVisible].Leave.
+	Visible bool `json:"visible"`
+	// Ratio is the visible share of the element, from 0 to 1.
+	Ratio float64 `json:"ratio"`
+	// Target is the bounding rectangle of the element.
+	Target Rect `json:"target"`
+	// Intersection is the visible part of Target.
+	Intersection Rect `json:"intersection"`
+	// Root is the viewport grown or shrunk by [AVisible].RootMargin.
+	Root      Rect      `json:"root"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // KeyboardEvent is the browser keyboard event payload sent to Doors.
 type KeyboardEvent struct {
 	Type      string    `json:"type"`
@@ -0,0 +1,274 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/form/v4"
+)
+
+// FormValidator is implemented by [ASubmit] data types that validate
+// themselves. Validate returns errors keyed by form field name, or nil when
+// the data is valid. It runs after `validate` tag rules; a field that already
+// failed a rule keeps that error.
+type FormValidator interface {
+	Validate() map[string]error
+}
+
+// FormErrors maps form field names to their errors. Field names follow the
+// form decoder namespaces: the `form` tag or Go field name, with nested
+// fields joined by "." and slice elements indexed as "[i]".
+type FormErrors map[string]error
+
+// Get returns the error of the field, or nil.
+func (e FormErrors) Get(field string) error {
+	return e[field]
+}
+
+// Message returns the error text of the field, or an empty string.
+func (e FormErrors) Message(field string) string {
+	if err := e[field]; err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// NewFormErrors creates a [Source] for [ASubmit.Errors]. It treats error sets
+// with the same fields and messages as equal.
+func NewFormErrors() Source[FormErrors] {
+	return NewSourceEqual[FormErrors](nil, func(new FormErrors, old FormErrors) bool {
+		return maps.EqualFunc(new, old, func(a error, b error) bool {
+			return a.Error() == b.Error()
+		})
+	})
+}
+
+// FormFieldBeam derives the error message of one field from a form errors
+// beam. The message is empty while the field is valid.
+func FormFieldBeam(errs Beam[FormErrors], field string) Beam[string] {
+	return DeriveBeam(errs, func(e FormErrors) string {
+		return e.Message(field)
+	})
+}
+
+// FieldError is a field decoding or `validate` tag rule failure.
+type FieldError struct {
+	// Rule is the failed rule: "required", "min", "max", "oneof", or
+	// "decode" when the submitted value could not be decoded.
+	Rule string
+	// Param is the rule parameter, such as the bound of "min".
+	Param string
+	// Kind is the kind of the validated value.
+	Kind reflect.Kind
+	// Err is the decoder error of a "decode" failure.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	switch e.Rule {
+	case "required":
+		return "is required"
+	case "min", "max":
+		bound := "at least"
+		if e.Rule == "max" {
+			bound = "at most"
+		}
+		switch e.Kind {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, e.Param)
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must have %s %s items", bound, e.Param)
+		default:
+			return fmt.Sprintf("must be %s %s", bound, e.Param)
+		}
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(e.Param), ", ")
+	default:
+		return "is invalid"
+	}
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+var errFormRule = errors.New("form: invalid validate tag")
+
+// validateForm maps field decoding errors of data and checks its `validate`
+// tags and [FormValidator]. It returns an error for a decoding failure that
+// isn't field specific or a malformed tag.
+func validateForm(data any, decodeErr error) (FormErrors, error) {
+	errs := make(FormErrors)
+	if decodeErr != nil {
+		var fieldErrs form.DecodeErrors
+		if !errors.As(decodeErr, &fieldErrs) {
+			return nil, decodeErr
+		}
+		for field, err := range fieldErrs {
+			errs[field] = FieldError{Rule: "decode", Err: err}
+		}
+	}
+	if err := validateValue(reflect.ValueOf(data), "", errs); err != nil {
+		return nil, err
+	}
+	if v, ok := data.(FormValidator); ok {
+		for field, err := range v.Validate() {
+			if err == nil {
+				continue
+			}
+			if _, ok := errs[field]; !ok {
+				errs[field] = err
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil, nil
+	}
+	return errs, nil
+}
+
+func validateValue(v reflect.Value, ns string, errs FormErrors) error {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return validateStruct(v, ns, errs)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := validateValue(v.Index(i), ns+"["+strconv.Itoa(i)+"]", errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, ns string, errs FormErrors) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous {
+			if err := validateValue(v.Field(i), ns, errs); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if ns != "" {
+			name = ns + "." + name
+		}
+		if tag := field.Tag.Get("validate"); tag != "" {
+			if _, failed := errs[name]; !failed {
+				fail, err := checkRules(v.Field(i), tag)
+				if err != nil {
+					return fmt.Errorf("%w on field %s: %w", errFormRule, field.Name, err)
+				}
+				if fail != nil {
+					errs[name] = *fail
+				}
+			}
+		}
+		if err := validateValue(v.Field(i), name, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRules applies comma separated rules to v and returns the first
+// failure. Rules other than required pass for zero values.
+func checkRules(v reflect.Value, tag string) (*FieldError, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+		v = v.Elem()
+	}
+	zero := v.IsZero()
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+		zero = true
+	}
+	var fail *FieldError
+	for rule := range strings.SplitSeq(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		failed := false
+		switch rule {
+		case "":
+			continue
+		case "required":
+			failed = zero
+		case "min", "max":
+			bound, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule, err)
+			}
+			size, ok := valueSize(v)
+			if !ok {
+				return nil, fmt.Errorf("rule %s: unsupported kind %s", rule, v.Kind())
+			}
+			failed = !zero && ((rule == "min" && size < bound) || (rule == "max" && size > bound))
+		case "oneof":
+			switch v.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Interface:
+				return nil, fmt.Errorf("rule oneof: unsupported kind %s", v.Kind())
+			}
+			failed = !zero && !slices.Contains(strings.Fields(param), fmt.Sprint(v.Interface()))
+		default:
+			return nil, fmt.Errorf("unknown rule %q", rule)
+		}
+		if failed && fail == nil {
+			fail = &FieldError{Rule: rule, Param: param, Kind: v.Kind()}
+		}
+	}
+	return fail, nil
+}
+
+func valueSize(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+type signupAddress struct {
+	City string `form:"city" validate:"required"`
+}
+
+type signupForm struct {
+	Email     string          `form:"email" validate:"required,max=20"`
+	Name      string          `form:"name" validate:"min=2"`
+	Age       int             `form:"age" validate:"min=18"`
+	Plan      string          `form:"plan" validate:"oneof=free pro"`
+	Tags      []string        `form:"tags" validate:"max=2"`
+	Addresses []signupAddress `form:"addresses"`
+	Password  string          `form:"password"`
+}
+
+func (f *signupForm) Validate() map[string]error {
+	if f.Password == "secret" {
+		return map[string]error{
+			"password": errors.New("is too common"),
+			"email":    errors.New("is taken"),
+		}
+	}
+	return nil
+}
+
+func decodeSignup(t *testing.T, values url.Values) (signupForm, FormErrors) {
+	t.Helper()
+	var v signupForm
+	errs, err := validateForm(&v, formDecoder.Decode(&v, values))
+	if err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	return v, errs
+}
+
+func TestValidateForm(t *testing.T) {
+	_, errs := decodeSignup(t, url.Values{
+		"email": {"a@example.com"},
+		"name":  {"Al"},
+		"age":   {"21"},
+		"plan":  {"pro"},
+	})
+	if errs != nil {
+		t.Fatalf("expected valid form, got %v", errs)
+	}
+
+	_, errs = decodeSignup(t, url.Values{
+		"name":              {"A"},
+		"age":               {"x"},
+		"plan":              {"gold"},
+		"tags":              {"a", "b", "c"},
+		"addresses[0].city": {"Oslo"},
+		"addresses[1].city": {""},
+		"password":          {"secret"},
+	})
+	want := map[string]string{
+		"email":             "is required",
+		"name":              "must be at least 2 characters long",
+		"age":               "is invalid",
+		"plan":              "must be one of free, pro",
+		"tags":              "must have at most 2 items",
+		"addresses[1].city": "is required",
+		"password":          "is too common",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for field, msg := range want {
+		if errs.Message(field) != msg {
+			t.Fatalf("field %s: expected %q, got %q", field, msg, errs.Message(field))
+		}
+	}
+	var fieldErr FieldError
+	if !errors.As(errs.Get("age"), &fieldErr) || fieldErr.Rule != "decode" || fieldErr.Err == nil {
+		t.Fatalf("expected decode error for age, got %#v", errs.Get("age"))
+	}
+}
+
+func TestValidateFormBadTag(t *testing.T) {
+	var v struct {
+		Name string `validate:"required,between=1"`
+	}
+	if _, err := validateForm(&v, nil); !errors.Is(err, errFormRule) {
+		t.Fatalf("expected tag error, got %v", err)
+	}
+}
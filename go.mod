@@ -3,11 +3,13 @@ module github.com/doors-dev/doors
 go 1.25.1
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/doors-dev/gox v0.2.3
 	github.com/evanw/esbuild v0.28.0
 	github.com/gammazero/deque v1.2.1
 	github.com/go-playground/form/v4 v4.3.0
 	github.com/go-rod/rod v0.116.2
+	github.com/klauspost/compress v1.18.0
 	github.com/mr-tron/base58 v1.3.0
 	github.com/tdewolff/minify/v2 v2.24.13
 	github.com/zeebo/xxh3 v1.1.0
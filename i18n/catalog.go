@@ -0,0 +1,152 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Catalog holds translated messages by locale. It is read-only after
+// [Load] and safe for concurrent use.
+type Catalog struct {
+	messages map[Locale]map[string]message
+}
+
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+// Load reads one JSON file per locale from the root of fsys, such as
+// "en.json" or "pt-BR.json". Nested objects are flattened into dotted keys,
+// and an object whose keys are plural categories with at least "other" is a
+// plural message:
+//
+//	{
+//		"cart": {
+//			"title": "Cart",
+//			"items": {"one": "{count} item", "other": "{count} items"}
+//		}
+//	}
+func Load(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{messages: make(map[Locale]map[string]message, len(files))}
+	for _, file := range files {
+		locale, ok := ParseLocale(strings.TrimSuffix(path.Base(file), ".json"))
+		if !ok {
+			return nil, fmt.Errorf("i18n: %s: file name is not a locale", file)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var tree map[string]any
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("i18n: %s: %w", file, err)
+		}
+		messages := make(map[string]message)
+		if err := flatten(messages, "", tree); err != nil {
+			return nil, fmt.Errorf("i18n: %s: %w", file, err)
+		}
+		c.messages[locale] = messages
+	}
+	return c, nil
+}
+
+func flatten(messages map[string]message, prefix string, tree map[string]any) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case string:
+			messages[key] = message{text: value}
+		case map[string]any:
+			if plural, ok := pluralForms(value); ok {
+				messages[key] = message{plural: plural}
+				continue
+			}
+			if err := flatten(messages, key, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: message must be a string or an object", key)
+		}
+	}
+	return nil
+}
+
+func pluralForms(tree map[string]any) (map[string]string, bool) {
+	if _, ok := tree[Other]; !ok {
+		return nil, false
+	}
+	forms := make(map[string]string, len(tree))
+	for category, form := range tree {
+		text, ok := form.(string)
+		if !ok || !slices.Contains(categories, category) {
+			return nil, false
+		}
+		forms[category] = text
+	}
+	return forms, true
+}
+
+// Locales returns the locales of the catalog, sorted.
+func (c *Catalog) Locales() []Locale {
+	locales := make([]Locale, 0, len(c.messages))
+	for l := range c.messages {
+		locales = append(locales, l)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+func (c *Catalog) lookup(chain []Locale, key string) (message, Locale, bool) {
+	for _, l := range chain {
+		if m, ok := c.messages[l][key]; ok {
+			return m, l, true
+		}
+	}
+	return message{}, "", false
+}
+
+// format replaces {name} placeholders with the values of name/value pairs.
+// Unknown placeholders are left as is.
+func format(text string, args []any) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// countArgs adds {count} after args, so a formatted count passed in args
+// takes precedence.
+func countArgs(count int, args []any) []any {
+	args = slices.Clip(args[:len(args)&^1])
+	return append(args, "count", strconv.Itoa(count))
+}
@@ -0,0 +1,362 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n negotiates the locale of a Doors session and translates
+// messages from JSON catalogs.
+//
+// The locale is a session-scoped [doors.Source], so switching the language
+// rerenders every open tab of the session:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	sub, _ := fs.Sub(locales, "locales")
+//	catalog, err := i18n.Load(sub)
+//	tr := i18n.New(i18n.Config{Catalog: catalog, Default: "en", Cookie: "lang"})
+//
+//	func Page(ctx context.Context, r doors.Request) gox.Elem {
+//		tr.Negotiate(ctx, r)
+//		return page()
+//	}
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/doors-dev/doors"
+	"github.com/doors-dev/gox"
+)
+
+// Config configures [New].
+type Config struct {
+	// Catalog holds the messages. Required.
+	Catalog *Catalog
+	// Default is used when nothing else matches and as the last translation
+	// fallback. Default: the first of Locales.
+	Default Locale
+	// Locales are the supported locales. Default: the catalog locales.
+	Locales []Locale
+	// Cookie is the name of a cookie that remembers the locale picked with
+	// [I18n.Switch]. Empty disables the cookie.
+	Cookie string
+	// PathPrefix reads the locale from the first path segment, such as
+	// "/de/docs", and keeps that segment in sync when the locale changes.
+	// It also enables hreflang alternates in [I18n.Head].
+	PathPrefix bool
+	// Origin is prepended to the hreflang alternates, such as
+	// "https://example.com". Search engines expect absolute URLs.
+	Origin string
+}
+
+// I18n negotiates and translates. Create it once with [New] and share it
+// between pages.
+type I18n struct {
+	conf      Config
+	persisted doors.SessionKey[Locale]
+}
+
+// New creates an [I18n]. It panics if conf has no catalog or no locales.
+func New(conf Config) *I18n {
+	if conf.Catalog == nil {
+		panic("i18n: catalog is required")
+	}
+	if len(conf.Locales) == 0 {
+		conf.Locales = conf.Catalog.Locales()
+	}
+	if conf.Default == "" && len(conf.Locales) != 0 {
+		conf.Default = conf.Locales[0]
+	}
+	if conf.Default == "" {
+		panic("i18n: no locales")
+	}
+	if !slices.Contains(conf.Locales, conf.Default) {
+		conf.Locales = append(slices.Clone(conf.Locales), conf.Default)
+	}
+	return &I18n{
+		conf:      conf,
+		persisted: doors.NewSessionKey[Locale]("doors.i18n.locale"),
+	}
+}
+
+type sourceKey struct {
+	i *I18n
+}
+
+// Locales returns the supported locales.
+func (i *I18n) Locales() []Locale {
+	return slices.Clone(i.conf.Locales)
+}
+
+// Negotiate returns the session locale source, picking the locale on the
+// first request of a session from, in order: the value saved in the session,
+// the cookie, Accept-Language, and the default. Call it in the page function.
+//
+// With PathPrefix, a supported locale in the first path segment wins and
+// navigating to another prefix switches the locale. In turn, switching the
+// locale rewrites the prefix of the current page.
+func (i *I18n) Negotiate(ctx context.Context, r doors.Request) doors.Source[Locale] {
+	source := i.source(ctx, func() Locale {
+		return i.detect(ctx, r)
+	})
+	if !i.conf.PathPrefix {
+		return source
+	}
+	router := doors.Router(ctx)
+	router.Sub(ctx, func(ctx context.Context, loc doors.Location) bool {
+		if l, ok := i.pathLocale(loc); ok {
+			i.set(ctx, source, l)
+		}
+		return false
+	})
+	source.Sub(ctx, func(ctx context.Context, l Locale) bool {
+		loc := router.Get()
+		if current, ok := i.pathLocale(loc); ok && current != l {
+			router.Update(doors.HistoryReplaceContext(ctx), withPrefix(loc, l))
+		}
+		return false
+	})
+	return source
+}
+
+// Source returns the session locale source. Before [I18n.Negotiate] runs in
+// the session, it holds the saved or default locale.
+func (i *I18n) Source(ctx context.Context) doors.Source[Locale] {
+	return i.source(ctx, func() Locale {
+		if l, ok := i.saved(ctx); ok {
+			return l
+		}
+		return i.conf.Default
+	})
+}
+
+func (i *I18n) source(ctx context.Context, detect func() Locale) doors.Source[Locale] {
+	return doors.SessionStore(ctx).Init(sourceKey{i}, func() any {
+		return doors.NewSource(detect())
+	}).(doors.Source[Locale])
+}
+
+func (i *I18n) detect(ctx context.Context, r doors.Request) Locale {
+	if l, ok := i.saved(ctx); ok {
+		return l
+	}
+	if i.conf.Cookie != "" {
+		if c, err := r.GetCookie(i.conf.Cookie); err == nil {
+			if l, ok := ParseLocale(c.Value); ok && slices.Contains(i.conf.Locales, l) {
+				return l
+			}
+		}
+	}
+	accept := ParseAcceptLanguage(r.RequestHeader().Get("Accept-Language"))
+	if l, ok := Match(accept, i.conf.Locales); ok {
+		return l
+	}
+	return i.conf.Default
+}
+
+func (i *I18n) saved(ctx context.Context) (Locale, bool) {
+	l, ok := doors.SessionStore(ctx).Load(i.persisted).(Locale)
+	return l, ok && slices.Contains(i.conf.Locales, l)
+}
+
+func (i *I18n) set(ctx context.Context, source doors.Source[Locale], l Locale) {
+	doors.SessionStore(ctx).Save(i.persisted, l)
+	source.Update(ctx, l)
+}
+
+// Switch changes the session locale, which rerenders every open page of the
+// session that reads it. When r is not nil and Cookie is set, it also
+// remembers l in the cookie for future sessions. It returns false if l is
+// not supported.
+//
+// Example:
+//
+//	doors.AClick{
+//		On: func(ctx context.Context, r doors.RequestEvent[doors.PointerEvent]) bool {
+//			tr.Switch(ctx, r, "de")
+//			return false
+//		},
+//	}
+func (i *I18n) Switch(ctx context.Context, r doors.RequestCommon, l Locale) bool {
+	if !slices.Contains(i.conf.Locales, l) {
+		return false
+	}
+	i.set(ctx, i.Source(ctx), l)
+	if r != nil && i.conf.Cookie != "" {
+		r.SetCookie(&http.Cookie{
+			Name:     i.conf.Cookie,
+			Value:    string(l),
+			Path:     "/",
+			MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return true
+}
+
+// For returns a [Translator] for l.
+func (i *I18n) For(l Locale) Translator {
+	chain := []Locale{l}
+	if lang := Locale(l.Language()); lang != l {
+		chain = append(chain, lang)
+	}
+	if !slices.Contains(chain, i.conf.Default) {
+		chain = append(chain, i.conf.Default)
+	}
+	return Translator{
+		catalog: i.conf.Catalog,
+		locale:  l,
+		chain:   chain,
+	}
+}
+
+// Effect returns a [Translator] for the session locale and rerenders the
+// closest dynamic parent when the locale changes.
+func (i *I18n) Effect(ctx context.Context) Translator {
+	l, ok := i.Source(ctx).Effect(ctx)
+	if !ok {
+		l = i.conf.Default
+	}
+	return i.For(l)
+}
+
+// Head sets the lang attribute of <html> to the session locale with
+// [doors.Lang]. With PathPrefix, it also renders a
+// <link rel="alternate" hreflang> for every supported locale of the current
+// page, plus x-default for the default locale. Like <title>, it can be
+// rendered anywhere.
+func (i *I18n) Head() gox.Editor {
+	return gox.EditorFunc(func(cur gox.Cursor) error {
+		source := i.Source(cur.Context())
+		return cur.Any(source.Bind(func(l Locale) gox.Elem {
+			return func(cur gox.Cursor) error {
+				if err := cur.Any(doors.Lang(string(l))); err != nil {
+					return err
+				}
+				if !i.conf.PathPrefix {
+					return nil
+				}
+				return cur.Any(doors.Router(cur.Context()).Bind(i.alternates))
+			}
+		}))
+	})
+}
+
+func (i *I18n) alternates(loc doors.Location) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if _, ok := i.pathLocale(loc); !ok {
+			return nil
+		}
+		link := func(hreflang string, l Locale) error {
+			href, err := doors.Href(cur.Context(), withPrefix(loc, l))
+			if err != nil {
+				return err
+			}
+			if err := cur.InitVoid("link"); err != nil {
+				return err
+			}
+			if err := cur.Set("rel", "alternate"); err != nil {
+				return err
+			}
+			if err := cur.Set("hreflang", hreflang); err != nil {
+				return err
+			}
+			if err := cur.Set("href", i.conf.Origin+href); err != nil {
+				return err
+			}
+			return cur.Submit()
+		}
+		for _, l := range i.conf.Locales {
+			if err := link(string(l), l); err != nil {
+				return err
+			}
+		}
+		return link("x-default", i.conf.Default)
+	}
+}
+
+func (i *I18n) pathLocale(loc doors.Location) (Locale, bool) {
+	if len(loc.Segments) == 0 {
+		return "", false
+	}
+	l, ok := ParseLocale(loc.Segments[0])
+	return l, ok && slices.Contains(i.conf.Locales, l)
+}
+
+func withPrefix(loc doors.Location, l Locale) doors.Location {
+	loc = loc.Clone()
+	loc.Segments[0] = string(l)
+	return loc
+}
+
+// Translator translates messages into one locale. The zero value returns
+// keys as is.
+type Translator struct {
+	catalog *Catalog
+	locale  Locale
+	chain   []Locale
+}
+
+// Locale returns the locale of the translator.
+func (t Translator) Locale() Locale {
+	return t.locale
+}
+
+// T returns the message for key with {name} placeholders replaced by the
+// name/value pairs in args. Missing messages fall back to the base language,
+// then the default locale, then the key itself.
+//
+// Example:
+//
+//	t.T("greeting", "name", user.Name) // "Hello, {name}!"
+func (t Translator) T(key string, args ...any) string {
+	if t.catalog == nil {
+		return key
+	}
+	m, _, ok := t.catalog.lookup(t.chain, key)
+	if !ok {
+		return key
+	}
+	if m.plural != nil {
+		return format(m.plural[Other], args)
+	}
+	return format(m.text, args)
+}
+
+// N returns the plural form of the message for key that matches count, with
+// {count} and the placeholders in args replaced like in [Translator.T].
+//
+// Example:
+//
+//	t.N("cart.items", len(items)) // "{count} items"
+func (t Translator) N(key string, count int, args ...any) string {
+	if t.catalog == nil {
+		return key
+	}
+	m, l, ok := t.catalog.lookup(t.chain, key)
+	if !ok {
+		return key
+	}
+	args = countArgs(count, args)
+	if m.plural == nil {
+		return format(m.text, args)
+	}
+	form, ok := m.plural[PluralCategory(l, count)]
+	if !ok {
+		form = m.plural[Other]
+	}
+	return format(form, args)
+}
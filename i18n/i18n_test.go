@@ -0,0 +1,302 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/doors-dev/doors"
+	"github.com/doors-dev/doors/doorstest"
+	"github.com/doors-dev/gox"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	c, err := Load(fstest.MapFS{
+		"en.json": {Data: []byte(`{
+			"hello": "Hello, {name}!",
+			"cart": {
+				"title": "Cart",
+				"items": {"one": "{count} item", "other": "{count} items"}
+			},
+			"only_en": "English"
+		}`)},
+		"de.json": {Data: []byte(`{
+			"hello": "Hallo, {name}!",
+			"cart": {"items": {"one": "{count} Artikel", "other": "{count} Artikel"}}
+		}`)},
+		"ru.json": {Data: []byte(`{
+			"cart": {"items": {"one": "{count} товар", "few": "{count} товара", "many": "{count} товаров", "other": "{count} товара"}}
+		}`)},
+		"README.md": {Data: []byte("not a catalog")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestParseLocale(t *testing.T) {
+	cases := map[string]Locale{
+		"en":         "en",
+		"pt_br":      "pt-BR",
+		"ZH-hant-tw": "zh-Hant-TW",
+		"es-419":     "es-419",
+	}
+	for in, want := range cases {
+		if got, ok := ParseLocale(in); !ok || got != want {
+			t.Fatalf("ParseLocale(%q) = %q, %v, want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "-", "e", "en--US", "en-US-", "1en", "en-$$"} {
+		if got, ok := ParseLocale(in); ok {
+			t.Fatalf("ParseLocale(%q) = %q, expected failure", in, got)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	accept := ParseAcceptLanguage("fr-CH, fr;q=0.9, *;q=0.5, de;q=0.95, en;q=0")
+	if want := []Locale{"fr-CH", "de", "fr"}; !slices.Equal(accept, want) {
+		t.Fatalf("unexpected accept %v", accept)
+	}
+	supported := []Locale{"en", "de", "pt-BR"}
+	cases := []struct {
+		requested []Locale
+		want      Locale
+		ok        bool
+	}{
+		{[]Locale{"de"}, "de", true},
+		{[]Locale{"de-AT"}, "de", true},
+		{[]Locale{"pt"}, "pt-BR", true},
+		{[]Locale{"fr", "en-GB"}, "en", true},
+		{[]Locale{"fr"}, "", false},
+	}
+	for _, c := range cases {
+		if got, ok := Match(c.requested, supported); got != c.want || ok != c.ok {
+			t.Fatalf("Match(%v) = %q, %v, want %q", c.requested, got, ok, c.want)
+		}
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		locale Locale
+		n      int
+		want   string
+	}{
+		{"en", 1, One},
+		{"en", 0, Other},
+		{"en-GB", 2, Other},
+		{"fr", 0, One},
+		{"fr", 1000000, Many},
+		{"es", 1000000, Many},
+		{"pt-PT", 0, Other},
+		{"ja", 1, Other},
+		{"ru", 21, One},
+		{"ru", 22, Few},
+		{"ru", 12, Many},
+		{"ru", 25, Many},
+		{"pl", 1, One},
+		{"pl", 22, Few},
+		{"pl", 21, Many},
+		{"cs", 3, Few},
+		{"ar", 0, Zero},
+		{"ar", 2, Two},
+		{"ar", 103, Few},
+		{"ar", 111, Many},
+		{"ar", 100, Other},
+		{"lv", 10, Zero},
+		{"xx", 1, One},
+	}
+	for _, c := range cases {
+		if got := PluralCategory(c.locale, c.n); got != c.want {
+			t.Fatalf("PluralCategory(%q, %d) = %q, want %q", c.locale, c.n, got, c.want)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	c := testCatalog(t)
+	if got := c.Locales(); !slices.Equal(got, []Locale{"de", "en", "ru"}) {
+		t.Fatalf("unexpected locales %v", got)
+	}
+	tr := New(Config{Catalog: c, Default: "en"})
+	de := tr.For("de-AT")
+	if got := de.T("hello", "name", "Welt"); got != "Hallo, Welt!" {
+		t.Fatalf("unexpected text %q", got)
+	}
+	if got := de.T("cart.title"); got != "Cart" {
+		t.Fatalf("expected default locale fallback, got %q", got)
+	}
+	if got := de.T("missing"); got != "missing" {
+		t.Fatalf("expected key fallback, got %q", got)
+	}
+	ru := tr.For("ru")
+	for n, want := range map[int]string{1: "1 товар", 3: "3 товара", 11: "11 товаров"} {
+		if got := ru.N("cart.items", n); got != want {
+			t.Fatalf("N(%d) = %q, want %q", n, got, want)
+		}
+	}
+	if got := ru.N("cart.items", 1000, "count", "1 000"); got != "1 000 товаров" {
+		t.Fatalf("expected count override, got %q", got)
+	}
+	if got := tr.For("ru").N("only_en", 2); got != "English" {
+		t.Fatalf("unexpected fallback %q", got)
+	}
+	if _, err := Load(fstest.MapFS{"en.json": {Data: []byte(`{"a": 1}`)}}); err == nil {
+		t.Fatal("expected error for a non-string message")
+	}
+}
+
+func testApp(tr *I18n) http.Handler {
+	app := doors.NewApp(func(ctx context.Context, r doors.Request) gox.Elem {
+		locale := tr.Negotiate(ctx, r)
+		toDe := doors.AClick{
+			On: func(ctx context.Context, r doors.RequestPointer) bool {
+				tr.Switch(ctx, r, "de")
+				return false
+			},
+		}
+		msg := locale.Bind(func(l Locale) gox.Elem {
+			return testElem("p", map[string]any{"id": "msg"}, nil, tr.For(l).T("hello", "name", "doors"))
+		})
+		return testElem("html", nil, nil,
+			testElem("head", nil, nil, testElem("title", nil, nil, "Test")),
+			testElem("body", nil, nil,
+				tr.Head(),
+				msg,
+				testElem("button", map[string]any{"id": "de"}, []gox.Modify{toDe}, "de"),
+			),
+		)
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Accept-Language", "fr, en;q=0.8, de;q=0.5")
+		app.ServeHTTP(w, r)
+	})
+}
+
+func testElem(tag string, attrs map[string]any, mods []gox.Modify, content ...any) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if err := cur.Init(tag); err != nil {
+			return err
+		}
+		for name, value := range attrs {
+			if err := cur.Set(name, value); err != nil {
+				return err
+			}
+		}
+		if err := cur.Modify(mods...); err != nil {
+			return err
+		}
+		if err := cur.Submit(); err != nil {
+			return err
+		}
+		if err := cur.Many(content...); err != nil {
+			return err
+		}
+		return cur.Close()
+	}
+}
+
+func htmlLang(p *doorstest.Page) string {
+	html, _ := p.Find("html")
+	return html.Attrs["lang"]
+}
+
+func TestSwitch(t *testing.T) {
+	tr := New(Config{Catalog: testCatalog(t), Default: "en", Cookie: "lang"})
+	client := doorstest.NewClient(testApp(tr))
+	defer client.Close()
+	first, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := first.Text("#msg"); got != "Hello, doors!" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if got := htmlLang(first); got != "en" {
+		t.Fatalf("unexpected lang %q", got)
+	}
+	if err := first.Click("#de"); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*doorstest.Page{first, second} {
+		err := p.Wait(func(p *doorstest.Page) bool {
+			return p.Text("#msg") == "Hallo, doors!" && htmlLang(p) == "de"
+		})
+		if err != nil {
+			t.Fatalf("expected switch in every tab: %v", err)
+		}
+	}
+	third, err := client.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := htmlLang(third); got != "de" {
+		t.Fatalf("expected session locale, got %q", got)
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	tr := New(Config{
+		Catalog:    testCatalog(t),
+		Default:    "en",
+		PathPrefix: true,
+		Origin:     "https://example.com",
+	})
+	client := doorstest.NewClient(testApp(tr))
+	defer client.Close()
+	page, err := client.Open("/en/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := htmlLang(page); got != "en" {
+		t.Fatalf("unexpected lang %q", got)
+	}
+	alternates := map[string]string{}
+	for _, link := range page.FindAll("link[rel=alternate]") {
+		alternates[link.Attrs["hreflang"]] = link.Attrs["href"]
+	}
+	if alternates["de"] != "https://example.com/de/docs" || alternates["x-default"] != "https://example.com/en/docs" {
+		t.Fatalf("unexpected alternates %v", alternates)
+	}
+	if err := page.Click("#de"); err != nil {
+		t.Fatal(err)
+	}
+	err = page.Wait(func(p *doorstest.Page) bool {
+		return p.URL() == "/de/docs" && htmlLang(p) == "de"
+	})
+	if err != nil {
+		t.Fatalf("expected prefix switch: %v", err)
+	}
+	if got := page.Text("#msg"); got != "Hallo, doors!" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	other, err := client.Open("/en/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := other.Text("#msg"); got != "Hello, doors!" {
+		t.Fatalf("expected path locale to win, got %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Locale is a BCP 47 language tag such as "en", "pt-BR", or "zh-Hant".
+// Use [ParseLocale] to get the canonical form of user input.
+type Locale string
+
+// String returns the tag.
+func (l Locale) String() string {
+	return string(l)
+}
+
+// Language returns the language subtag, such as "pt" for "pt-BR".
+func (l Locale) Language() string {
+	lang, _, _ := strings.Cut(string(l), "-")
+	return lang
+}
+
+// ParseLocale returns the canonical form of a language tag: "_" becomes "-",
+// the language is lower case, a script title case, and a region upper case,
+// so "pt_br" parses as "pt-BR". It reports false for malformed tags.
+func ParseLocale(s string) (Locale, bool) {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	if len(parts) == 0 || len(parts) != strings.Count(s, "-")+strings.Count(s, "_")+1 {
+		return "", false
+	}
+	for i, part := range parts {
+		if len(part) > 8 || !isAlnum(part) {
+			return "", false
+		}
+		switch {
+		case i == 0:
+			if len(part) < 2 || !isAlpha(part) {
+				return "", false
+			}
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4 && isAlpha(part):
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2 && isAlpha(part), len(part) == 3 && !isAlpha(part):
+			parts[i] = strings.ToUpper(part)
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return Locale(strings.Join(parts, "-")), true
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header in
+// order of preference. Wildcards, malformed tags, and tags with q=0 are
+// skipped.
+func ParseAcceptLanguage(header string) []Locale {
+	type weighted struct {
+		locale Locale
+		q      float64
+	}
+	var tags []weighted
+	for item := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		q := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		l, ok := ParseLocale(strings.TrimSpace(tag))
+		if !ok {
+			continue
+		}
+		tags = append(tags, weighted{l, q})
+	}
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		return cmp.Compare(b.q, a.q)
+	})
+	locales := make([]Locale, len(tags))
+	for i, t := range tags {
+		locales[i] = t.locale
+	}
+	return locales
+}
+
+// Match returns the best supported locale for the requested ones, tried in
+// order. A requested locale matches a supported one exactly, then one with
+// just its language ("de-AT" matches "de"), then any with its language
+// ("pt" matches "pt-BR").
+func Match(requested []Locale, supported []Locale) (Locale, bool) {
+	for _, r := range requested {
+		if slices.Contains(supported, r) {
+			return r, true
+		}
+		lang := r.Language()
+		if slices.Contains(supported, Locale(lang)) {
+			return Locale(lang), true
+		}
+		for _, s := range supported {
+			if s.Language() == lang {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
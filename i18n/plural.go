@@ -0,0 +1,260 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// CLDR plural categories.
+const (
+	Zero  = "zero"
+	One   = "one"
+	Two   = "two"
+	Few   = "few"
+	Many  = "many"
+	Other = "other"
+)
+
+var categories = []string{Zero, One, Two, Few, Many, Other}
+
+type pluralRule func(n int) string
+
+var pluralRules = map[string]pluralRule{}
+
+func init() {
+	rules := []struct {
+		langs []string
+		rule  pluralRule
+	}{
+		{[]string{"ja", "zh", "ko", "th", "vi", "id", "ms", "lo", "my", "km"}, pluralNone},
+		{[]string{"en", "de", "nl", "sv", "da", "nb", "nn", "no", "fi", "et", "el", "hu", "tr", "bg", "ka", "az", "eu", "gl", "sq", "ur", "sw", "ca"}, pluralOne},
+		{[]string{"es", "it"}, pluralOneMany},
+		{[]string{"fr", "pt"}, pluralZeroOneMany},
+		{[]string{"ru", "uk", "be"}, pluralEastSlavic},
+		{[]string{"hr", "sr", "bs"}, pluralSouthSlavic},
+		{[]string{"pl"}, pluralPolish},
+		{[]string{"cs", "sk"}, pluralCzech},
+		{[]string{"lt"}, pluralLithuanian},
+		{[]string{"lv"}, pluralLatvian},
+		{[]string{"ro"}, pluralRomanian},
+		{[]string{"ar"}, pluralArabic},
+		{[]string{"he"}, pluralHebrew},
+		{[]string{"hi", "bn", "fa", "gu", "kn", "zu", "am"}, pluralZeroOne},
+		{[]string{"sl"}, pluralSlovenian},
+		{[]string{"ga"}, pluralIrish},
+		{[]string{"cy"}, pluralWelsh},
+	}
+	for _, r := range rules {
+		for _, lang := range r.langs {
+			pluralRules[lang] = r.rule
+		}
+	}
+	pluralRules["pt-PT"] = pluralOneMany
+}
+
+// PluralCategory returns the CLDR plural category of the integer n in locale
+// l, such as [One] or [Few]. Locales without a known rule use the English one.
+func PluralCategory(l Locale, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	if rule, ok := pluralRules[string(l)]; ok {
+		return rule(n)
+	}
+	if rule, ok := pluralRules[l.Language()]; ok {
+		return rule(n)
+	}
+	return pluralOne(n)
+}
+
+func pluralNone(int) string {
+	return Other
+}
+
+func pluralOne(n int) string {
+	if n == 1 {
+		return One
+	}
+	return Other
+}
+
+func pluralZeroOne(n int) string {
+	if n <= 1 {
+		return One
+	}
+	return Other
+}
+
+func pluralOneMany(n int) string {
+	switch {
+	case n == 1:
+		return One
+	case n != 0 && n%1000000 == 0:
+		return Many
+	}
+	return Other
+}
+
+func pluralZeroOneMany(n int) string {
+	switch {
+	case n <= 1:
+		return One
+	case n%1000000 == 0:
+		return Many
+	}
+	return Other
+}
+
+func pluralEastSlavic(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func pluralSouthSlavic(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	}
+	return Other
+}
+
+func pluralPolish(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case n == 1:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func pluralCzech(n int) string {
+	switch {
+	case n == 1:
+		return One
+	case n >= 2 && n <= 4:
+		return Few
+	}
+	return Other
+}
+
+func pluralLithuanian(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && (mod100 < 11 || mod100 > 19):
+		return One
+	case mod10 >= 2 && (mod100 < 11 || mod100 > 19):
+		return Few
+	}
+	return Other
+}
+
+func pluralLatvian(n int) string {
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 0 || (mod100 >= 11 && mod100 <= 19):
+		return Zero
+	case mod10 == 1 && mod100 != 11:
+		return One
+	}
+	return Other
+}
+
+func pluralRomanian(n int) string {
+	mod100 := n % 100
+	switch {
+	case n == 1:
+		return One
+	case n == 0 || (mod100 >= 2 && mod100 <= 19):
+		return Few
+	}
+	return Other
+}
+
+func pluralArabic(n int) string {
+	mod100 := n % 100
+	switch {
+	case n == 0:
+		return Zero
+	case n == 1:
+		return One
+	case n == 2:
+		return Two
+	case mod100 >= 3 && mod100 <= 10:
+		return Few
+	case mod100 >= 11:
+		return Many
+	}
+	return Other
+}
+
+func pluralHebrew(n int) string {
+	switch n {
+	case 1:
+		return One
+	case 2:
+		return Two
+	}
+	return Other
+}
+
+func pluralSlovenian(n int) string {
+	switch n % 100 {
+	case 1:
+		return One
+	case 2:
+		return Two
+	case 3, 4:
+		return Few
+	}
+	return Other
+}
+
+func pluralIrish(n int) string {
+	switch {
+	case n == 1:
+		return One
+	case n == 2:
+		return Two
+	case n >= 3 && n <= 6:
+		return Few
+	case n >= 7 && n <= 10:
+		return Many
+	}
+	return Other
+}
+
+func pluralWelsh(n int) string {
+	switch n {
+	case 0:
+		return Zero
+	case 1:
+		return One
+	case 2:
+		return Two
+	case 3:
+		return Few
+	case 6:
+		return Many
+	}
+	return Other
+}
@@ -214,3 +214,47 @@ func (irc IndicatorClassRemove) Indicators() []Indicator {
 }
 
 var _ Indicators = IndicatorClassRemove{}
+
+// IndicatorProgress shows the upload progress of the request on the selected
+// element as a whole percentage. With Attr set, the attribute receives the
+// number (e.g. the value of a <progress max="100">); otherwise the element
+// content is replaced with "N%". Requests with a progress indicator are sent
+// with XMLHttpRequest to observe the upload.
+type IndicatorProgress struct {
+	Selector Selector // Target element
+	Attr     string   // Attribute name, or empty to replace the content
+}
+
+// IndicateProgress builds an [IndicatorProgress] that targets the event
+// element.
+func IndicateProgress(attr string) IndicatorProgress {
+	return IndicatorProgress{Selector: SelectorTarget(), Attr: attr}
+}
+
+// IndicateProgressQuery builds an [IndicatorProgress] that targets the first
+// element matching query.
+func IndicateProgressQuery(query, attr string) IndicatorProgress {
+	return IndicatorProgress{Selector: SelectorQuery(query), Attr: attr}
+}
+
+// IndicateProgressQueryAll builds an [IndicatorProgress] that targets every
+// element matching query.
+func IndicateProgressQueryAll(query, attr string) IndicatorProgress {
+	return IndicatorProgress{Selector: SelectorQueryAll(query), Attr: attr}
+}
+
+// IndicateProgressQueryParent builds an [IndicatorProgress] that targets the
+// closest ancestor matching query.
+func IndicateProgressQueryParent(query, attr string) IndicatorProgress {
+	return IndicatorProgress{Selector: SelectorQueryParent(query), Attr: attr}
+}
+
+func (ip IndicatorProgress) And(i Indicators) Indicators {
+	return indication([]Indicators{ip, i})
+}
+
+func (ip IndicatorProgress) Indicators() []Indicator {
+	return []Indicator{front.IndicatorProgress(ip.Selector, ip.Attr)}
+}
+
+var _ Indicators = IndicatorProgress{}
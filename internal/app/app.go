@@ -1,11 +1,14 @@
 package app
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/doors-dev/doors/internal/instance"
@@ -27,13 +30,30 @@ func NewApp(page Page, o Options) App {
 		page:       page,
 		conf:       o.Conf,
 		csp:        o.CSP,
-		pathMaker:  path.NewPathMaker(o.Conf.ServerSessionCookiePrefix, o.ID, o.CookieName),
+		policy:     o.RequestPolicy,
+		headers:    o.Headers,
+		onAbuse:    o.OnHookAbuse,
+		pathMaker:  path.NewPathMaker(o.Conf.ServerSessionCookiePrefix, o.ID, o.CookieName).WithBase(o.BasePath),
 		tracker:    o.SessionTracker,
+		backend:    o.SessionBackend,
 		esProfiles: o.ESBuild,
 		errPage:    o.ErrorPage,
 		logger:     o.Logger,
+		tracer:     o.Tracer,
+	}
+	if o.CSP != nil && o.CSP.CollectReports {
+		csp := *o.CSP
+		csp.ReportEndpoint = a.pathMaker.CSPReport()
+		if csp.ReportTo == "" {
+			csp.ReportTo = "doors-csp"
+		}
+		if csp.ReportRateLimit == (common.RateLimit{}) {
+			csp.ReportRateLimit = common.RateLimit{Every: 100 * time.Millisecond, Burst: 100}
+		}
+		a.csp = &csp
 	}
 	a.registry = resources.NewRegistry(a)
+	a.loadAssets(o.Assets)
 	a.Use()
 	return a
 }
@@ -44,15 +64,22 @@ type app struct {
 	page       Page
 	conf       common.Conf
 	csp        *common.CSP
+	cspRate    common.RateBucket
+	policy     *common.RequestPolicy
+	headers    *common.SecurityHeaders
+	onAbuse    func(ctx context.Context, abuse common.HookAbuse)
 	registry   resources.Registry
 	pathMaker  path.PathMaker
 	tracker    SessionTracker
+	backend    SessionBackend
 	esProfiles func(profile string) api.BuildOptions
 	sessions   sync.Map
 	use        []Middleware
 	handler    http.Handler
 	errPage    ErrorPage
 	logger     *slog.Logger
+	tracer     common.Tracer
+	metrics    common.Metrics
 
 	instanceCount atomic.Int64
 	drainCallback atomic.Pointer[func()]
@@ -62,6 +89,10 @@ func (a *app) Logger() *slog.Logger {
 	return a.logger
 }
 
+func (a *app) Tracer() common.Tracer {
+	return a.tracer
+}
+
 func (a *app) ResourceRegistry() resources.Registry {
 	return a.registry
 }
@@ -90,6 +121,25 @@ func (a App) CSP() *common.CSP {
 	return a.csp
 }
 
+func (a App) RequestPolicy() *common.RequestPolicy {
+	return a.policy
+}
+
+// HookLimited counts and logs a rate limited hook request and passes it to
+// the abuse callback. ctx is the session context.
+func (a *app) HookLimited(ctx context.Context, abuse common.HookAbuse) {
+	a.metrics.HookLimited(abuse.Scope)
+	a.logger.Warn("hook request rate limited",
+		"scope", abuse.Scope.String(),
+		"instance", abuse.InstanceID,
+		"hook", abuse.HookID,
+		"rejected", abuse.Rejected,
+	)
+	if a.onAbuse != nil {
+		a.onAbuse(ctx, abuse)
+	}
+}
+
 func (a App) Resources() resources.Registry {
 	return a.registry
 }
@@ -99,9 +149,28 @@ func (a App) RemoveSession(id string) {
 	if !ok {
 		return
 	}
+	if err := a.backend.Delete(id); err != nil {
+		a.logger.Error("session backend delete error", "session", id, "error", err)
+	}
 	a.tracker.Delete(id)
 }
 
+// PersistSessions reports whether a [SessionBackend] was configured.
+func (a App) PersistSessions() bool {
+	_, none := a.backend.(noBackend)
+	return !none
+}
+
+func (a App) SaveSession(rec SessionRecord) {
+	if err := a.backend.Save(rec); err != nil {
+		a.logger.Error("session backend save error", "session", rec.ID, "error", err)
+	}
+}
+
+func (a App) ResourceStats() resources.Stats {
+	return a.registry.Stats()
+}
+
 func (a App) InstanceCount() int {
 	return int(a.instanceCount.Load())
 }
@@ -114,6 +183,56 @@ func (a App) SessionCount() (n int) {
 	return
 }
 
+type SessionInfo = instance.SessionInfo
+
+type InstanceInfo = instance.InstanceInfo
+
+type Tag = instance.Tag
+
+// Sessions returns snapshots of the live sessions, ordered by ID.
+func (a App) Sessions() []SessionInfo {
+	var infos []SessionInfo
+	a.sessions.Range(func(_, v any) bool {
+		infos = append(infos, v.(instance.Session).Info())
+		return true
+	})
+	slices.SortFunc(infos, func(a, b SessionInfo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return infos
+}
+
+// EndSessions ends the live sessions labeled with tag and deletes the
+// matching records of the session backend, so their cookies can't
+// rehydrate them. It returns how many sessions were ended.
+func (a App) EndSessions(tag Tag) (n int) {
+	a.sessions.Range(func(_, v any) bool {
+		sess := v.(instance.Session)
+		if sess.HasTag(tag) {
+			sess.End()
+			n++
+		}
+		return true
+	})
+	ids, err := a.backend.Tagged(tag)
+	if err != nil {
+		a.logger.Error("session backend lookup error", "tag", tag.Key, "error", err)
+		return
+	}
+	for _, id := range ids {
+		// Live sessions are ended above by their current tags.
+		if _, live := a.sessions.Load(id); live {
+			continue
+		}
+		if err := a.backend.Delete(id); err != nil {
+			a.logger.Error("session backend delete error", "session", id, "error", err)
+			continue
+		}
+		n++
+	}
+	return
+}
+
 func (a App) Draining() bool {
 	return a.drainCallback.Load() != nil
 }
@@ -139,6 +258,10 @@ func (a App) Drain(callback func()) {
 		a.logger.Error("Drain called more than once")
 		return
 	}
+	a.sessions.Range(func(_, v any) bool {
+		v.(instance.Session).Save()
+		return true
+	})
 	if a.instanceCount.Load() == 0 {
 		once()
 	}
@@ -163,7 +286,7 @@ func (a *app) getSession(w http.ResponseWriter, r *http.Request) instance.Sessio
 	}
 	v, ok := a.sessions.Load(c.Value)
 	if !ok {
-		return nil
+		return a.restoreSession(w, r, c.Value)
 	}
 	sess := v.(instance.Session)
 	if !sess.Renew(w) {
@@ -171,3 +294,31 @@ func (a *app) getSession(w http.ResponseWriter, r *http.Request) instance.Sessio
 	}
 	return sess
 }
+
+func (a *app) restoreSession(w http.ResponseWriter, r *http.Request, id string) instance.Session {
+	rec, ok, err := a.backend.Load(id)
+	if err != nil {
+		a.logger.Warn("session backend load error", "session", id, "error", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	if !rec.KillTime().After(time.Now()) {
+		if err := a.backend.Delete(id); err != nil {
+			a.logger.Error("session backend delete error", "session", id, "error", err)
+		}
+		return nil
+	}
+	s := instance.RestoreSession(a, rec)
+	v, loaded := a.sessions.LoadOrStore(s.ID(), s)
+	if loaded {
+		s = v.(instance.Session)
+	} else {
+		a.tracker.Create(s.ID(), r)
+	}
+	if !s.Renew(w) {
+		return nil
+	}
+	return s
+}
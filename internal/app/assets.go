@@ -0,0 +1,44 @@
+package app
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/doors-dev/doors/internal/resources"
+)
+
+// Assets configures resources built ahead of time.
+type Assets struct {
+	// Manifest holds the output of [resources.Registry.Export].
+	Manifest fs.FS
+	// Build lists assets built at startup unless found in Manifest.
+	Build []resources.Asset
+	// Strict fails startup on manifest or build errors instead of logging
+	// them and falling back to lazy builds.
+	Strict bool
+}
+
+func (a *app) loadAssets(assets Assets) {
+	var errs []error
+	if assets.Manifest != nil {
+		if err := a.registry.LoadManifest(assets.Manifest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if assets.Strict {
+		if err := a.registry.BuildClient(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := a.registry.Build(assets.Build); err != nil {
+		errs = append(errs, err)
+	}
+	err := errors.Join(errs...)
+	if err == nil {
+		return
+	}
+	if assets.Strict {
+		panic(errors.Join(errors.New("asset precompilation failed"), err))
+	}
+	a.logger.Error("asset precompilation error", "error", err)
+}
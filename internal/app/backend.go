@@ -0,0 +1,223 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/doors-dev/doors/internal/instance"
+)
+
+// SessionRecord is the persisted state of a session: its ID, expiry and
+// values saved under persistent store keys.
+type SessionRecord = instance.SessionRecord
+
+// SessionBackend persists sessions so they can be rehydrated after the
+// process restarts or on another process sharing the backend.
+type SessionBackend interface {
+	// Load returns the record saved under id. The boolean is false when no
+	// record exists.
+	Load(id string) (SessionRecord, bool, error)
+
+	// Save creates or replaces the record.
+	Save(rec SessionRecord) error
+
+	// Delete removes the record saved under id. Deleting a missing record
+	// is not an error.
+	Delete(id string) error
+
+	// Tagged returns the IDs of the records labeled with tag.
+	Tagged(tag instance.Tag) ([]string, error)
+}
+
+// noBackend is the default [SessionBackend]: sessions live only in the
+// memory of the process that created them.
+type noBackend struct{}
+
+func (noBackend) Load(id string) (SessionRecord, bool, error) {
+	return SessionRecord{}, false, nil
+}
+
+func (noBackend) Save(rec SessionRecord) error {
+	return nil
+}
+
+func (noBackend) Delete(id string) error {
+	return nil
+}
+
+func (noBackend) Tagged(tag instance.Tag) ([]string, error) {
+	return nil, nil
+}
+
+var _ SessionBackend = noBackend{}
+
+// NewMemoryBackend returns a [SessionBackend] that keeps records in memory.
+// Records are shared by every app using the same backend in this process.
+func NewMemoryBackend() SessionBackend {
+	return &memoryBackend{
+		records: make(map[string]SessionRecord),
+	}
+}
+
+type memoryBackend struct {
+	mu      sync.Mutex
+	records map[string]SessionRecord
+}
+
+func (m *memoryBackend) Load(id string) (SessionRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.records[id]
+	return rec, ok, nil
+}
+
+func (m *memoryBackend) Save(rec SessionRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[rec.ID] = rec
+	return nil
+}
+
+func (m *memoryBackend) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, id)
+	return nil
+}
+
+func (m *memoryBackend) Tagged(tag instance.Tag) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id, rec := range m.records {
+		if value, ok := rec.Tags[tag.Key]; ok && value == tag.Value {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+var _ SessionBackend = &memoryBackend{}
+
+// NewFileBackend returns a [SessionBackend] that keeps one JSON file per
+// session in dir. The directory is created if it does not exist.
+func NewFileBackend(dir string) (SessionBackend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &fileBackend{
+		dir: dir,
+	}, nil
+}
+
+type fileBackend struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (f *fileBackend) path(id string) (string, error) {
+	if id == "" {
+		return "", errors.New("empty session id")
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return "", fmt.Errorf("invalid session id %q", id)
+		}
+	}
+	return filepath.Join(f.dir, id+".json"), nil
+}
+
+func (f *fileBackend) Load(id string) (SessionRecord, bool, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return SessionRecord{}, false, err
+	}
+	f.mu.Lock()
+	data, err := os.ReadFile(path)
+	f.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return SessionRecord{}, false, nil
+	}
+	if err != nil {
+		return SessionRecord{}, false, err
+	}
+	var rec SessionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return SessionRecord{}, false, err
+	}
+	return rec, true, nil
+}
+
+func (f *fileBackend) Save(rec SessionRecord) error {
+	path, err := f.path(rec.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp, err := os.CreateTemp(f.dir, rec.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (f *fileBackend) Delete(id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Tagged reads every record in the directory.
+func (f *fileBackend) Tagged(tag instance.Tag) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paths, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var rec SessionRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("session record %s: %w", filepath.Base(path), err)
+		}
+		if value, ok := rec.Tags[tag.Key]; ok && value == tag.Value {
+			ids = append(ids, rec.ID)
+		}
+	}
+	return ids, nil
+}
+
+var _ SessionBackend = &fileBackend{}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFileBackendRoundTrip(t *testing.T) {
+	b, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected backend error: %v", err)
+	}
+	rec := SessionRecord{
+		ID:    "abc123",
+		TTL:   time.Now().Add(time.Hour).Round(0),
+		Store: map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`)},
+	}
+	if err := b.Save(rec); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	got, ok, err := b.Load("abc123")
+	if err != nil || !ok {
+		t.Fatalf("expected saved record, got ok=%v err=%v", ok, err)
+	}
+	if !got.TTL.Equal(rec.TTL) || string(got.Store["theme"]) != `"dark"` {
+		t.Fatalf("unexpected record: %#v", got)
+	}
+	if err := b.Delete("abc123"); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if _, ok, _ := b.Load("abc123"); ok {
+		t.Fatal("expected record to be deleted")
+	}
+	if _, _, err := b.Load("../etc/passwd"); err == nil {
+		t.Fatal("expected invalid session id to be rejected")
+	}
+}
+
+// waitSaved waits for the background save that follows a session renew.
+func waitSaved(b SessionBackend, id string) bool {
+	for range 100 {
+		if _, ok, _ := b.Load(id); ok {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestAppRestoresSessionFromBackend(t *testing.T) {
+	backend := NewMemoryBackend()
+	first := NewApp(nil, Options{SessionBackend: backend})
+	w := httptest.NewRecorder()
+	sess := first.ensureSession(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("expected session cookie")
+	}
+	if !waitSaved(backend, sess.ID()) {
+		t.Fatal("expected new session to be saved")
+	}
+
+	second := NewApp(nil, Options{SessionBackend: backend})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+	restored := second.getSession(httptest.NewRecorder(), r)
+	if restored == nil || restored.ID() != sess.ID() {
+		t.Fatalf("expected session %q to be restored, got %v", sess.ID(), restored)
+	}
+
+	restored.Kill()
+	if _, ok, _ := backend.Load(sess.ID()); ok {
+		t.Fatal("expected killed session to be deleted from backend")
+	}
+}
+
+// blockingBackend holds every save until release is closed.
+type blockingBackend struct {
+	SessionBackend
+	release chan struct{}
+}
+
+func (b blockingBackend) Save(rec SessionRecord) error {
+	<-b.release
+	return b.SessionBackend.Save(rec)
+}
+
+func TestSessionSavesInBackground(t *testing.T) {
+	backend := blockingBackend{SessionBackend: NewMemoryBackend(), release: make(chan struct{})}
+	a := NewApp(nil, Options{SessionBackend: backend})
+	sess := a.ensureSession(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	done := make(chan struct{})
+	go func() {
+		sess.Tag("user", "alice")
+		sess.Expire(time.Hour)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Tag and Expire not to wait for the backend")
+	}
+	close(backend.release)
+	for range 100 {
+		if rec, ok, _ := backend.Load(sess.ID()); ok && rec.Tags["user"] == "alice" && !rec.Expire.IsZero() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected tagged session to be saved")
+}
+
+func TestAppDefaultsToNoBackend(t *testing.T) {
+	a := NewApp(nil, Options{})
+	if a.PersistSessions() {
+		t.Fatal("expected sessions not to be persisted without a backend")
+	}
+	w := httptest.NewRecorder()
+	sess := a.ensureSession(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+	sess.Kill()
+	if restored := a.getSession(httptest.NewRecorder(), r); restored != nil {
+		t.Fatal("expected killed session not to be restored")
+	}
+}
+
+func TestEndSessionsDeletesBackendOnlySessions(t *testing.T) {
+	for name, backend := range map[string]func(t *testing.T) SessionBackend{
+		"memory": func(t *testing.T) SessionBackend { return NewMemoryBackend() },
+		"file": func(t *testing.T) SessionBackend {
+			b, err := NewFileBackend(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			backend := backend(t)
+			ttl := time.Now().Add(time.Hour)
+			for _, rec := range []SessionRecord{
+				{ID: "alice1", TTL: ttl, Tags: map[string]string{"user": "alice"}},
+				{ID: "alice2", TTL: ttl, Tags: map[string]string{"user": "alice", "role": "admin"}},
+				{ID: "bob", TTL: ttl, Tags: map[string]string{"user": "bob"}},
+			} {
+				if err := backend.Save(rec); err != nil {
+					t.Fatal(err)
+				}
+			}
+			a := NewApp(nil, Options{SessionBackend: backend})
+			if n := a.EndSessions(Tag{Key: "user", Value: "alice"}); n != 2 {
+				t.Fatalf("expected 2 ended sessions, got %d", n)
+			}
+			for id, saved := range map[string]bool{"alice1": false, "alice2": false, "bob": true} {
+				if _, ok, _ := backend.Load(id); ok != saved {
+					t.Fatalf("expected %s saved=%v", id, saved)
+				}
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(&http.Cookie{Name: a.pathMaker.SessionCookie(), Value: "alice1"})
+			if sess := a.getSession(httptest.NewRecorder(), r); sess != nil {
+				t.Fatal("expected ended session not to be restored")
+			}
+		})
+	}
+}
@@ -3,14 +3,23 @@ package app
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/doors-dev/doors/internal/instance"
 	"github.com/doors-dev/doors/internal/path"
 )
 
+const cspReportLimit = 64 * 1024
+
 func (a App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.headers.Apply(w.Header())
+	if a.csp != nil && a.csp.CollectReports && r.URL.Path == a.pathMaker.CSPReport() {
+		a.serveCSPReport(w, r)
+		return
+	}
 	sess := a.ensureSession(w, r)
 	r = r.WithContext(context.WithValue(r.Context(), common.KeySession, sess))
 	a.handler.ServeHTTP(w, r)
@@ -29,9 +38,13 @@ func (a *app) serve(w http.ResponseWriter, r *http.Request) {
 		a.serveError(w, r, errors.New("Session is removed from the request context"))
 		return
 	}
+	loc, ok := a.pathMaker.Location(r.URL)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Content-Type", "text/html")
-	loc := path.NewLocationFromURL(r.URL)
 	inst, ok := sess.Instance(loc)
 	if !ok {
 		http.Redirect(w, r, r.URL.String(), http.StatusTemporaryRedirect)
@@ -62,6 +75,9 @@ func (a *app) tryServeUtility(w http.ResponseWriter, r *http.Request) bool {
 		a.registry.Serve(id, w, r)
 		return true
 	}
+	if !a.checkRequest(w, r) {
+		return true
+	}
 	if hook, ok := match.Hook(); ok {
 		a.serveHook(w, r, hook.Instance, hook.Hook, hook.Track)
 		return true
@@ -89,6 +105,9 @@ func (a *app) serveSync(w http.ResponseWriter, r *http.Request, instanceId strin
 		w.WriteHeader(http.StatusGone)
 		return
 	}
+	if !a.checkToken(w, r, inst) {
+		return
+	}
 	inst.Connect(w, r)
 }
 
@@ -104,6 +123,9 @@ func (a *app) serveHook(w http.ResponseWriter, r *http.Request, instanceID strin
 		w.WriteHeader(http.StatusGone)
 		return
 	}
+	if !a.checkToken(w, r, inst) {
+		return
+	}
 	found = inst.TriggerHook(hookID, w, r, track)
 	if !found {
 		w.WriteHeader(http.StatusNotFound)
@@ -123,6 +145,9 @@ func (a *app) restoreLocation(w http.ResponseWriter, r *http.Request, instId str
 		w.WriteHeader(http.StatusGone)
 		return
 	}
+	if !a.checkToken(w, r, inst) {
+		return
+	}
 	if a.Draining() {
 		inst.Kill()
 		w.WriteHeader(http.StatusGone)
@@ -136,6 +161,89 @@ func (a *app) restoreLocation(w http.ResponseWriter, r *http.Request, instId str
 	w.WriteHeader(http.StatusOK)
 }
 
+// checkRequest applies the request policy to a hook, sync, or undo request.
+// Without a policy, WebSocket handshakes are still held to the page's own
+// origin: browsers don't apply CORS to them, so any site could open one
+// with the user's cookies otherwise.
+func (a *app) checkRequest(w http.ResponseWriter, r *http.Request) bool {
+	policy := a.policy
+	if policy == nil {
+		if !common.IsWebSocket(r) {
+			return true
+		}
+		policy = &common.RequestPolicy{}
+	}
+	reason, ok := policy.Check(r)
+	if !ok {
+		a.reject(w, r, reason)
+	}
+	return ok
+}
+
+func (a *app) checkToken(w http.ResponseWriter, r *http.Request, inst instance.Instance) bool {
+	if a.policy == nil || !a.policy.RequireToken || common.EqualToken(common.RequestToken(r), inst.Token()) {
+		return true
+	}
+	a.reject(w, r, common.RequestViolationToken)
+	return false
+}
+
+func (a *app) reject(w http.ResponseWriter, r *http.Request, reason common.RequestViolation) {
+	a.metrics.RequestRejected(reason)
+	a.logger.Warn("request rejected by policy",
+		"reason", reason.String(),
+		"method", r.Method,
+		"path", r.URL.Path,
+		"origin", r.Header.Get("Origin"),
+		"fetch_site", r.Header.Get("Sec-Fetch-Site"),
+	)
+	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+}
+
+// serveCSPReport passes violation reports to the CSP callback. Browsers
+// send reports without credentials, so no session is involved, and the
+// rate limit is shared by all clients.
+func (a *app) serveCSPReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if ok, retry, _ := a.cspRate.Take(a.csp.ReportRateLimit, time.Now()); !ok {
+		a.metrics.CSPReportDropped()
+		common.WriteRateLimited(w, retry)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, cspReportLimit))
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	reports, err := common.ParseCSPReports(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	for _, report := range reports {
+		if report.UserAgent == "" {
+			report.UserAgent = r.UserAgent()
+		}
+		if a.csp.OnReport != nil {
+			a.csp.OnReport(r, report)
+			continue
+		}
+		a.logger.Warn("csp violation",
+			"document", report.DocumentURL,
+			"directive", report.EffectiveDirective,
+			"blocked", report.BlockedURL,
+			"source", report.SourceFile,
+			"line", report.LineNumber,
+			"disposition", report.Disposition,
+		)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (a *app) serveError(w http.ResponseWriter, r *http.Request, err error) {
 	if a.errPage == nil {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
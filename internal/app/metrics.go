@@ -0,0 +1,150 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/doors-dev/doors/internal/instance"
+)
+
+var instanceStates = []string{"new", "initializing", "active", "killed"}
+
+func (a *app) Metrics() *common.Metrics {
+	return &a.metrics
+}
+
+// WriteMetrics writes the runtime metrics in the Prometheus text format.
+func (a App) WriteMetrics(w io.Writer) error {
+	states := make(map[string]int, len(instanceStates))
+	var queue, pending, saturated int
+	var rttMax float64
+	var workers, busy, limit, queued int
+	sessions := 0
+	a.sessions.Range(func(_, v any) bool {
+		sessions++
+		for inst := range v.(instance.Session).Instances() {
+			stats := inst.Stats()
+			states[stats.State]++
+			if stats.State != "active" {
+				continue
+			}
+			queue += stats.Sync.Queue
+			pending += stats.Sync.Pending
+			rttMax = max(rttMax, stats.Sync.RTT.Seconds())
+			workers += stats.Runtime.Workers
+			busy += stats.Runtime.Busy
+			limit += stats.Runtime.Limit
+			queued += stats.Runtime.Queued
+			if stats.Runtime.Busy == stats.Runtime.Limit {
+				saturated++
+			}
+		}
+		return true
+	})
+	m := &a.metrics
+	p := &promWriter{}
+
+	p.family("doors_sessions", "gauge", "Number of live sessions.")
+	p.sample("doors_sessions", "", float64(sessions))
+	p.family("doors_instances", "gauge", "Number of page instances by state.")
+	for _, state := range instanceStates {
+		p.sample("doors_instances", label("state", state), float64(states[state]))
+	}
+	p.family("doors_instance_ends_total", "counter", "Ended page instances by cause.")
+	for cause, n := range m.Ends() {
+		p.sample("doors_instance_ends_total", label("cause", common.EndCause(cause).String()), float64(n))
+	}
+	p.family("doors_sync_errors_total", "counter", "Synchronization errors that ended an instance, by reason.")
+	for reason, n := range m.SyncErrors() {
+		p.sample("doors_sync_errors_total", label("reason", common.SyncErrorReason(reason).String()), float64(n))
+	}
+	p.family("doors_requests_rejected_total", "counter", "Hook, sync, and undo requests rejected by the request policy, by reason.")
+	for reason, n := range m.Rejected() {
+		p.sample("doors_requests_rejected_total", label("reason", common.RequestViolation(reason).String()), float64(n))
+	}
+	p.family("doors_hooks_limited_total", "counter", "Hook requests rejected by rate limits, by scope.")
+	for scope, n := range m.Limited() {
+		p.sample("doors_hooks_limited_total", label("scope", common.RateLimitScope(scope).String()), float64(n))
+	}
+	p.family("doors_csp_reports_dropped_total", "counter", "CSP report requests rejected by the report rate limit.")
+	p.sample("doors_csp_reports_dropped_total", "", float64(m.CSPReportsDropped()))
+
+	p.family("doors_sync_queue_calls", "gauge", "Calls waiting to be sent to clients.")
+	p.sample("doors_sync_queue_calls", "", float64(queue))
+	p.family("doors_sync_pending_calls", "gauge", "Calls sent to clients and waiting for a report.")
+	p.sample("doors_sync_pending_calls", "", float64(pending))
+	p.family("doors_sync_rtt_max_seconds", "gauge", "Highest round trip time estimate of active instances.")
+	p.sample("doors_sync_rtt_max_seconds", "", rttMax)
+	p.histogram("doors_sync_rtt_seconds", "Round trip time estimates taken on client reports.", m.RTTs())
+	frames, frameBytes := m.Flushes()
+	p.family("doors_sync_flushes_total", "counter", "Frames written to sync streams.")
+	p.sample("doors_sync_flushes_total", "", float64(frames))
+	p.family("doors_sync_frame_bytes_total", "counter", "Bytes written to sync streams.")
+	p.sample("doors_sync_frame_bytes_total", "", float64(frameBytes))
+
+	p.histogram("doors_hook_duration_seconds", "Time taken by hook handlers.", m.Hooks())
+
+	res := a.registry.Stats()
+	p.family("doors_resource_cache_entries", "gauge", "Resources in the registry cache.")
+	p.sample("doors_resource_cache_entries", "", float64(res.Entries))
+	p.family("doors_resource_cache_bytes", "gauge", "Content size of resources in the registry cache.")
+	p.sample("doors_resource_cache_bytes", "", float64(res.Bytes))
+	p.family("doors_resource_cache_pinned", "gauge", "Cached resources held by live doors.")
+	p.sample("doors_resource_cache_pinned", "", float64(res.Pinned))
+	p.family("doors_resource_cache_hits_total", "counter", "Resource lookups served from the cache.")
+	p.sample("doors_resource_cache_hits_total", "", float64(res.Hits))
+	p.family("doors_resource_cache_misses_total", "counter", "Resource lookups that read or built the resource.")
+	p.sample("doors_resource_cache_misses_total", "", float64(res.Misses))
+	p.family("doors_resource_cache_evictions_total", "counter", "Resources evicted from the cache.")
+	p.sample("doors_resource_cache_evictions_total", "", float64(res.Evictions))
+
+	p.family("doors_runtime_workers", "gauge", "Started runtime workers of active instances.")
+	p.sample("doors_runtime_workers", "", float64(workers))
+	p.family("doors_runtime_busy_workers", "gauge", "Runtime workers of active instances running a task.")
+	p.sample("doors_runtime_busy_workers", "", float64(busy))
+	p.family("doors_runtime_worker_limit", "gauge", "Total runtime worker limit of active instances.")
+	p.sample("doors_runtime_worker_limit", "", float64(limit))
+	p.family("doors_runtime_queued_tasks", "gauge", "Runtime tasks waiting for a free worker.")
+	p.sample("doors_runtime_queued_tasks", "", float64(queued))
+	p.family("doors_runtime_saturated_instances", "gauge", "Active instances with all runtime workers busy.")
+	p.sample("doors_runtime_saturated_instances", "", float64(saturated))
+
+	_, err := p.buf.WriteTo(w)
+	return err
+}
+
+type promWriter struct {
+	buf bytes.Buffer
+}
+
+func (p *promWriter) family(name string, typ string, help string) {
+	p.buf.WriteString("# HELP " + name + " " + help + "\n")
+	p.buf.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+func (p *promWriter) sample(name string, labels string, value float64) {
+	p.buf.WriteString(name)
+	if labels != "" {
+		p.buf.WriteString("{" + labels + "}")
+	}
+	p.buf.WriteByte(' ')
+	p.buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	p.buf.WriteByte('\n')
+}
+
+func (p *promWriter) histogram(name string, help string, h common.HistogramSnapshot) {
+	p.family(name, "histogram", help)
+	for i, bound := range common.DurationBuckets {
+		le := strconv.FormatFloat(bound, 'g', -1, 64)
+		p.sample(name+"_bucket", label("le", le), float64(h.Buckets[i]))
+	}
+	p.sample(name+"_bucket", label("le", "+Inf"), float64(h.Count))
+	p.sample(name+"_sum", "", h.Sum.Seconds())
+	p.sample(name+"_count", "", float64(h.Count))
+}
+
+func label(name string, value string) string {
+	return name + "=" + strconv.Quote(value)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 
@@ -11,9 +12,9 @@ import (
 
 // SessionTracker is notified when sessions are created and removed.
 type SessionTracker interface {
-	// Create is called when a session is created. The request is the one
-	// that triggered creation; it must not be retained beyond the call,
-	// and its body must not be read.
+	// Create is called when a session is created or rehydrated from the
+	// [SessionBackend]. The request is the one that triggered creation; it
+	// must not be retained beyond the call, and its body must not be read.
 	Create(id string, r *http.Request)
 
 	// Delete is called when a session is removed.
@@ -23,12 +24,19 @@ type SessionTracker interface {
 type Options struct {
 	Conf           common.Conf
 	CSP            *common.CSP
+	RequestPolicy  *common.RequestPolicy
+	Headers        *common.SecurityHeaders
+	OnHookAbuse    func(ctx context.Context, abuse common.HookAbuse)
 	ESBuild        func(profile string) api.BuildOptions
 	SessionTracker SessionTracker
+	SessionBackend SessionBackend
+	Assets         Assets
 	ID             string
+	BasePath       string
 	CookieName     string
 	ErrorPage      ErrorPage
 	Logger         *slog.Logger
+	Tracer         common.Tracer
 }
 
 type notracker struct{}
@@ -52,7 +60,13 @@ func (o *Options) initDefaults() {
 	if o.SessionTracker == nil {
 		o.SessionTracker = notracker{}
 	}
+	if o.SessionBackend == nil {
+		o.SessionBackend = noBackend{}
+	}
 	if o.Logger == nil {
 		o.Logger = slog.Default()
 	}
+	if o.Tracer == nil {
+		o.Tracer = common.NoopTracer{}
+	}
 }
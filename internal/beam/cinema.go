@@ -16,6 +16,7 @@ package beam
 
 import (
 	"context"
+	"log/slog"
 	"sync"
 
 	"github.com/doors-dev/doors/internal/common"
@@ -27,6 +28,7 @@ type Door interface {
 	Runtime() shredder.Runtime
 	ReadFrame() shredder.Frame
 	Context() context.Context
+	StartSpan(name string, attrs ...slog.Attr) common.Span
 }
 
 type parentScreen interface {
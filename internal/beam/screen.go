@@ -16,6 +16,7 @@ package beam
 
 import (
 	"context"
+	"log/slog"
 	"sync"
 
 	"github.com/doors-dev/doors/internal/common"
@@ -119,7 +120,10 @@ func (s *screen) sync(init bool, ctx context.Context, cleanFrame shredder.Simple
 			if !b {
 				return
 			}
+			span := s.cinema.door.StartSpan(common.SpanBeamSync)
 			watchers, subs = s.commit(seq)
+			span.SetAttrs(slog.Int(common.TraceWatchers, len(watchers)))
+			span.End(nil)
 		})
 		commitFrame.Release()
 
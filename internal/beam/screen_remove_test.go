@@ -37,6 +37,10 @@ type screenTestDoor struct {
 func (d *screenTestDoor) Runtime() shredder.Runtime { return d.rt }
 func (d *screenTestDoor) ReadFrame() shredder.Frame { return shredder.FreeFrame{} }
 func (d *screenTestDoor) Context() context.Context  { return d.ctx }
+func (d *screenTestDoor) StartSpan(name string, attrs ...slog.Attr) common.Span {
+	_, span := common.NoopTracer{}.Start(d.ctx, name, attrs...)
+	return span
+}
 
 type screenTestCore struct{ c Cinema }
 
@@ -81,6 +81,33 @@ const actions = {
 		}
 		syncAttributes(meta, targetAttrs)
 	},
+	"update_lang": (_: Extras, lang: string) => {
+		if (lang) {
+			document.documentElement.lang = lang
+		} else {
+			document.documentElement.removeAttribute("lang")
+		}
+	},
+	"remove_alternate": (_: Extras, hreflang: string) => {
+		const link = document.head.querySelector(`link[rel="alternate"][hreflang=${JSON.stringify(hreflang)}]`)
+		if (!link) {
+			return
+		}
+		link.remove()
+	},
+	"update_alternate": (_: Extras, hreflang: string, attrs: {[key:string]:string}) => {
+		const targetAttrs = {
+			...attrs,
+			rel: "alternate",
+			hreflang,
+		}
+		let link = document.head.querySelector(`link[rel="alternate"][hreflang=${JSON.stringify(hreflang)}]`)
+		if (!link) {
+			link = document.createElement("link")
+			document.head.appendChild(link)
+		}
+		syncAttributes(link, targetAttrs)
+	},
 	"report_hook": (_: Extras, track: number) => {
 		report(track)
 	},
@@ -131,11 +158,14 @@ const actions = {
 		}
 		navigator.push(path, true)
 	},
-	"door_replace": (ext: Extras, doorId: number) => {
-		doors.replace(doorId, ext.payload!.text!)
+	"door_replace": (ext: Extras, doorId: number, morph?: boolean) => {
+		doors.replace(doorId, ext.payload!.text!, morph === true)
+	},
+	"door_update": (ext: Extras, doorId: number, morph?: boolean) => {
+		doors.update(doorId, ext.payload!.text!, morph === true)
 	},
-	"door_update": (ext: Extras, doorId: number) => {
-		doors.update(doorId, ext.payload!.text!)
+	"door_splice": (ext: Extras, doorId: number, order: Array<number | null> | null) => {
+		doors.splice(doorId, order, ext.payload?.text ?? "")
 	},
 }
 
@@ -56,6 +56,14 @@ interface CaptureOpt {
 	hr?: boolean;
 	// exclude value
 	ev?: boolean;
+	// visibility threshold
+	th?: number;
+	// visibility root margin
+	rm?: string;
+	// stop observing once visible
+	oc?: boolean;
+	// also fire when leaving the viewport
+	lv?: boolean;
 }
 function applyEventOpt(event: Event, opt: CaptureOpt): boolean {
 	if (opt.et) {
@@ -129,6 +137,136 @@ function getInputValues(input: HTMLInputElement | HTMLSelectElement): InputValue
 }
 
 
+// dragSource is the element being dragged within the page, so drag events
+// of other elements can report its data.
+let dragSource: HTMLElement | null = null
+document.addEventListener("dragstart", (e) => {
+	dragSource = e.target instanceof HTMLElement ? e.target : null
+}, true)
+document.addEventListener("dragend", () => {
+	dragSource = null
+})
+
+// elementData returns the dataset of an element without the Doors attributes.
+function elementData(element: HTMLElement): { [key: string]: string } {
+	const data: { [key: string]: string } = {}
+	for (const [key, value] of Object.entries(element.dataset)) {
+		if (!key.startsWith("d0") && value !== undefined) {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+function getDragValues(event: DragEvent) {
+	const dt = event.dataTransfer
+	const types = dt ? Array.from(dt.types) : []
+	const data: { [key: string]: string } = {}
+	if (dt && (event.type === "dragstart" || event.type === "drop")) {
+		for (const type of types) {
+			if (type !== "Files") {
+				data[type] = dt.getData(type)
+			}
+		}
+	}
+	const files = dt && event.type === "drop" ? Array.from(dt.files).map(file => ({
+		name: file.name,
+		size: file.size,
+		type: file.type,
+	})) : []
+	const element = event.currentTarget as HTMLElement
+	const rect = element.getBoundingClientRect()
+	return {
+		type: event.type,
+		types,
+		data,
+		files,
+		dropEffect: dt?.dropEffect ?? "",
+		effectAllowed: dt?.effectAllowed ?? "",
+		sourceData: dragSource ? elementData(dragSource) : null,
+		targetData: elementData(element),
+		ctrlKey: event.ctrlKey,
+		shiftKey: event.shiftKey,
+		altKey: event.altKey,
+		metaKey: event.metaKey,
+		pointer: {
+			x: event.clientX - rect.x,
+			y: event.clientY - rect.y,
+			width: 0,
+			height: 0,
+		},
+		target: {
+			x: rect.x,
+			y: rect.y,
+			width: rect.width,
+			height: rect.height,
+		},
+		page: {
+			x: window.scrollX,
+			y: window.scrollY,
+			width: window.innerWidth,
+			height: window.innerHeight,
+		},
+		screen: {
+			x: window.screenX,
+			y: window.screenY,
+			width: window.outerWidth,
+			height: window.outerHeight,
+		},
+		timestamp: date(new Date()),
+	}
+}
+
+function rectValues(rect: DOMRectReadOnly | null) {
+	if (!rect) {
+		return { x: 0, y: 0, width: window.innerWidth, height: window.innerHeight }
+	}
+	return { x: rect.x, y: rect.y, width: rect.width, height: rect.height }
+}
+
+const visibleEvent = "d0-visible"
+
+// observe dispatches a d0-visible event on the element when it enters the
+// viewport, and when it leaves it if the option asks for it. The returned
+// function checks the element again, so one that stays visible after a
+// completed hook fires again.
+function observe(element: Element, opt: CaptureOpt, signal: AbortSignal): () => void {
+	const threshold = opt.th ?? 0
+	let visible = false
+	let done = false
+	const observer = new IntersectionObserver((entries) => {
+		for (const entry of entries) {
+			const now = entry.isIntersecting && entry.intersectionRatio >= threshold
+			if (now === visible) {
+				continue
+			}
+			visible = now
+			if (!now && !opt.lv) {
+				continue
+			}
+			element.dispatchEvent(new CustomEvent(visibleEvent, { detail: entry }))
+			if (now && opt.oc) {
+				done = true
+				observer.disconnect()
+			}
+		}
+	}, { threshold, rootMargin: opt.rm || "0px" })
+	observer.observe(element)
+	signal.addEventListener("abort", () => {
+		done = true
+		observer.disconnect()
+	})
+	return () => {
+		if (done || !visible) {
+			return
+		}
+		// observing again reports the current state as a fresh entry
+		visible = false
+		observer.unobserve(element)
+		observer.observe(element)
+	}
+}
+
 export function capture(name: string, opt: any, arg: any, event: Event | undefined, hook: any): Promise<Response> {
 	const captureFunction = captures[name]
 	if (!captureFunction) {
@@ -259,6 +397,35 @@ const captures: { [key: string]: Capture } = {
 		};
 		return fetch(fetchOptJson(obj))
 	},
+	"drag": (fetch: Fetch, event: DragEvent, opt: CaptureOpt) => {
+		if (!applyEventOpt(event, opt)) {
+			throw new HookErr(hookErrKinds.canceled)
+		}
+		return fetch(fetchOptJson(getDragValues(event)))
+	},
+	"drop": (fetch: Fetch, event: DragEvent, opt: CaptureOpt) => {
+		opt.pd = true
+		if (!applyEventOpt(event, opt)) {
+			throw new HookErr(hookErrKinds.canceled)
+		}
+		const formData = new FormData()
+		formData.append("event", JSON.stringify(getDragValues(event)))
+		for (const file of Array.from(event.dataTransfer?.files ?? [])) {
+			formData.append("file", file, file.name)
+		}
+		return fetch(fetchOptForm(formData))
+	},
+	"visible": (fetch: Fetch, event: CustomEvent<IntersectionObserverEntry>) => {
+		const entry = event.detail
+		return fetch(fetchOptJson({
+			visible: entry.isIntersecting,
+			ratio: entry.intersectionRatio,
+			target: rectValues(entry.boundingClientRect),
+			intersection: rectValues(entry.intersectionRect),
+			root: rectValues(entry.rootBounds),
+			timestamp: date(new Date()),
+		}))
+	},
 	"input": (fetch: Fetch, event: InputEvent, opt: CaptureOpt) => {
 		return fetch(fetchOptJson({
 			type: event.type,
@@ -283,15 +450,37 @@ const captures: { [key: string]: Capture } = {
 }
 
 const attr = "data-d0c"
+
+const listeners = new WeakMap<Element, AbortController>()
+
+// detach removes the capture listeners of an element, so a morph can attach
+// the ones of its new render.
+export function detach(element: Element) {
+	listeners.get(element)?.abort()
+	listeners.delete(element)
+}
+
 export function attach(parent: Element | DocumentFragment | Document) {
 	for (const element of parent.querySelectorAll<Element>(`[${attr}]:not([${attr}="applied"])`)) {
 		const capturesList = JSON.parse(element.getAttribute(attr)!)
 		element.setAttribute(attr, "applied")
+		detach(element)
+		const abort = new AbortController()
+		listeners.set(element, abort)
 		for (const [event, name, opt, hook] of capturesList) {
 			const [hookId, scopeQueue, indicator, before, onErr] = hook
+			if (name === "drop") {
+				// the element only becomes a drop target when dragover is canceled
+				element.addEventListener("dragover", (e) => e.preventDefault(), { signal: abort.signal })
+			}
+			let recheck: (() => void) | undefined
+			if (name === "visible") {
+				recheck = observe(element, opt, abort.signal)
+			}
 			element.addEventListener(event, async (e) => {
 				try {
 					await capture(name, opt, e, e, [hookId, scopeQueue, indicator, before])
+					recheck?.()
 				} catch (error: any) {
 					if (!(error instanceof HookErr)) {
 						console.error("unknown error in capture:", error)
@@ -315,7 +504,7 @@ export function attach(parent: Element | DocumentFragment | Document) {
 						}
 					}
 				}
-			})
+			}, { signal: abort.signal })
 		}
 	}
 }
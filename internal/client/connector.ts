@@ -1,5 +1,5 @@
-import { Header, Package, Sync, SyncFrame } from "./package";
-import { boot, id, solitaireRoll, prefix, noStream } from "./params"
+import { Header, Package, Sync, SyncFrame, decoders } from "./package";
+import { boot, id, solitaireRoll, prefix, noStream, webSocket, token, tokenHeaders } from "./params"
 import { ProgressiveDelay, AbortTimer, ReliableTimer, Result, result } from "./lib"
 
 
@@ -47,12 +47,28 @@ class Conn {
 	private ts: number = 0
 	private pausePromise: Promise<void>
 	private pauseCont: (() => void) | null = null
+	private webSocket = webSocket
+	socket: WebSocket | null = null
 
 	constructor(private ctrl_: Controller) {
-		new PackageReceiver(this, 1)
+		this.receiver(1)
 		this.sender = new ReportSender(this)
 		this.pausePromise = new Promise((res) => res())
 	}
+	receiver(id: number) {
+		if (this.webSocket) {
+			new SocketReceiver(this, id)
+			return
+		}
+		new PackageReceiver(this, id)
+	}
+	socketFailed() {
+		if (!this.webSocket) {
+			return
+		}
+		this.webSocket = false
+		this.sender.fallback()
+	}
 	resetDelays() {
 		this.receiverDelay.reset()
 		this.senderDelay.reset()
@@ -193,7 +209,12 @@ class Conn {
 class ReportSender {
 	private streamer: ReportStreamer | null = null
 	constructor(private conn_: Conn) {
-		if (supportsRequestStreams) {
+		if (!webSocket) {
+			this.fallback()
+		}
+	}
+	fallback() {
+		if (supportsRequestStreams && !this.streamer) {
 			this.streamer = new ReportStreamer(this.conn_)
 		}
 	}
@@ -223,6 +244,11 @@ class ReportSender {
 			payload[3] = gaps
 		}
 		const message = JSON.stringify(payload)
+		const socket = this.conn_.socket
+		if (socket && socket.readyState === WebSocket.OPEN) {
+			socket.send(message)
+			return
+		}
 		if (this.streamer) {
 			this.streamer.write(message)
 			return
@@ -237,10 +263,11 @@ class ReportSender {
 		this.counter += 1
 		const abortTimer = new AbortTimer(solitaireRoll)
 		const [response, err] = await result(() => {
-			return fetch(`${prefix}/s/${id}?t=${this.counter}&b=${boot}`, {
+			return fetch(`${prefix}/s/${id}?t=${this.counter}&b=${boot}&e=${decoders}`, {
 				signal: abortTimer.signal,
 				method: "POST",
 				headers: {
+					...tokenHeaders,
 					'Content-Type': 'application/json;charset=UTF-8',
 				},
 				body: body,
@@ -354,13 +381,14 @@ class ReportWriter {
 		})
 	}
 	private loop() {
-		const promise = fetch(`${prefix}/s/${id}?t=${this.id}&b=${boot}`, {
+		const promise = fetch(`${prefix}/s/${id}?t=${this.id}&b=${boot}&e=${decoders}`, {
 			signal: this.abortTimer_.signal,
 			method: "POST",
 			body: this.stream.readable,
 			//@ts-ignore
 			duplex: "half",
 			headers: {
+				...tokenHeaders,
 				'Content-Type': "application/octet-stream",
 			},
 		})
@@ -429,7 +457,7 @@ class PackageReceiver {
 		this.rolled = true
 		this.rollTimer_.cancel()
 		this.conn_.pauseGuard().then(() => {
-			new PackageReceiver(this.conn_, this.id + 1)
+			this.conn_.receiver(this.id + 1)
 		})
 	}
 	private async launch() {
@@ -441,11 +469,12 @@ class PackageReceiver {
 
 	private async loop(): Promise<boolean> {
 		const [response, err] = await result(() => {
-			return fetch(`${prefix}/s/${id}?t=${this.id}&b=${boot}`, {
+			return fetch(`${prefix}/s/${id}?t=${this.id}&b=${boot}&e=${decoders}`, {
 				signal: this.abortTimer_.signal,
 				method: "GET",
 				cache: "no-store",
 				headers: {
+					...tokenHeaders,
 					Accept: "application/octet-stream",
 				},
 			})
@@ -521,6 +550,111 @@ class PackageReceiver {
 	}
 }
 
+class SocketReceiver {
+	private rollTimer_: ReliableTimer
+	private closeTimer_: ReliableTimer
+	private rolled = false
+	private opened = false
+	private finished = false
+	private packageReader: PackageReader
+	private socket: WebSocket
+	private queue: Promise<void> = Promise.resolve()
+	constructor(private conn_: Conn, private id: number) {
+		this.rollTimer_ = new ReliableTimer(solitaireRoll, () => {
+			this.roll()
+		})
+		this.closeTimer_ = new ReliableTimer(solitaireRoll * 4 / 3, () => {
+			this.socket.close()
+		})
+		this.packageReader = new PackageReader(this.conn_, this.id)
+		const protocol = location.protocol === "https:" ? "wss:" : "ws:"
+		const key = token ? `&k=${encodeURIComponent(token)}` : ""
+		this.socket = new WebSocket(`${protocol}//${location.host}${prefix}/s/${id}?t=${this.id}&b=${boot}&e=${decoders}${key}`)
+		this.socket.binaryType = "arraybuffer"
+		this.socket.onopen = () => {
+			this.opened = true
+			this.conn_.socket = this.socket
+			this.conn_.resetTTL()
+		}
+		this.socket.onmessage = (e) => {
+			const data = new Uint8Array(e.data as ArrayBuffer)
+			this.queue = this.queue.then(() => this.read(data))
+		}
+		this.socket.onclose = () => {
+			this.queue = this.queue.then(() => this.closed())
+		}
+	}
+	private roll() {
+		if (this.rolled) {
+			return
+		}
+		this.rolled = true
+		this.rollTimer_.cancel()
+		this.conn_.pauseGuard().then(() => {
+			this.conn_.receiver(this.id + 1)
+		})
+	}
+	private async read(data: Uint8Array) {
+		if (this.finished) {
+			return
+		}
+		const [chunkResult, chunkErr] = await result(() => this.packageReader.readChunk(data))
+		if (chunkErr) {
+			console.error("stream decode error", chunkErr)
+			this.error()
+			return
+		}
+		switch (chunkResult) {
+			case readResult.cont:
+				return
+			case readResult.done:
+				this.finished = true
+				this.roll()
+				this.clear()
+				return
+			case readResult.error:
+				this.error()
+				return
+		}
+	}
+	private closed() {
+		if (this.conn_.socket === this.socket) {
+			this.conn_.socket = null
+		}
+		if (this.finished) {
+			return
+		}
+		if (!this.opened) {
+			this.finished = true
+			this.clear()
+			this.rolled = true
+			this.conn_.socketFailed()
+			new PackageReceiver(this.conn_, this.id)
+			return
+		}
+		this.error()
+	}
+	private error() {
+		if (this.finished) {
+			return
+		}
+		this.finished = true
+		this.clear()
+		this.conn_.receiverDone(this.id, false)
+		if (this.rolled) {
+			return
+		}
+		this.conn_.receiverDelay.wait().then(() => {
+			this.roll()
+		})
+	}
+	private clear() {
+		this.rollTimer_.cancel()
+		this.closeTimer_.cancel()
+		result(() => this.socket.close())
+	}
+}
+
 const connectorStatus = {
 	signal: "signal",
 	sync: "sync",
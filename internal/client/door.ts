@@ -15,9 +15,10 @@
 import { rootId } from './params'
 import { doorId } from './lib'
 
-import { attach as attachCaptures, HookErr } from "./capture"
+import { attach as attachCaptures, detach as detachCaptures, HookErr } from "./capture"
 import navigator from "./navigator"
 import { attach as attachDyna } from "./dyna"
+import { Morph } from "./morph"
 
 type Handler = ((arg: any) => any) | ((arg: any, err: HookErr) => any)
 type Closure = () => void | Promise<void>
@@ -60,6 +61,10 @@ function getSelfId(el: Element): (number | undefined) {
 	return undefined
 }
 
+function isDoor(el: Element): boolean {
+	return el.matches(`${tag}, [${attr}]`)
+}
+
 function getParentId(el: Element): number {
 	const parentAttr = el.getAttribute("data-d0p")
 	if (parentAttr !== null) {
@@ -80,6 +85,30 @@ class Doors {
 	private onClear = new Map<number, Array<Closure>>()
 	private onRemove = new Map<number, Array<Closure>>()
 	private impostors = new Map<number, Set<number>>()
+	private morpher = new Morph({
+		key: (el) => isDoor(el) ? null : el.id || null,
+		compatible: (el, next) => el.hasAttribute(attr) === next.hasAttribute(attr),
+		before: (el) => {
+			const door = el as DoorElement
+			if (isDoor(el) && door._d0r !== undefined && this.elements.get(door._d0r.id) === door) {
+				this.unregister(door)
+			}
+			detachCaptures(el)
+		},
+		after: (el) => {
+			if (el.matches(tag)) {
+				this.register(el, {})
+			}
+		},
+		move: (parent, node, ref) => {
+			moving = true
+			try {
+				parent.insertBefore(node, ref)
+			} finally {
+				moving = false
+			}
+		},
+	})
 
 	private scanImpostors(parent: Element | Document | DocumentFragment) {
 		for (const element of parent.querySelectorAll<Element>(`[${attr}]:not([${attrIndexed}="indexed"])`)) {
@@ -163,12 +192,19 @@ class Doors {
 		navigator.scan(parent)
 	}
 
-	update(id: number, content: string) {
+	update(id: number, content: string, morph = false) {
 		const door = this.elements.get(id)
 		if (!door) {
 			throw new Error(`door ${id} not found`)
 		}
 		this.clear(door._d0r.id)
+		if (morph) {
+			const range = document.createRange()
+			range.selectNodeContents(door)
+			this.morpher.children(door, range.createContextualFragment(content))
+			this.scan(door)
+			return
+		}
 
 		const range = document.createRange()
 		range.selectNodeContents(door)
@@ -178,18 +214,61 @@ class Doors {
 		range.insertNode(fragment)
 	}
 
-	replace(id: number, content: string) {
+	splice(id: number, order: Array<number | null> | null, content: string) {
 		const door = this.elements.get(id)
 		if (!door) {
 			throw new Error(`door ${id} not found`)
 		}
-		if (door._d0r.impostor) {
-			this.unregister(door)
+		const range = document.createRange()
+		range.selectNodeContents(door)
+		const fragment = range.createContextualFragment(content)
+		this.scan(fragment)
+		const fresh = [...fragment.children].filter(el => el.matches(`${tag}, [${attr}]`))
+		const children: Array<Element> = []
+		for (const entry of order ?? []) {
+			const el = entry === null ? fresh.shift() : this.elements.get(entry)
+			if (el && (el.parentNode === door || el.parentNode === fragment)) {
+				children.push(el)
+			}
+		}
+		const wanted = new Set(children)
+		let ref = door.firstElementChild
+		for (const el of children) {
+			while (ref && !wanted.has(ref)) {
+				ref = ref.nextElementSibling
+			}
+			if (el === ref) {
+				ref = ref.nextElementSibling
+				continue
+			}
+			moving = el.parentNode === door
+			try {
+				door.insertBefore(el, ref)
+			} finally {
+				moving = false
+			}
+		}
+		door.appendChild(fragment)
+	}
+
+	replace(id: number, content: string, morph = false) {
+		const door = this.elements.get(id)
+		if (!door) {
+			throw new Error(`door ${id} not found`)
 		}
 		const range = document.createRange()
 		range.selectNode(door)
-		range.deleteContents()
 		const fragment = range.createContextualFragment(content)
+		const next = fragment.firstChild
+		if (morph && next && next === fragment.lastChild && this.morpher.matches(door, next)) {
+			this.morpher.node(door, next)
+			this.scan(door.parentElement ?? door)
+			return
+		}
+		if (door._d0r.impostor) {
+			this.unregister(door)
+		}
+		range.deleteContents()
 		this.scan(fragment)
 		range.insertNode(fragment)
 	}
@@ -249,15 +328,25 @@ class Doors {
 
 const doors = new Doors()
 
+// moving suppresses door registration changes while splice moves elements
+// that stay mounted.
+let moving = false
+
 customElements.define(tag,
 	class extends HTMLElement {
 		constructor() {
 			super()
 		}
 		connectedCallback() {
+			if (moving) {
+				return
+			}
 			doors.register(this, {})
 		}
 		disconnectedCallback() {
+			if (moving) {
+				return
+			}
 			doors.unregister(this)
 		}
 	}
@@ -6,6 +6,7 @@ export const hookErrKinds = {
 	network: "network",
 	bad_request: "bad_request",
 	server: "server",
+	rate_limited: "rate_limited",
 } as const
 
 type HookErrKind = typeof hookErrKinds[keyof typeof hookErrKinds]
@@ -14,6 +15,7 @@ export class HookErr extends Error {
 	public arg: any
 	public status: number | undefined = undefined
 	public cause: Error | undefined = undefined
+	public retryAfter: number | undefined = undefined
 	constructor(public kind: HookErrKind, opt?: any) {
 		let message: string
 		switch (kind) {
@@ -44,6 +46,9 @@ export class HookErr extends Error {
 			case hookErrKinds.bad_request:
 				message = `body parsing error, bad request`
 				break
+			case hookErrKinds.rate_limited:
+				message = `rate limited by server`
+				break
 			default:
 				throw new Error(`unsupported error type: ${kind}`)
 		}
@@ -57,6 +62,12 @@ export class HookErr extends Error {
 		if (cause) {
 			this.cause = cause
 		}
+		if (kind === hookErrKinds.rate_limited && opt instanceof Response) {
+			const retryAfter = Number(opt.headers.get("Retry-After"))
+			if (retryAfter > 0) {
+				this.retryAfter = retryAfter
+			}
+		}
 	}
 	canceled() { return this.kind === hookErrKinds.canceled; }
 	notFound() { return this.kind === hookErrKinds.not_found; }
@@ -65,5 +76,6 @@ export class HookErr extends Error {
 	network() { return this.kind === hookErrKinds.network; }
 	server() { return this.kind === hookErrKinds.server; }
 	badRequest() { return this.kind === hookErrKinds.bad_request; }
+	rateLimited() { return this.kind === hookErrKinds.rate_limited; }
 }
 
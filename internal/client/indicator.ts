@@ -16,7 +16,7 @@ import { setDiff, arrayDiff, splitClass } from "./lib"
 
 
 type SelectorType = "target" | "query" | "query_all" | "parent_query"
-type Kind = "attr" | "class" | "remove_class" | "content"
+type Kind = "attr" | "class" | "remove_class" | "content" | "progress"
 
 
 export type IndicatorEntry = [[SelectorType, string | null], Kind, string, string | undefined]
@@ -28,6 +28,55 @@ interface Indication {
     content_: string | null
 }
 
+function selectElements(target: Element | null, selectorType: SelectorType, query: string | null): Array<Element> {
+    const elements: Array<Element> = []
+    if (selectorType === "query") {
+        const element = document.querySelector(query!)
+        if (element) {
+            elements.push(element)
+        }
+    } else if (selectorType === "query_all") {
+        elements.push(...document.querySelectorAll(query!))
+    } else if (selectorType === "parent_query") {
+        if (target && target.parentElement) {
+            const anchestor = target.parentElement.closest(query!)
+            if (anchestor) {
+                elements.push(anchestor)
+            }
+        }
+    } else if (target) {
+        elements.push(target)
+    }
+    return elements
+}
+
+// ProgressTarget is an element showing upload progress: the attribute name
+// (empty for content) and the saved value to restore.
+type ProgressTarget = [Element, string, string | null]
+
+function newProgress(target: Element | null, indicators: IndicatorEntry[]): Array<ProgressTarget> {
+    const targets: Array<ProgressTarget> = []
+    for (const [[selectorType, query], kind, attr] of indicators) {
+        if (kind !== "progress") {
+            continue
+        }
+        for (const el of selectElements(target, selectorType, query)) {
+            targets.push([el, attr, attr ? el.getAttribute(attr) : el.innerHTML])
+        }
+    }
+    return targets
+}
+
+function setProgress(targets: Array<ProgressTarget>, percent: number) {
+    for (const [el, attr] of targets) {
+        if (attr) {
+            el.setAttribute(attr, String(percent))
+        } else {
+            el.textContent = `${percent}%`
+        }
+    }
+}
+
 function newIndicator(
     target: Element | null,
     indicators: IndicatorEntry[]
@@ -35,25 +84,10 @@ function newIndicator(
     const indications = new Map<Element, Indication>()
     for (const entry of indicators) {
         const [[selectorType, query], kind, param1, param2] = entry
-        const elements: Array<Element> = []
-        if (selectorType === "query") {
-            const element = document.querySelector(query!)
-            if (element) {
-                elements.push(element)
-            }
-        } else if (selectorType === "query_all") {
-            elements.push(...document.querySelectorAll(query!))
-        } else if (selectorType === "parent_query") {
-            if (target && target.parentElement) {
-                const anchestor = target.parentElement.closest(query!)
-                if (anchestor) {
-                    elements.push(anchestor)
-                }
-            }
-        } else if (target) {
-            elements.push(target)
+        if (kind === "progress") {
+            continue
         }
-        for (const el of elements) {
+        for (const el of selectElements(target, selectorType, query)) {
             let indication = indications.get(el)
             if (!indication) {
                 indication = {
@@ -216,6 +250,7 @@ class ElementIndicator {
 
 class IndicationController {
     private indicators_ = new Map<number, Map<Element, Indication>>()
+    private progress_ = new Map<number, Array<ProgressTarget>>()
     private elements_ = new WeakMap<Element, ElementIndicator>()
     private counter_ = 0
 
@@ -224,10 +259,18 @@ class IndicationController {
             return undefined
         }
         const indicator = newIndicator(target, indicators)
-        if (!indicator) {
+        const progress = newProgress(target, indicators)
+        if (!indicator && progress.length === 0) {
             return undefined
         }
         this.counter_ += 1
+        if (progress.length !== 0) {
+            this.progress_.set(this.counter_, progress)
+            setProgress(progress, 0)
+        }
+        if (!indicator) {
+            return this.counter_
+        }
         this.indicators_.set(this.counter_, indicator)
         for (const [el, indication] of indicator.entries()) {
             let element = this.elements_.get(el)
@@ -240,8 +283,32 @@ class IndicationController {
         return this.counter_
     }
 
+    hasProgress(id: number | undefined): boolean {
+        return id !== undefined && this.progress_.has(id)
+    }
+
+    progress(id: number | undefined, loaded: number, total: number): void {
+        if (id === undefined) return
+        const progress = this.progress_.get(id)
+        if (!progress || total <= 0) return
+        setProgress(progress, Math.min(100, Math.floor(loaded * 100 / total)))
+    }
+
     end(id: number | undefined): void {
         if (id === undefined) return
+        const progress = this.progress_.get(id)
+        if (progress) {
+            this.progress_.delete(id)
+            for (const [el, attr, saved] of progress) {
+                if (!attr) {
+                    el.innerHTML = saved ?? ""
+                } else if (saved === null) {
+                    el.removeAttribute(attr)
+                } else {
+                    el.setAttribute(attr, saved)
+                }
+            }
+        }
         const indication = this.indicators_.get(id)
         if (!indication) return
         this.indicators_.delete(id)
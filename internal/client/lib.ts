@@ -312,6 +312,9 @@ export class AbortTimer {
 	cancel() {
 		this.timer_.cancel()
 	}
+	extend() {
+		this.timer_.reset()
+	}
 	abort() {
 		this.timer_.cancel()
 		this.abortController_.abort()
@@ -331,3 +334,33 @@ export interface ReadonlySet<T> {
 	[Symbol.iterator](): IterableIterator<T>;
 }
 
+const nullBodyStatus = [101, 103, 204, 205, 304]
+
+// xhrFetch is a minimal fetch over XMLHttpRequest that reports upload
+// progress, which fetch cannot observe.
+export function xhrFetch(url: string, init: RequestInit & { headers?: { [key: string]: string } }, onProgress: (loaded: number, total: number) => void): Promise<Response> {
+	return new Promise((resolve, reject) => {
+		const xhr = new XMLHttpRequest()
+		xhr.open(init.method ?? "GET", url)
+		xhr.responseType = "blob"
+		for (const [name, value] of Object.entries(init.headers ?? {})) {
+			xhr.setRequestHeader(name, value)
+		}
+		xhr.upload.onprogress = (e) => onProgress(e.loaded, e.lengthComputable ? e.total : 0)
+		xhr.onload = () => {
+			const headers = new Headers()
+			for (const line of xhr.getAllResponseHeaders().trim().split(/[\r\n]+/)) {
+				const i = line.indexOf(":")
+				if (i > 0) {
+					headers.append(line.slice(0, i).trim(), line.slice(i + 1).trim())
+				}
+			}
+			const body = nullBodyStatus.includes(xhr.status) ? null : xhr.response
+			resolve(new Response(body, { status: xhr.status, statusText: xhr.statusText, headers }))
+		}
+		xhr.onerror = () => reject(new TypeError("network error"))
+		xhr.onabort = () => reject(init.signal?.reason ?? new DOMException("aborted", "AbortError"))
+		init.signal?.addEventListener("abort", () => xhr.abort())
+		xhr.send(init.body as XMLHttpRequestBodyInit ?? null)
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+const preserveAttr = "data-d0m"
+const systemPrefix = "data-d0"
+
+export type MorphHooks = {
+	// key identifies an element among its siblings, or null
+	key(el: Element): string | null
+	// compatible reports whether el may be patched into next
+	compatible(el: Element, next: Element): boolean
+	// before is called before el is patched into next
+	before(el: Element, next: Element): void
+	// after is called after el and its children are patched
+	after(el: Element): void
+	// move inserts a node of the live tree before ref
+	move(parent: Node, node: Node, ref: Node | null): void
+}
+
+export class Morph {
+	constructor(private hooks: MorphHooks) { }
+
+	matches(node: Node, next: Node): boolean {
+		if (node.nodeType !== next.nodeType) {
+			return false
+		}
+		if (!(node instanceof Element)) {
+			return true
+		}
+		const el = next as Element
+		if (node.tagName !== el.tagName || node.tagName === "SCRIPT") {
+			return false
+		}
+		return this.hooks.key(node) === this.hooks.key(el) && this.hooks.compatible(node, el)
+	}
+
+	// node patches node to match next, which must match it.
+	node(node: Node, next: Node) {
+		if (!(node instanceof Element)) {
+			if (node.nodeValue !== next.nodeValue) {
+				node.nodeValue = next.nodeValue
+			}
+			return
+		}
+		const el = next as Element
+		const preserve = node.hasAttribute(preserveAttr) && node.contains(document.activeElement)
+		this.hooks.before(node, el)
+		const valueChanged = node.getAttribute("value") !== el.getAttribute("value")
+		const checkedChanged = node.hasAttribute("checked") !== el.hasAttribute("checked")
+		const selectedChanged = node.hasAttribute("selected") !== el.hasAttribute("selected")
+		const textChanged = node instanceof HTMLTextAreaElement && node.defaultValue !== (el as HTMLTextAreaElement).defaultValue
+		this.attributes(node, el, preserve)
+		this.children(node, el)
+		// user input survives unless the server changed the rendered state
+		if (preserve) {
+			this.hooks.after(node)
+			return
+		}
+		if (node instanceof HTMLInputElement) {
+			if (valueChanged) {
+				node.value = (el as HTMLInputElement).value
+			}
+			if (checkedChanged) {
+				node.checked = (el as HTMLInputElement).checked
+			}
+		} else if (node instanceof HTMLTextAreaElement && textChanged) {
+			node.value = (el as HTMLTextAreaElement).value
+		} else if (node instanceof HTMLOptionElement && selectedChanged) {
+			node.selected = (el as HTMLOptionElement).selected
+		}
+		this.hooks.after(node)
+	}
+
+	// children patches the children of parent to match the children of next.
+	// Unmatched children of next are moved into parent.
+	children(parent: Node, next: Node) {
+		let cur = parent.firstChild
+		let child = next.firstChild
+		while (child) {
+			const following = child.nextSibling
+			const match = this.find(cur, child)
+			if (match === null) {
+				parent.insertBefore(child, cur)
+			} else {
+				if (match === cur) {
+					cur = cur.nextSibling
+				} else {
+					this.hooks.move(parent, match, cur)
+				}
+				this.node(match, child)
+			}
+			child = following
+		}
+		while (cur) {
+			const following = cur.nextSibling
+			parent.removeChild(cur)
+			cur = following
+		}
+	}
+
+	private find(cur: ChildNode | null, next: Node): ChildNode | null {
+		if (cur && this.matches(cur, next)) {
+			return cur
+		}
+		if (!(next instanceof Element)) {
+			return null
+		}
+		const key = this.hooks.key(next)
+		if (key === null) {
+			return null
+		}
+		for (let node = cur; node; node = node.nextSibling) {
+			if (node instanceof Element && this.matches(node, next)) {
+				return node
+			}
+		}
+		return null
+	}
+
+	// attributes syncs the attributes of el with next. With preserve, only
+	// system attributes are synced, so hooks of the new render still apply.
+	private attributes(el: Element, next: Element, preserve: boolean) {
+		for (const { name } of Array.from(el.attributes)) {
+			if (preserve && !name.startsWith(systemPrefix)) {
+				continue
+			}
+			if (!next.hasAttribute(name)) {
+				el.removeAttribute(name)
+			}
+		}
+		for (const { name, value } of Array.from(next.attributes)) {
+			if (preserve && !name.startsWith(systemPrefix)) {
+				continue
+			}
+			if (el.getAttribute(name) !== value) {
+				el.setAttribute(name, value)
+			}
+		}
+	}
+}
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { id, prefix, requestTimeout } from "./params"
+import { id, prefix, requestTimeout, tokenHeaders } from "./params"
 import { AbortTimer, arraysEqual, scrollInto } from "./lib"
 import indicator from "./indicator"
 import doors from "./door";
@@ -125,6 +125,7 @@ export class Navigator {
 			const r = await fetch(`${prefix}/u/${id}${urls.toString(urls.current())}`, {
 				method: "GET",
 				signal: abortTimer.signal,
+				headers: tokenHeaders,
 			});
 			if (!r.ok) {
 				throw new Error("code " + r.status);
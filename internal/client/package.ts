@@ -21,14 +21,45 @@ const payloadTypes = {
 	binaryGz: 0x11,
 	jsonGz: 0x12,
 	textGz: 0x13,
+	binaryBr: 0x21,
+	jsonBr: 0x22,
+	textBr: 0x23,
+	binaryZstd: 0x31,
+	jsonZstd: 0x32,
+	textZstd: 0x33,
 } as const;
 
 export type PayloadType = typeof payloadTypes[keyof typeof payloadTypes]
 
-function isGzip(payloadType: PayloadType): boolean {
-	return (payloadType & 0xF0) == 0x10
+const payloadFormats: { [flag: number]: string } = {
+	0x10: "gzip",
+	0x20: "brotli",
+	0x30: "zstd",
 }
 
+function format(payloadType: PayloadType): string | undefined {
+	return payloadFormats[payloadType & 0xF0]
+}
+
+function decompress(stream: ReadableStream, payloadType: PayloadType): ReadableStream {
+	const f = format(payloadType)
+	if (f === undefined) {
+		return stream
+	}
+	return stream.pipeThrough(new DecompressionStream(f as CompressionFormat))
+}
+
+// decoders lists the payload encodings this browser can decode besides gzip,
+// reported to the server on connect.
+export const decoders: string = [["br", "brotli"], ["zstd", "zstd"]].filter(([, f]) => {
+	try {
+		new DecompressionStream(f as CompressionFormat)
+		return true
+	} catch {
+		return false
+	}
+}).map(([encoding]) => encoding).join(",")
+
 function isBinary(payloadType: PayloadType): boolean {
 	return (payloadType & 0x0F) == 0x01
 }
@@ -60,7 +91,7 @@ export function decodePayload(payload: EncodedPayload | undefined): DecodedPaylo
 	if (payloadType === payloadTypes.none) {
 		return {}
 	}
-	if (!isGzip(payloadType)) {
+	if (format(payloadType) === undefined) {
 		if (isText(payloadType)) {
 			return {
 				text: content,
@@ -78,12 +109,7 @@ export function decodePayload(payload: EncodedPayload | undefined): DecodedPaylo
 	return (async () => {
 		const prefix = "data:application/octet-stream;base64,"
 		const res = await fetch(prefix + content)
-		let stream = res.body!
-		if (isGzip(payloadType)) {
-			const ds = new DecompressionStream("gzip")
-			stream = stream.pipeThrough(ds)
-		}
-		const resp = new Response(stream)
+		const resp = new Response(decompress(res.body!, payloadType))
 		if (isBinary(payloadType)) {
 			const binary = await resp.arrayBuffer()
 			return {
@@ -145,11 +171,7 @@ export class Package {
 	private stream(): ReadableStream {
 		const blob = new Blob(this.parts_ as any)
 		this.parts_ = []
-		if (!isGzip(this.payloadType_)) {
-			return blob.stream()
-		}
-		const ds = new DecompressionStream("gzip");
-		return blob.stream().pipeThrough(ds);
+		return decompress(blob.stream(), this.payloadType_)
 	}
 
 	async finalize(): Promise<boolean> {
@@ -21,3 +21,6 @@ export const requestTimeout: number = Number(document.currentScript!.dataset.req
 export const solitaireRoll: number = Number(document.currentScript!.dataset.roll)
 export const noStream: boolean = !!document.currentScript!.dataset.nostream
 export const boot: string = Array.from(crypto.getRandomValues(new Uint8Array(8)), b => b.toString(16).padStart(2, "0")).join("")
+export const webSocket: boolean = !!document.currentScript!.dataset.ws
+export const token: string = document.currentScript!.dataset.token ?? ""
+export const tokenHeaders: { [key: string]: string } = token ? { "D0-Token": token } : {}
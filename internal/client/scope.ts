@@ -13,8 +13,8 @@
 // limitations under the License.
 
 import indicator, { IndicatorEntry } from './indicator'
-import { requestTimeout, id, prefix } from './params'
-import { AbortTimer, FetchOpt, result } from './lib'
+import { requestTimeout, id, prefix, tokenHeaders } from './params'
+import { AbortTimer, FetchOpt, result, xhrFetch } from './lib'
 import action, { Action } from './calls'
 import { decodePayload } from './package'
 import { HookErr, hookErrKinds } from './hook_err'
@@ -96,11 +96,20 @@ class Hook {
 		this.track_ = runtime.hookRegister(this)
 		const track = this.track_
 		this.actions(this.params_.before).then(() => {
-			fetch(`${prefix}/h/${id}/${this.params_.hookId}?t=${track}`, {
+			const url = `${prefix}/h/${id}/${this.params_.hookId}?t=${track}`
+			const init = {
 				method: "POST",
 				signal: this.abortTimer_!.signal,
 				...this.fetch_,
-			}).then(r => {
+				headers: { ...tokenHeaders, ...this.fetch_.headers },
+			}
+			const request = indicator.hasProgress(this.indicatorId_)
+				? xhrFetch(url, init, (loaded, total) => {
+					this.abortTimer_!.extend()
+					indicator.progress(this.indicatorId_, loaded, total)
+				})
+				: fetch(url, init)
+			request.then(r => {
 				this.abortTimer_!.cancel()
 				if (r.ok) {
 					controller.resetDelays()
@@ -113,6 +122,8 @@ class Hook {
 					runtime.hookErr(track, new HookErr(hookErrKinds.bad_request))
 				} else if (r.status === 404) {
 					runtime.hookErr(track, new HookErr(hookErrKinds.not_found))
+				} else if (r.status === 429) {
+					runtime.hookErr(track, new HookErr(hookErrKinds.rate_limited, r))
 				} else if (r.status >= 500 && r.status < 600) {
 					runtime.hookErr(track, new HookErr(hookErrKinds.server, r))
 				} else {
@@ -0,0 +1,239 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/doors-dev/doors/internal/beam"
+	"github.com/doors-dev/doors/internal/common"
+)
+
+// Broker moves messages between nodes.
+type Broker interface {
+	// Publish sends data to every subscriber of topic, including subscribers
+	// on the publishing node. Messages from one publisher must reach each
+	// subscriber in publish order.
+	Publish(topic string, data []byte) error
+	// Subscribe calls handler for every message published to topic, one at a
+	// time. The handler must not modify data. The returned function ends the
+	// subscription.
+	Subscribe(topic string, handler func(data []byte)) (func(), error)
+}
+
+// reconnector is implemented by brokers that can lose messages while
+// disconnected, such as [NetBroker]. Replicas resync when f runs.
+type reconnector interface {
+	OnReconnect(f func()) func()
+}
+
+// stamp orders replicated values. Clock is a Lamport clock, Node breaks
+// ties, so every node picks the same winner among concurrent updates.
+type stamp struct {
+	Clock uint64
+	Node  string
+}
+
+func (s stamp) after(o stamp) bool {
+	if s.Clock != o.Clock {
+		return s.Clock > o.Clock
+	}
+	return s.Node > o.Node
+}
+
+type envelope struct {
+	Node  string          `json:"n"`
+	Clock uint64          `json:"c,omitempty"`
+	Value json.RawMessage `json:"v,omitempty"`
+	// Hello asks other nodes to publish their current value.
+	Hello bool `json:"h,omitempty"`
+}
+
+type Replica[T any] = *replica[T]
+
+var _ beam.Lenser[any] = (*replica[any])(nil)
+
+// replica replicates a source over a broker topic. Values are sent as
+// JSON and the latest stamp wins.
+type replica[T any] struct {
+	beam.Source[T]
+	broker Broker
+	topic  string
+	node   string
+	mu     sync.Mutex
+	clock  uint64
+	last   stamp
+	cancel func()
+	unhook func()
+	closed bool
+}
+
+func NewReplica[T any](broker Broker, topic string, source beam.Source[T]) (Replica[T], error) {
+	r := &replica[T]{
+		Source: source,
+		broker: broker,
+		topic:  topic,
+		node:   common.RandId(),
+	}
+	cancel, err := broker.Subscribe(topic, r.receive)
+	if err != nil {
+		return nil, err
+	}
+	r.cancel = cancel
+	if err := r.hello(); err != nil {
+		cancel()
+		return nil, err
+	}
+	if rb, ok := broker.(reconnector); ok {
+		r.unhook = rb.OnReconnect(r.resync)
+	}
+	return r, nil
+}
+
+// hello asks the other nodes to publish their current value.
+func (r *replica[T]) hello() error {
+	data, err := json.Marshal(envelope{Node: r.node, Hello: true})
+	if err != nil {
+		panic(err)
+	}
+	return r.broker.Publish(r.topic, data)
+}
+
+// resync runs after the broker reconnected. Updates may have been lost in
+// both directions, so the replica asks for the current values and publishes
+// its own again.
+func (r *replica[T]) resync() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	if err := r.hello(); err != nil {
+		common.Logger(context.Background()).Error("Cluster source resync error", "topic", r.topic, "error", err)
+		return
+	}
+	if r.last.Clock != 0 {
+		r.publish(context.Background(), r.Source.Get(), r.last)
+	}
+}
+
+// Close stops replication. The source keeps its value and works locally.
+func (r *replica[T]) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.cancel()
+	if r.unhook != nil {
+		r.unhook()
+	}
+}
+
+func (r *replica[T]) Update(ctx context.Context, v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Source.Update(ctx, v)
+	r.publish(ctx, v, r.tick())
+}
+
+func (r *replica[T]) XUpdate(ctx context.Context, v T) <-chan error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch := r.Source.XUpdate(ctx, v)
+	r.publish(ctx, v, r.tick())
+	return ch
+}
+
+func (r *replica[T]) Mutate(ctx context.Context, m func(T) T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var next T
+	r.Source.Mutate(ctx, func(v T) T {
+		next = m(v)
+		return next
+	})
+	r.publish(ctx, next, r.tick())
+}
+
+func (r *replica[T]) XMutate(ctx context.Context, m func(T) T) <-chan error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var next T
+	ch := r.Source.XMutate(ctx, func(v T) T {
+		next = m(v)
+		return next
+	})
+	r.publish(ctx, next, r.tick())
+	return ch
+}
+
+func (r *replica[T]) tick() stamp {
+	r.clock += 1
+	r.last = stamp{Clock: r.clock, Node: r.node}
+	return r.last
+}
+
+func (r *replica[T]) publish(ctx context.Context, v T, s stamp) {
+	if r.closed {
+		return
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		common.Logger(ctx).Error("Cluster source encoding error", "topic", r.topic, "error", err)
+		return
+	}
+	data, err := json.Marshal(envelope{Node: s.Node, Clock: s.Clock, Value: value})
+	if err != nil {
+		panic(err)
+	}
+	if err := r.broker.Publish(r.topic, data); err != nil {
+		common.Logger(ctx).Error("Cluster source publish error", "topic", r.topic, "error", err)
+	}
+}
+
+func (r *replica[T]) receive(data []byte) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		common.Logger(context.Background()).Error("Cluster source message error", "topic", r.topic, "error", err)
+		return
+	}
+	if env.Node == r.node {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if env.Hello {
+		if r.last.Clock != 0 {
+			r.publish(context.Background(), r.Source.Get(), r.last)
+		}
+		return
+	}
+	r.clock = max(r.clock, env.Clock)
+	s := stamp{Clock: env.Clock, Node: env.Node}
+	if !s.after(r.last) {
+		return
+	}
+	var v T
+	if err := json.Unmarshal(env.Value, &v); err != nil {
+		common.Logger(context.Background()).Error("Cluster source decoding error", "topic", r.topic, "error", err)
+		return
+	}
+	r.last = s
+	r.Source.Update(context.Background(), v)
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doors-dev/doors/internal/beam"
+	"github.com/doors-dev/doors/internal/common"
+)
+
+func newTestReplica[T comparable](t *testing.T, b Broker, topic string, init T) Replica[T] {
+	t.Helper()
+	r, err := NewReplica(b, topic, beam.NewSource(init, beam.DefaultEqual, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(r.Close)
+	return r
+}
+
+func eventually(t *testing.T, what string, ok func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !ok() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// stampBroker records the stamps of published values.
+type stampBroker struct {
+	Broker
+	mu     sync.Mutex
+	stamps map[stamp]struct{}
+}
+
+func (b *stampBroker) Publish(topic string, data []byte) error {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	if !env.Hello {
+		b.mu.Lock()
+		b.stamps[stamp{Clock: env.Clock, Node: env.Node}] = struct{}{}
+		b.mu.Unlock()
+	}
+	return b.Broker.Publish(topic, data)
+}
+
+func TestReplicaPropagatesAndSuppressesEchoes(t *testing.T) {
+	broker := &stampBroker{Broker: NewMemoryBroker(), stamps: make(map[stamp]struct{})}
+	a := newTestReplica(t, broker, "orders", 0)
+	b := newTestReplica(t, broker, "orders", 0)
+	other := newTestReplica(t, broker, "users", 0)
+	ctx := context.Background()
+
+	a.Update(ctx, 1)
+	eventually(t, "update on b", func() bool { return b.Get() == 1 })
+	b.Mutate(ctx, func(v int) int { return v + 1 })
+	eventually(t, "mutation on a", func() bool { return a.Get() == 2 })
+
+	time.Sleep(20 * time.Millisecond)
+	broker.mu.Lock()
+	if n := len(broker.stamps); n != 2 {
+		t.Fatalf("expected received values not to be published as new updates, got %d stamps", n)
+	}
+	broker.mu.Unlock()
+	if other.Get() != 0 {
+		t.Fatalf("expected other topics to be unaffected, got %d", other.Get())
+	}
+}
+
+func TestReplicaConcurrentUpdatesConverge(t *testing.T) {
+	broker := NewMemoryBroker()
+	replicas := []Replica[int]{
+		newTestReplica(t, broker, "counter", 0),
+		newTestReplica(t, broker, "counter", 0),
+		newTestReplica(t, broker, "counter", 0),
+	}
+	var wg sync.WaitGroup
+	for i, r := range replicas {
+		wg.Go(func() {
+			for j := range 50 {
+				r.Update(context.Background(), i*100+j)
+			}
+		})
+	}
+	wg.Wait()
+	eventually(t, "replicas to converge", func() bool {
+		v := replicas[0].Get()
+		return replicas[1].Get() == v && replicas[2].Get() == v
+	})
+}
+
+func TestReplicaLateJoinerCatchesUp(t *testing.T) {
+	broker := NewMemoryBroker()
+	a := newTestReplica(t, broker, "state", "init")
+	a.Update(context.Background(), "current")
+	b := newTestReplica(t, broker, "state", "init")
+	eventually(t, "late joiner to catch up", func() bool { return b.Get() == "current" })
+}
+
+func TestNetBroker(t *testing.T) {
+	for _, network := range []string{"tcp", "unix"} {
+		t.Run(network, func(t *testing.T) {
+			address := "127.0.0.1:0"
+			if network == "unix" {
+				address = filepath.Join(t.TempDir(), "broker.sock")
+			}
+			l, err := net.Listen(network, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := NewBrokerServer()
+			go srv.Serve(l)
+			t.Cleanup(func() { srv.Close() })
+
+			dial := func() *NetBroker {
+				b, err := DialBroker(network, l.Addr().String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { b.Close() })
+				return b
+			}
+			a := newTestReplica(t, dial(), "orders", 0)
+			b := newTestReplica(t, dial(), "orders", 0)
+			ctx := context.Background()
+			for i := 1; i <= 20; i++ {
+				a.Update(ctx, i)
+			}
+			eventually(t, "update on b", func() bool { return b.Get() == 20 })
+			b.Update(ctx, 42)
+			eventually(t, "update on a", func() bool { return a.Get() == 42 })
+		})
+	}
+}
+
+func TestNetBrokerReconnects(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	srv := NewBrokerServer()
+	go srv.Serve(l)
+
+	a, err := DialBroker("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	received := make(chan string, 16)
+	cancel, err := a.Subscribe("topic", func(data []byte) { received <- string(data) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	srv.Close()
+	l, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("address not reusable: %v", err)
+	}
+	srv = NewBrokerServer()
+	go srv.Serve(l)
+	defer srv.Close()
+
+	// Publishes may fail or get lost until the client notices the restart.
+	deadline := time.After(5 * time.Second)
+	for {
+		a.Publish("topic", []byte("hello"))
+		select {
+		case msg := <-received:
+			if msg != "hello" {
+				t.Fatalf("unexpected message %q", msg)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("expected subscription to be restored after reconnect")
+		}
+	}
+}
+
+func TestReplicaResyncsAfterReconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	srv := NewBrokerServer()
+	go srv.Serve(l)
+
+	dial := func() *NetBroker {
+		b, err := DialBroker("tcp", address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { b.Close() })
+		return b
+	}
+	a := newTestReplica(t, dial(), "state", "init")
+	b := newTestReplica(t, dial(), "state", "init")
+	ctx := context.Background()
+	a.Update(ctx, "before")
+	eventually(t, "update on b", func() bool { return b.Get() == "before" })
+
+	srv.Close()
+	// Lost: the server is gone.
+	a.Update(ctx, "during")
+	l, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("address not reusable: %v", err)
+	}
+	srv = NewBrokerServer()
+	go srv.Serve(l)
+	defer srv.Close()
+
+	eventually(t, "b to resync", func() bool { return b.Get() == "during" })
+	b.Update(ctx, "after")
+	eventually(t, "update on a", func() bool { return a.Get() == "after" })
+}
+
+func TestNetBrokerSubscribeError(t *testing.T) {
+	server, client := net.Pipe()
+	server.Close()
+	b := &NetBroker{
+		conn:       client,
+		topics:     make(map[string]common.Set[*subscriber]),
+		reconnects: common.NewSet[*func()](),
+		done:       make(chan struct{}),
+	}
+	if _, err := b.Subscribe("topic", func([]byte) {}); err == nil {
+		t.Fatal("expected failed subscribe write to be returned")
+	}
+	if len(b.topics) != 0 {
+		t.Fatalf("expected failed subscription to be dropped, got %v", b.topics)
+	}
+}
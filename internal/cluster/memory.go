@@ -0,0 +1,118 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/doors-dev/doors/internal/common"
+)
+
+// MemoryBroker is a [Broker] within one process. Each subscriber gets
+// messages in publish order on its own goroutine.
+type MemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]common.Set[*subscriber]
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics: make(map[string]common.Set[*subscriber]),
+	}
+}
+
+func (b *MemoryBroker) Publish(topic string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.topics[topic] {
+		sub.push(slices.Clone(data))
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(topic string, handler func(data []byte)) (func(), error) {
+	sub := newSubscriber(handler)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs, ok := b.topics[topic]
+	if !ok {
+		subs = common.NewSet[*subscriber]()
+		b.topics[topic] = subs
+	}
+	subs.Add(sub)
+	return sync.OnceFunc(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		subs.Remove(sub)
+		if len(subs) == 0 {
+			delete(b.topics, topic)
+		}
+		sub.close()
+	}), nil
+}
+
+// subscriber queues messages for a handler and drains the queue on one
+// goroutine at a time, so the handler never runs concurrently with itself.
+type subscriber struct {
+	mu      sync.Mutex
+	handler func([]byte)
+	queue   [][]byte
+	running bool
+	closed  bool
+}
+
+func newSubscriber(handler func([]byte)) *subscriber {
+	return &subscriber{
+		handler: handler,
+	}
+}
+
+func (s *subscriber) push(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.queue = append(s.queue, data)
+	if s.running {
+		return
+	}
+	s.running = true
+	go s.drain()
+}
+
+func (s *subscriber) drain() {
+	for {
+		s.mu.Lock()
+		if s.closed || len(s.queue) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		data := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		s.handler(data)
+	}
+}
+
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.queue = nil
+}
@@ -0,0 +1,472 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/doors-dev/doors/internal/common"
+)
+
+// Wire format: one op byte, a 2 byte topic length, the topic, a 4 byte
+// data length and the data. Lengths are big-endian.
+const (
+	opSubscribe   byte = 'S'
+	opUnsubscribe byte = 'U'
+	opPublish     byte = 'P'
+)
+
+const (
+	maxMessage  = 16 * 1024 * 1024
+	outboxSize  = 1024
+	dialTimeout = 5 * time.Second
+	maxBackoff  = 5 * time.Second
+)
+
+var (
+	ErrBrokerClosed       = errors.New("broker closed")
+	ErrBrokerDisconnected = errors.New("broker disconnected")
+	errMessageSize        = errors.New("broker message too large")
+)
+
+func encodeMessage(op byte, topic string, data []byte) []byte {
+	buf := make([]byte, 0, 7+len(topic)+len(data))
+	buf = append(buf, op)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(topic)))
+	buf = append(buf, topic...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+func writeMessage(w io.Writer, op byte, topic string, data []byte) error {
+	if len(topic) > 0xFFFF || len(data) > maxMessage {
+		return errMessageSize
+	}
+	_, err := w.Write(encodeMessage(op, topic, data))
+	return err
+}
+
+func readMessage(r *bufio.Reader) (byte, string, []byte, error) {
+	var head [3]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, "", nil, err
+	}
+	topic := make([]byte, binary.BigEndian.Uint16(head[1:]))
+	if _, err := io.ReadFull(r, topic); err != nil {
+		return 0, "", nil, err
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return 0, "", nil, err
+	}
+	length := binary.BigEndian.Uint32(size[:])
+	if length > maxMessage {
+		return 0, "", nil, errMessageSize
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, "", nil, err
+	}
+	return head[0], string(topic), data, nil
+}
+
+// BrokerServer relays messages between [NetBroker] clients. Every
+// message goes to all clients subscribed to its topic, the publisher
+// included. A client that can't keep up is disconnected and reconnects.
+type BrokerServer struct {
+	mu        sync.Mutex
+	topics    map[string]common.Set[*peer]
+	peers     common.Set[*peer]
+	listeners common.Set[net.Listener]
+	closed    bool
+}
+
+func NewBrokerServer() *BrokerServer {
+	return &BrokerServer{
+		topics:    make(map[string]common.Set[*peer]),
+		peers:     common.NewSet[*peer](),
+		listeners: common.NewSet[net.Listener](),
+	}
+}
+
+// Serve accepts clients on l until l fails or the server is closed.
+func (s *BrokerServer) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrBrokerClosed
+	}
+	s.listeners.Add(l)
+	s.mu.Unlock()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.listeners.Remove(l)
+			s.mu.Unlock()
+			if closed {
+				return ErrBrokerClosed
+			}
+			return err
+		}
+		p := &peer{
+			conn:   conn,
+			outbox: make(chan []byte, outboxSize),
+			done:   make(chan struct{}),
+			topics: common.NewSet[string](),
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrBrokerClosed
+		}
+		s.peers.Add(p)
+		s.mu.Unlock()
+		go p.write()
+		go s.read(p)
+	}
+}
+
+// Close stops all listeners and disconnects all clients.
+func (s *BrokerServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	for p := range s.peers {
+		p.close()
+	}
+	return nil
+}
+
+func (s *BrokerServer) read(p *peer) {
+	defer s.drop(p)
+	r := bufio.NewReader(p.conn)
+	for {
+		op, topic, data, err := readMessage(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		switch op {
+		case opSubscribe:
+			subs, ok := s.topics[topic]
+			if !ok {
+				subs = common.NewSet[*peer]()
+				s.topics[topic] = subs
+			}
+			subs.Add(p)
+			p.topics.Add(topic)
+		case opUnsubscribe:
+			s.unsubscribe(p, topic)
+		case opPublish:
+			for sub := range s.topics[topic] {
+				sub.send(opPublish, topic, data)
+			}
+		default:
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *BrokerServer) unsubscribe(p *peer, topic string) {
+	p.topics.Remove(topic)
+	subs := s.topics[topic]
+	subs.Remove(p)
+	if len(subs) == 0 {
+		delete(s.topics, topic)
+	}
+}
+
+func (s *BrokerServer) drop(p *peer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for topic := range p.topics {
+		s.unsubscribe(p, topic)
+	}
+	s.peers.Remove(p)
+	p.close()
+}
+
+type peer struct {
+	conn   net.Conn
+	outbox chan []byte
+	done   chan struct{}
+	once   sync.Once
+	topics common.Set[string]
+}
+
+func (p *peer) send(op byte, topic string, data []byte) {
+	select {
+	case p.outbox <- encodeMessage(op, topic, data):
+	default:
+		p.close()
+	}
+}
+
+func (p *peer) write() {
+	w := bufio.NewWriter(p.conn)
+	for {
+		select {
+		case buf := <-p.outbox:
+			if _, err := w.Write(buf); err != nil {
+				p.close()
+				return
+			}
+			if len(p.outbox) != 0 {
+				continue
+			}
+			if err := w.Flush(); err != nil {
+				p.close()
+				return
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *peer) close() {
+	p.once.Do(func() {
+		close(p.done)
+		p.conn.Close()
+	})
+}
+
+// NetBroker is a [Broker] client of a [BrokerServer]. It reconnects on
+// its own, subscribes again after a reconnect and then runs the
+// [NetBroker.OnReconnect] callbacks; messages published while disconnected fail
+// with [ErrBrokerDisconnected].
+//
+// Writes are ordered by writeMu, which is taken before mu is released, so
+// subscribe and unsubscribe messages reach the server in the order the
+// topics changed without holding mu during I/O.
+type NetBroker struct {
+	network    string
+	address    string
+	mu         sync.Mutex
+	writeMu    sync.Mutex
+	conn       net.Conn
+	topics     map[string]common.Set[*subscriber]
+	reconnects common.Set[*func()]
+	closed     bool
+	done       chan struct{}
+}
+
+// DialBroker connects to a [BrokerServer] at address, for example
+// DialBroker("tcp", "10.0.0.2:7070") or DialBroker("unix", "/run/doors.sock").
+func DialBroker(network string, address string) (*NetBroker, error) {
+	conn, err := net.DialTimeout(network, address, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	b := &NetBroker{
+		network:    network,
+		address:    address,
+		conn:       conn,
+		topics:     make(map[string]common.Set[*subscriber]),
+		reconnects: common.NewSet[*func()](),
+		done:       make(chan struct{}),
+	}
+	go b.run(conn)
+	return b, nil
+}
+
+func (b *NetBroker) Publish(topic string, data []byte) error {
+	b.mu.Lock()
+	conn := b.conn
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		return ErrBrokerClosed
+	}
+	if conn == nil {
+		return ErrBrokerDisconnected
+	}
+	return b.write(conn, opPublish, topic, data)
+}
+
+// Subscribe fails if the subscribe message can't be written. While the
+// broker is disconnected it succeeds and the topic is subscribed on
+// reconnect.
+func (b *NetBroker) Subscribe(topic string, handler func(data []byte)) (func(), error) {
+	sub := newSubscriber(handler)
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrBrokerClosed
+	}
+	subs, ok := b.topics[topic]
+	if !ok {
+		subs = common.NewSet[*subscriber]()
+		b.topics[topic] = subs
+	}
+	subs.Add(sub)
+	cancel := sync.OnceFunc(func() {
+		b.mu.Lock()
+		sub.close()
+		subs.Remove(sub)
+		if len(subs) != 0 || b.topics[topic] == nil {
+			b.mu.Unlock()
+			return
+		}
+		delete(b.topics, topic)
+		conn := b.conn
+		if conn == nil || b.closed {
+			b.mu.Unlock()
+			return
+		}
+		b.writeMu.Lock()
+		b.mu.Unlock()
+		defer b.writeMu.Unlock()
+		// A failed write means the connection is gone, and the topic is
+		// not subscribed again on reconnect.
+		writeMessage(conn, opUnsubscribe, topic, nil)
+	})
+	conn := b.conn
+	if ok || conn == nil {
+		b.mu.Unlock()
+		return cancel, nil
+	}
+	b.writeMu.Lock()
+	b.mu.Unlock()
+	err := writeMessage(conn, opSubscribe, topic, nil)
+	b.writeMu.Unlock()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return cancel, nil
+}
+
+// OnReconnect registers f to run after every reconnect, once the topics
+// are subscribed again. Messages may have been lost while disconnected, so
+// f should resync whatever state travels over the broker. The returned
+// function removes f.
+func (b *NetBroker) OnReconnect(f func()) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reconnects.Add(&f)
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.reconnects.Remove(&f)
+	}
+}
+
+// Close disconnects from the server and stops reconnecting.
+func (b *NetBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	for _, subs := range b.topics {
+		for sub := range subs {
+			sub.close()
+		}
+	}
+	b.topics = nil
+	if b.conn != nil {
+		return b.conn.Close()
+	}
+	return nil
+}
+
+func (b *NetBroker) write(conn net.Conn, op byte, topic string, data []byte) error {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	return writeMessage(conn, op, topic, data)
+}
+
+func (b *NetBroker) run(conn net.Conn) {
+	backoff := 100 * time.Millisecond
+	for {
+		b.read(conn)
+		b.mu.Lock()
+		b.conn = nil
+		b.mu.Unlock()
+		conn.Close()
+		for {
+			select {
+			case <-b.done:
+				return
+			case <-time.After(backoff):
+			}
+			var err error
+			conn, err = net.DialTimeout(b.network, b.address, dialTimeout)
+			if err == nil {
+				break
+			}
+			backoff = min(backoff*2, maxBackoff)
+		}
+		backoff = 100 * time.Millisecond
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			conn.Close()
+			return
+		}
+		b.conn = conn
+		topics := make([]string, 0, len(b.topics))
+		for topic := range b.topics {
+			topics = append(topics, topic)
+		}
+		reconnects := b.reconnects.Slice()
+		b.writeMu.Lock()
+		b.mu.Unlock()
+		for _, topic := range topics {
+			if err := writeMessage(conn, opSubscribe, topic, nil); err != nil {
+				break
+			}
+		}
+		b.writeMu.Unlock()
+		for _, f := range reconnects {
+			(*f)()
+		}
+	}
+}
+
+func (b *NetBroker) read(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		op, topic, data, err := readMessage(r)
+		if err != nil || op != opPublish {
+			return
+		}
+		b.mu.Lock()
+		for sub := range b.topics[topic] {
+			sub.push(data)
+		}
+		b.mu.Unlock()
+	}
+}
@@ -93,7 +93,8 @@ func TestInitDefaultsAndSolitaireConf(t *testing.T) {
 		solitaire.DisableReportStreaming != conf.SolitaireDisableReportStreaming ||
 		solitaire.ReportSize != conf.SolitaireReportLimit ||
 		solitaire.ReportTimeout != conf.SolitaireReportTimeout ||
-		solitaire.MaxRTT != conf.SolitaireMaxRTT {
+		solitaire.MaxRTT != conf.SolitaireMaxRTT ||
+		solitaire.WebSocket != conf.SolitaireWebSocket {
 		t.Fatal("expected solitaire transport config to mirror system config")
 	}
 
@@ -201,6 +202,73 @@ func TestCSPGenerateAndCollector(t *testing.T) {
 	}
 }
 
+func TestCSPNonceOrderAndReporting(t *testing.T) {
+	csp := &CSP{
+		ImgSources:     []string{"data:"},
+		FontSources:    []string{"https://fonts.example"},
+		WorkerSources:  []string{"'self'"},
+		ReportTo:       "csp",
+		ReportEndpoint: "/~/doors/c",
+		CollectReports: true,
+		Nonce:          true,
+	}
+	a, b := csp.NewCollector(), csp.NewCollector()
+	if a.Nonce() == "" || a.Nonce() == b.Nonce() {
+		t.Fatalf("expected distinct nonces, got %q and %q", a.Nonce(), b.Nonce())
+	}
+	out := a.Generate()
+	if other := strings.ReplaceAll(b.Generate(), b.Nonce(), a.Nonce()); other != out {
+		t.Fatalf("expected stable directive order:\n%s\n%s", out, other)
+	}
+	nonce := "'nonce-" + a.Nonce() + "'"
+	want := "default-src 'self'; connect-src 'self'; script-src 'self' " + nonce + "; style-src 'self' " + nonce +
+		"; form-action 'none'; object-src 'none'; frame-src 'none'; frame-ancestors 'none'; base-uri 'none'" +
+		"; img-src data:; font-src https://fonts.example; worker-src 'self'; report-to csp; report-uri /~/doors/c"
+	if out != want {
+		t.Fatalf("unexpected csp:\n%s\nwant:\n%s", out, want)
+	}
+	if got := a.ReportingEndpoints(); got != `csp="/~/doors/c"` {
+		t.Fatalf("unexpected reporting endpoints %q", got)
+	}
+	if got := (&CSP{ReportTo: "csp"}).NewCollector().ReportingEndpoints(); got != "" {
+		t.Fatalf("expected no reporting endpoints without an endpoint, got %q", got)
+	}
+	var nilCollector CSPCollector
+	if nilCollector.Nonce() != "" || nilCollector.ReportingEndpoints() != "" {
+		t.Fatal("expected nil collector to have no nonce and endpoints")
+	}
+}
+
+func TestParseCSPReports(t *testing.T) {
+	reports, err := ParseCSPReports("application/reports+json", []byte(`[
+		{"type":"csp-violation","user_agent":"test","body":{"documentURL":"https://app.example/","blockedURL":"inline","effectiveDirective":"script-src-elem","lineNumber":3,"disposition":"enforce","statusCode":200}},
+		{"type":"deprecation","body":{}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].BlockedURL != "inline" || reports[0].EffectiveDirective != "script-src-elem" ||
+		reports[0].LineNumber != 3 || reports[0].UserAgent != "test" || reports[0].StatusCode != 200 {
+		t.Fatalf("unexpected reports %+v", reports)
+	}
+	reports, err = ParseCSPReports("application/csp-report", []byte(`{"csp-report":{"document-uri":"https://app.example/","blocked-uri":"eval","violated-directive":"script-src","script-sample":"x"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].BlockedURL != "eval" || reports[0].EffectiveDirective != "script-src" || reports[0].Sample != "x" {
+		t.Fatalf("unexpected legacy reports %+v", reports)
+	}
+	for _, c := range [][2]string{
+		{"text/plain", `{}`},
+		{"application/csp-report", `{}`},
+		{"application/reports+json", `{`},
+	} {
+		if _, err := ParseCSPReports(c[0], []byte(c[1])); err != ErrCSPReport {
+			t.Fatalf("expected ErrCSPReport for %v, got %v", c, err)
+		}
+	}
+}
+
 func TestCollectionAndEncodingHelpers(t *testing.T) {
 	set := NewSet[string]()
 	if !set.IsEmpty() {
@@ -303,3 +371,56 @@ func TestPrimeIDAndEndCause(t *testing.T) {
 		t.Fatalf("unexpected end cause error: %q", got)
 	}
 }
+
+func TestMetricsHistogram(t *testing.T) {
+	m := &Metrics{}
+	m.Hook(500 * time.Microsecond)
+	m.Hook(30 * time.Millisecond)
+	m.Hook(time.Minute)
+	h := m.Hooks()
+	if h.Count != 3 || h.Sum != time.Minute+30*time.Millisecond+500*time.Microsecond {
+		t.Fatalf("unexpected histogram totals: %d %v", h.Count, h.Sum)
+	}
+	if h.Buckets[0] != 1 || h.Buckets[4] != 1 || h.Buckets[5] != 2 || h.Buckets[len(h.Buckets)-1] != 2 {
+		t.Fatalf("unexpected histogram buckets: %v", h.Buckets)
+	}
+
+	m.InstanceEnded(EndCauseSyncError)
+	m.SyncError(SyncErrorTimeout)
+	if m.Ends()[EndCauseSyncError] != 1 || m.SyncErrors()[SyncErrorTimeout] != 1 {
+		t.Fatalf("unexpected counters: %v %v", m.Ends(), m.SyncErrors())
+	}
+}
+
+func TestRateBucket(t *testing.T) {
+	limit := RateLimit{Every: time.Second, Burst: 2}
+	b := &RateBucket{}
+	now := time.Unix(1000, 0)
+	for i := range 2 {
+		if ok, _, _ := b.Take(limit, now); !ok {
+			t.Fatalf("expected burst request %d to pass", i)
+		}
+	}
+	ok, retry, rejected := b.Take(limit, now)
+	if ok || retry != time.Second || rejected != 1 {
+		t.Fatalf("unexpected over limit result: %v %v %d", ok, retry, rejected)
+	}
+	if _, _, rejected := b.Take(limit, now.Add(500*time.Millisecond)); rejected != 2 {
+		t.Fatalf("expected rejections in a row to add up, got %d", rejected)
+	}
+	if ok, _, _ := b.Take(limit, now.Add(time.Second)); !ok {
+		t.Fatal("expected refilled request to pass")
+	}
+	if ok, _, _ := b.Take(limit, now.Add(time.Second)); ok {
+		t.Fatal("expected bucket to be empty again")
+	}
+	if ok, _, _ := b.Take(RateLimit{}, now); !ok {
+		t.Fatal("expected zero limit to be unlimited")
+	}
+
+	m := &Metrics{}
+	m.HookLimited(RateLimitHook)
+	if m.Limited()[RateLimitHook] != 1 || RateLimitSession.String() != "session" {
+		t.Fatalf("unexpected limited counters: %v", m.Limited())
+	}
+}
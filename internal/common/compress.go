@@ -0,0 +1,229 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding is an HTTP content coding.
+type Encoding string
+
+const (
+	EncodingGzip   Encoding = "gzip"
+	EncodingBrotli Encoding = "br"
+	EncodingZstd   Encoding = "zstd"
+)
+
+// Compression configures one content coding Doors may respond with.
+type Compression struct {
+	// Encoding is the content coding.
+	Encoding Encoding
+	// Level is the compression level for page HTML and sync payloads, on the
+	// encoding's own scale (gzip 1-9, brotli 0-11, zstd 1-22).
+	// Default (value 0): gzip 6, brotli 5, zstd 3.
+	Level int
+	// StaticLevel is the compression level for JS, CSS, and other resources
+	// that are compressed once and cached.
+	// Default (value 0): gzip 9, brotli 11, zstd 19.
+	StaticLevel int
+}
+
+// DefaultCompression is the default encoding preference: brotli, zstd, then
+// gzip.
+func DefaultCompression() []Compression {
+	return []Compression{
+		{Encoding: EncodingBrotli},
+		{Encoding: EncodingZstd},
+		{Encoding: EncodingGzip},
+	}
+}
+
+func (c Compression) level(static bool) int {
+	if static && c.StaticLevel != 0 {
+		return c.StaticLevel
+	}
+	if !static && c.Level != 0 {
+		return c.Level
+	}
+	switch c.Encoding {
+	case EncodingBrotli:
+		if static {
+			return brotli.BestCompression
+		}
+		return 5
+	case EncodingZstd:
+		if static {
+			return 19
+		}
+		return 3
+	default:
+		if static {
+			return gzip.BestCompression
+		}
+		return gzip.DefaultCompression
+	}
+}
+
+// NegotiateCompression picks the encoding from prefs that the client accepts
+// with the highest q-value in acceptEncoding. Ties are resolved by the order
+// of prefs. It returns false when none of prefs is acceptable.
+func NegotiateCompression(acceptEncoding string, prefs []Compression) (Compression, bool) {
+	if acceptEncoding == "" || len(prefs) == 0 {
+		return Compression{}, false
+	}
+	accepted := make(map[string]float64)
+	wildcard := -1.0
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for param := range strings.SplitSeq(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				q = 0
+				break
+			}
+			q = parsed
+		}
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		accepted[name] = q
+	}
+	var best Compression
+	bestQ := 0.0
+	for _, pref := range prefs {
+		q, ok := accepted[string(pref.Encoding)]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best = pref
+			bestQ = q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// Compressor is a pooled streaming encoder.
+type Compressor interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(io.Writer)
+}
+
+type compressorKey struct {
+	encoding Encoding
+	level    int
+}
+
+// NegotiatePayloadCompression picks the encoding for solitaire sync payloads:
+// the first of prefs that is gzip or is listed in decoders, the
+// comma-separated encodings the client reported it can decode. Every client
+// decodes gzip, so it is the fallback when prefs has nothing else to offer.
+func NegotiatePayloadCompression(decoders string, prefs []Compression) Compression {
+	for _, pref := range prefs {
+		if pref.Encoding == EncodingGzip {
+			return pref
+		}
+		for name := range strings.SplitSeq(decoders, ",") {
+			if Encoding(strings.TrimSpace(name)) == pref.Encoding {
+				return pref
+			}
+		}
+	}
+	return Compression{Encoding: EncodingGzip}
+}
+
+var compressorPools sync.Map
+
+// GetCompressor returns a pooled encoder for c writing to w. static selects
+// [Compression.StaticLevel] over [Compression.Level]. Close the encoder, then
+// return it with [PutCompressor] using the same c and static.
+func GetCompressor(w io.Writer, c Compression, static bool) Compressor {
+	key := compressorKey{encoding: c.Encoding, level: c.level(static)}
+	pool, ok := compressorPools.Load(key)
+	if !ok {
+		pool, _ = compressorPools.LoadOrStore(key, &sync.Pool{
+			New: func() any {
+				return newCompressor(key)
+			},
+		})
+	}
+	cw := pool.(*sync.Pool).Get().(Compressor)
+	cw.Reset(w)
+	return cw
+}
+
+// PutCompressor returns an encoder obtained from [GetCompressor].
+func PutCompressor(cw Compressor, c Compression, static bool) {
+	cw.Reset(io.Discard)
+	key := compressorKey{encoding: c.Encoding, level: c.level(static)}
+	if pool, ok := compressorPools.Load(key); ok {
+		pool.(*sync.Pool).Put(cw)
+	}
+}
+
+func newCompressor(key compressorKey) Compressor {
+	switch key.encoding {
+	case EncodingBrotli:
+		return brotli.NewWriterLevel(io.Discard, key.level)
+	case EncodingZstd:
+		enc, err := zstd.NewWriter(io.Discard,
+			zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(key.level)),
+			zstd.WithEncoderConcurrency(1),
+		)
+		if err != nil {
+			panic(err)
+		}
+		return enc
+	case EncodingGzip:
+		gz, err := gzip.NewWriterLevel(io.Discard, key.level)
+		if err != nil {
+			gz = gzip.NewWriter(io.Discard)
+		}
+		return gz
+	default:
+		panic("unknown compression encoding " + strconv.Quote(string(key.encoding)))
+	}
+}
+
+// Compress encodes input in one shot using [Compression.StaticLevel].
+func Compress(input []byte, c Compression) []byte {
+	var buf bytes.Buffer
+	cw := GetCompressor(&buf, c, true)
+	defer PutCompressor(cw, c, true)
+	cw.Write(input)
+	cw.Close()
+	return buf.Bytes()
+}
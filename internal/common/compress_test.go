@@ -0,0 +1,129 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	prefs := DefaultCompression()
+	cases := []struct {
+		header string
+		want   Encoding
+		ok     bool
+	}{
+		{"", "", false},
+		{"gzip", EncodingGzip, true},
+		{"gzip, deflate, br, zstd", EncodingBrotli, true},
+		{"gzip;q=1.0, br;q=0.5", EncodingGzip, true},
+		{"br;q=0, zstd", EncodingZstd, true},
+		{"*;q=0.1, br;q=0", EncodingZstd, true},
+		{"identity", "", false},
+		{"gzip;q=0", "", false},
+	}
+	for _, c := range cases {
+		got, ok := NegotiateCompression(c.header, prefs)
+		if ok != c.ok || got.Encoding != c.want {
+			t.Fatalf("NegotiateCompression(%q) = %q, %v; want %q, %v", c.header, got.Encoding, ok, c.want, c.ok)
+		}
+	}
+	if _, ok := NegotiateCompression("gzip, br", nil); ok {
+		t.Fatal("expected no encoding when compression is disabled")
+	}
+}
+
+func TestNegotiatePayloadCompression(t *testing.T) {
+	prefs := []Compression{
+		{Encoding: EncodingBrotli, Level: 4},
+		{Encoding: EncodingZstd},
+		{Encoding: EncodingGzip, Level: 3},
+	}
+	cases := []struct {
+		decoders string
+		want     Compression
+	}{
+		{decoders: "br,zstd", want: prefs[0]},
+		{decoders: "zstd", want: prefs[1]},
+		{decoders: " zstd , br", want: prefs[0]},
+		{decoders: "", want: prefs[2]},
+		{decoders: "deflate", want: prefs[2]},
+	}
+	for _, tc := range cases {
+		if got := NegotiatePayloadCompression(tc.decoders, prefs); got != tc.want {
+			t.Fatalf("%q: expected %+v, got %+v", tc.decoders, tc.want, got)
+		}
+	}
+	if got := NegotiatePayloadCompression("", prefs[:2]); got.Encoding != EncodingGzip {
+		t.Fatalf("expected gzip fallback, got %q", got.Encoding)
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	input := bytes.Repeat([]byte("doors "), 1024)
+	readers := map[Encoding]func(io.Reader) (io.Reader, error){
+		EncodingBrotli: func(r io.Reader) (io.Reader, error) {
+			return brotli.NewReader(r), nil
+		},
+		EncodingZstd: func(r io.Reader) (io.Reader, error) {
+			return zstd.NewReader(r)
+		},
+	}
+	for encoding, newReader := range readers {
+		compressed := Compress(input, Compression{Encoding: encoding})
+		if len(compressed) >= len(input) {
+			t.Fatalf("%s: expected compressed output to be smaller", encoding)
+		}
+		r, err := newReader(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, input) {
+			t.Fatalf("%s: round trip mismatch", encoding)
+		}
+	}
+}
+
+func TestUnknownCompression(t *testing.T) {
+	expectPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s: expected panic for unknown encoding", name)
+			}
+		}()
+		f()
+	}
+	expectPanic("conf", func() {
+		InitDefaults(&Conf{ServerCompression: []Compression{{Encoding: "deflate"}}})
+	})
+	expectPanic("compressor", func() {
+		Compress([]byte("doors"), Compression{Encoding: "deflate"})
+	})
+	conf := &Conf{ServerCompression: []Compression{{Encoding: EncodingGzip}}}
+	InitDefaults(conf)
+	if len(conf.ServerCompression) != 1 {
+		t.Fatal("expected valid encodings to be kept")
+	}
+}
@@ -14,7 +14,10 @@
 
 package common
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 const DefaultCacheControl string = "public, max-age=31536000, immutable"
 
@@ -28,6 +31,14 @@ type Conf struct {
 	// SessionTTL controls how long session lives after last activity.
 	// Default behavior (value 0): InstanceTTL
 	SessionTTL time.Duration
+	// SessionHookRateLimit limits the hook requests of a session, across all
+	// its instances and hooks. Rejected requests get status 429.
+	// Default: unlimited.
+	SessionHookRateLimit RateLimit
+	// HookRateLimit is the default limit of each event, form, and hook attr
+	// that doesn't set its own RateLimit. Rejected requests get status 429.
+	// Default: unlimited.
+	HookRateLimit RateLimit
 	// InstanceConnectTimeout controls how long new instance waits
 	// before shutdown for the first client connection.
 	// Default: RequestTimeout
@@ -42,8 +53,24 @@ type Conf struct {
 	// resources prepared by the framework.
 	// Default "public, max-age=31536000, immutable"
 	ServerCacheControl string
-	// ServerDisableGzip disables gzip compression for HTML, JS, and CSS if true.
+	// ServerDisableGzip disables compression for HTML, JS, and CSS if true.
 	ServerDisableGzip bool
+	// ServerCompression lists the content encodings used for HTML, JS, and
+	// CSS, in order of preference. The encoding the client
+	// accepts with the highest q-value wins; ties follow this order.
+	// Only br, zstd, and gzip are accepted; other encodings panic when
+	// the configuration is applied. Default: brotli, zstd, gzip.
+	ServerCompression []Compression
+	// ServerResourceCacheLimit is the memory budget in bytes for cached JS,
+	// CSS, and static resources, their compressed variants included. Above
+	// it, the least recently used resources not held by a live door are
+	// evicted.
+	// Default: 256 MB.
+	ServerResourceCacheLimit int
+	// ServerResourceCacheEntries is the max number of cached resources,
+	// enforced the same way as ServerResourceCacheLimit.
+	// Default: 16384.
+	ServerResourceCacheEntries int
 	// ServerSessionCookiePrefix sets the internal Doors session cookie name prefix.
 	// Use it when you want browser-enforced cookie prefix rules, such as
 	// __Host- or __Secure-. Empty by default.
@@ -77,7 +104,9 @@ type Conf struct {
 	// server-to-client frame before forcing a sync flush.
 	// Default: 32 KB.
 	SolitaireFrameSize int
-	// SolitaireDisableGzip disables gzip compression for solitaire sync payloads if true.
+	// SolitaireDisableGzip disables compression for solitaire sync payloads if true.
+	// Otherwise payloads use the first ServerCompression encoding the client
+	// can decode, or gzip.
 	SolitaireDisableGzip bool
 	// SolitaireQueue is the max total queued plus unresolved
 	// server-to-client sync tasks. Exceeding this kills the instance.
@@ -100,6 +129,11 @@ type Conf struct {
 	// server has pending work but no frame ready to flush. Values below
 	// 2*SolitaireFrameTime are raised to that minimum. Default: 1s.
 	SolitaireMaxRTT time.Duration
+	// SolitaireWebSocket carries the sync stream and reports over a
+	// WebSocket, one connection per roll. Clients fall back to HTTP
+	// streaming when the socket can't be opened, for example behind a
+	// proxy without upgrade support. Default: false.
+	SolitaireWebSocket bool
 }
 
 type SolitaireConf struct {
@@ -125,6 +159,8 @@ type SolitaireConf struct {
 	ReportTimeout time.Duration
 	// MaxRTT is the effective RTT estimate cap after the 2*FlushTime minimum.
 	MaxRTT time.Duration
+	// WebSocket is the effective WebSocket transport flag.
+	WebSocket bool
 }
 
 func GetSolitaireConf(s *Conf) *SolitaireConf {
@@ -140,6 +176,7 @@ func GetSolitaireConf(s *Conf) *SolitaireConf {
 		ReportSize:             s.SolitaireReportLimit,
 		ReportTimeout:          s.SolitaireReportTimeout,
 		MaxRTT:                 s.SolitaireMaxRTT,
+		WebSocket:              s.SolitaireWebSocket,
 	}
 }
 
@@ -177,6 +214,15 @@ func (s *Conf) solitaireDefaults() {
 	}
 }
 
+// Compression returns the effective encoding preference, or nil when
+// compression is disabled.
+func (s *Conf) Compression() []Compression {
+	if s.ServerDisableGzip {
+		return nil
+	}
+	return s.ServerCompression
+}
+
 // InitDefaults fills zero or invalid values in s with Doors defaults.
 func InitDefaults(s *Conf) {
 	if s.RequestTimeout <= 0 {
@@ -206,6 +252,22 @@ func InitDefaults(s *Conf) {
 	if s.SessionTTL <= s.InstanceTTL {
 		s.SessionTTL = s.InstanceTTL
 	}
+	if s.ServerResourceCacheLimit <= 0 {
+		s.ServerResourceCacheLimit = 256 * 1024 * 1024
+	}
+	if s.ServerResourceCacheEntries <= 0 {
+		s.ServerResourceCacheEntries = 16384
+	}
+	if s.ServerCompression == nil {
+		s.ServerCompression = DefaultCompression()
+	}
+	for _, c := range s.ServerCompression {
+		switch c.Encoding {
+		case EncodingBrotli, EncodingZstd, EncodingGzip:
+		default:
+			panic("unsupported server compression encoding " + strconv.Quote(string(c.Encoding)))
+		}
+	}
 	if s.ServerRequestBodyLimit <= 0 {
 		s.ServerRequestBodyLimit = 8 * 1024 * 1024
 	}
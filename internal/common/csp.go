@@ -15,8 +15,10 @@
 package common
 
 import (
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -109,9 +111,36 @@ type CSP struct {
 	// report-to:
 	//   ""  ⇒ omit
 	//   set ⇒ emit: report-to <value>
-	// NOTE: To make this effective, you must also send a corresponding
-	//       `Report-To` HTTP response header that defines the reporting group.
+	// Set ReportEndpoint, or CollectReports, to also send the matching
+	// Reporting-Endpoints header.
 	ReportTo string
+
+	// ReportEndpoint is the URL reports for the ReportTo group are sent to.
+	// When both are set, Doors sends the header:
+	//   Reporting-Endpoints: <ReportTo>="<ReportEndpoint>"
+	ReportEndpoint string
+
+	// CollectReports enables the built-in report endpoint. It sets
+	// ReportEndpoint to a Doors path, ReportTo to "doors-csp" if empty, and
+	// also emits report-uri for browsers without Reporting API support.
+	// Reports go to OnReport, or are logged at warn level if it is nil.
+	CollectReports bool
+
+	// OnReport receives violation reports collected by the built-in
+	// endpoint. r is the report request; its body is already read.
+	OnReport func(r *http.Request, report CSPReport)
+
+	// ReportRateLimit limits the report requests the built-in endpoint
+	// accepts, across all clients. Requests over it get status 429 and are
+	// counted in metrics instead of being read.
+	// Default (zero value): one every 100ms, with a burst of 100.
+	// A negative Every accepts every report.
+	ReportRateLimit RateLimit
+
+	// Nonce generates a nonce for every page response, adds it to
+	// script-src and style-src, and sets it on every script, style, and
+	// stylesheet link Doors renders for the page, including later updates.
+	Nonce bool
 }
 
 type collectedCSP struct {
@@ -132,10 +161,29 @@ type CSPCollector = *cspCollector
 // [CSP] during one response.
 type cspCollector struct {
 	csp     *CSP
+	nonce   string
 	styles  *collectedCSP
 	scripts *collectedCSP
 }
 
+// Nonce returns the nonce of the response, or "" if the [CSP] doesn't use
+// nonces.
+func (c CSPCollector) Nonce() string {
+	if c == nil {
+		return ""
+	}
+	return c.nonce
+}
+
+// ReportingEndpoints returns the Reporting-Endpoints header value, or "" if
+// no endpoint is configured.
+func (c CSPCollector) ReportingEndpoints() string {
+	if c == nil || c.csp.ReportTo == "" || c.csp.ReportEndpoint == "" {
+		return ""
+	}
+	return c.csp.ReportTo + "=" + `"` + c.csp.ReportEndpoint + `"`
+}
+
 // StyleSource records a dynamic stylesheet source for the final CSP header.
 func (c CSPCollector) StyleSource(source string) {
 	if c == nil {
@@ -170,7 +218,7 @@ func (c CSPCollector) ScriptHash(hash []byte) {
 
 // Generate returns the final Content-Security-Policy header value.
 func (c CSPCollector) Generate() string {
-	return c.csp.generate(c.styles, c.scripts)
+	return c.csp.generate(c.nonce, c.styles, c.scripts)
 }
 
 // NewCollector creates a per-response collector for c.
@@ -178,52 +226,58 @@ func (c *CSP) NewCollector() CSPCollector {
 	if c == nil {
 		return nil
 	}
-	return &cspCollector{
+	collector := &cspCollector{
 		csp:     c,
 		styles:  newCollectedCSP(),
 		scripts: newCollectedCSP(),
 	}
+	if c.Nonce {
+		collector.nonce = newNonce()
+	}
+	return collector
+}
+
+func newNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
 }
 
-func (c *CSP) generate(styleCollected *collectedCSP, scriptCollected *collectedCSP) string {
-	def := c.simple("default-src", nil, c.DefaultSources, []string{"'self'"})
-	connect := c.simple("connect-src", []string{"'self'"}, c.ConnectSources, nil)
-	script := c.collected("script-src", scriptCollected, c.ScriptSources)
+func (c *CSP) generate(nonce string, styleCollected *collectedCSP, scriptCollected *collectedCSP) string {
+	script := c.collected("script-src", nonce, scriptCollected, c.ScriptSources)
 	if c.ScriptStrictDynamic {
 		script = script + " " + "'strict-dynamic'"
 	}
-	style := c.collected("style-src", styleCollected, c.StyleSources)
-	allow := map[string][]string{
-		"form-action":     c.FormActions,
-		"object-src":      c.ObjectSources,
-		"frame-src":       c.FrameSources,
-		"frame-ancestors": c.FrameAncestors,
-		"base-uri":        c.BaseURIAllow,
-	}
-	parts := []string{def, connect, script, style}
-	for directive := range allow {
-		value := c.simple(directive, nil, allow[directive], []string{"'none'"})
-		parts = append(parts, value)
-	}
-	optional := map[string][]string{
-		"img-src":    c.ImgSources,
-		"font-src":   c.FontSources,
-		"media-src":  c.MediaSources,
-		"sandbox":    c.Sandbox,
-		"worker-src": c.WorkerSources,
-	}
-	for directive := range optional {
-		value := c.simple(directive, nil, optional[directive], nil)
-		parts = append(parts, value)
+	parts := []string{
+		c.simple("default-src", nil, c.DefaultSources, []string{"'self'"}),
+		c.simple("connect-src", []string{"'self'"}, c.ConnectSources, nil),
+		script,
+		c.collected("style-src", nonce, styleCollected, c.StyleSources),
+		c.simple("form-action", nil, c.FormActions, []string{"'none'"}),
+		c.simple("object-src", nil, c.ObjectSources, []string{"'none'"}),
+		c.simple("frame-src", nil, c.FrameSources, []string{"'none'"}),
+		c.simple("frame-ancestors", nil, c.FrameAncestors, []string{"'none'"}),
+		c.simple("base-uri", nil, c.BaseURIAllow, []string{"'none'"}),
+		c.simple("img-src", nil, c.ImgSources, nil),
+		c.simple("font-src", nil, c.FontSources, nil),
+		c.simple("media-src", nil, c.MediaSources, nil),
+		c.simple("sandbox", nil, c.Sandbox, nil),
+		c.simple("worker-src", nil, c.WorkerSources, nil),
 	}
 	if c.ReportTo != "" {
 		parts = append(parts, "report-to "+c.ReportTo)
 	}
+	if c.CollectReports && c.ReportEndpoint != "" {
+		parts = append(parts, "report-uri "+c.ReportEndpoint)
+	}
 	return c.join(parts)
 }
 
-func (c *CSP) collected(directive string, collected *collectedCSP, user []string) string {
+func (c *CSP) collected(directive string, nonce string, collected *collectedCSP, user []string) string {
 	parts := []string{"'self'"}
+	if nonce != "" {
+		parts = append(parts, "'nonce-"+nonce+"'")
+	}
 	for _, hash := range collected.hashes {
 		value := fmt.Sprintf("'sha256-%s'", base64.StdEncoding.EncodeToString(hash))
 		parts = append(parts, value)
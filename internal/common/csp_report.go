@@ -0,0 +1,148 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+)
+
+// CSPReport is a Content-Security-Policy violation reported by a browser.
+type CSPReport struct {
+	// DocumentURL is the page the violation happened on.
+	DocumentURL string
+	// Referrer is the referrer of the page.
+	Referrer string
+	// BlockedURL is the blocked resource, or "inline" or "eval".
+	BlockedURL string
+	// EffectiveDirective is the directive that was violated.
+	EffectiveDirective string
+	// OriginalPolicy is the policy as sent in the header.
+	OriginalPolicy string
+	// SourceFile, LineNumber, and ColumnNumber locate the violation.
+	SourceFile   string
+	LineNumber   int
+	ColumnNumber int
+	// Sample is the start of the blocked inline code, if the policy asks
+	// for samples.
+	Sample string
+	// Disposition is "enforce" or "report".
+	Disposition string
+	// StatusCode is the HTTP status of the page.
+	StatusCode int
+	// UserAgent is the browser that sent the report.
+	UserAgent string
+}
+
+// ErrCSPReport is returned for bodies that are not CSP violation reports.
+var ErrCSPReport = errors.New("malformed csp report")
+
+// reportingBody is the body of a Reporting API csp-violation report.
+type reportingBody struct {
+	DocumentURL        string `json:"documentURL"`
+	Referrer           string `json:"referrer"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	OriginalPolicy     string `json:"originalPolicy"`
+	SourceFile         string `json:"sourceFile"`
+	LineNumber         int    `json:"lineNumber"`
+	ColumnNumber       int    `json:"columnNumber"`
+	Sample             string `json:"sample"`
+	Disposition        string `json:"disposition"`
+	StatusCode         int    `json:"statusCode"`
+}
+
+// legacyReport is the body sent to report-uri endpoints.
+type legacyReport struct {
+	Report *struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		BlockedURI         string `json:"blocked-uri"`
+		EffectiveDirective string `json:"effective-directive"`
+		ViolatedDirective  string `json:"violated-directive"`
+		OriginalPolicy     string `json:"original-policy"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+		ColumnNumber       int    `json:"column-number"`
+		ScriptSample       string `json:"script-sample"`
+		Disposition        string `json:"disposition"`
+		StatusCode         int    `json:"status-code"`
+	} `json:"csp-report"`
+}
+
+// ParseCSPReports decodes a report request body. It accepts the Reporting
+// API format (application/reports+json), where reports of other types are
+// skipped, and the legacy report-uri format (application/csp-report).
+func ParseCSPReports(contentType string, body []byte) ([]CSPReport, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/reports+json":
+		var reports []struct {
+			Type      string        `json:"type"`
+			UserAgent string        `json:"user_agent"`
+			Body      reportingBody `json:"body"`
+		}
+		if err := json.Unmarshal(body, &reports); err != nil {
+			return nil, ErrCSPReport
+		}
+		out := make([]CSPReport, 0, len(reports))
+		for _, r := range reports {
+			if r.Type != "csp-violation" {
+				continue
+			}
+			out = append(out, CSPReport{
+				DocumentURL:        r.Body.DocumentURL,
+				Referrer:           r.Body.Referrer,
+				BlockedURL:         r.Body.BlockedURL,
+				EffectiveDirective: r.Body.EffectiveDirective,
+				OriginalPolicy:     r.Body.OriginalPolicy,
+				SourceFile:         r.Body.SourceFile,
+				LineNumber:         r.Body.LineNumber,
+				ColumnNumber:       r.Body.ColumnNumber,
+				Sample:             r.Body.Sample,
+				Disposition:        r.Body.Disposition,
+				StatusCode:         r.Body.StatusCode,
+				UserAgent:          r.UserAgent,
+			})
+		}
+		return out, nil
+	case "application/csp-report", "application/json":
+		var legacy legacyReport
+		if err := json.Unmarshal(body, &legacy); err != nil || legacy.Report == nil {
+			return nil, ErrCSPReport
+		}
+		r := legacy.Report
+		directive := r.EffectiveDirective
+		if directive == "" {
+			directive = r.ViolatedDirective
+		}
+		return []CSPReport{{
+			DocumentURL:        r.DocumentURI,
+			Referrer:           r.Referrer,
+			BlockedURL:         r.BlockedURI,
+			EffectiveDirective: directive,
+			OriginalPolicy:     r.OriginalPolicy,
+			SourceFile:         r.SourceFile,
+			LineNumber:         r.LineNumber,
+			ColumnNumber:       r.ColumnNumber,
+			Sample:             r.ScriptSample,
+			Disposition:        r.Disposition,
+			StatusCode:         r.StatusCode,
+		}}, nil
+	default:
+		return nil, ErrCSPReport
+	}
+}
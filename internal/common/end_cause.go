@@ -27,3 +27,14 @@ const (
 func (c EndCause) Error() string {
 	return fmt.Sprint("cause: ", int(c))
 }
+
+func (c EndCause) String() string {
+	switch c {
+	case EndCauseKilled:
+		return "killed"
+	case EndCauseSuspend:
+		return "suspend"
+	default:
+		return "sync_error"
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SyncErrorReason classifies errors that end an instance with
+// [EndCauseSyncError].
+type SyncErrorReason int
+
+const (
+	// SyncErrorTimeout means the client did not report a call in time.
+	SyncErrorTimeout SyncErrorReason = iota
+	// SyncErrorQueueLimit means too many calls were waiting for the client.
+	SyncErrorQueueLimit
+	// SyncErrorReportSize means a client report exceeded the size limit.
+	SyncErrorReportSize
+	// SyncErrorReport means a client report didn't match the sent calls.
+	SyncErrorReport
+	syncErrorReasons
+)
+
+func (r SyncErrorReason) String() string {
+	switch r {
+	case SyncErrorTimeout:
+		return "timeout"
+	case SyncErrorQueueLimit:
+		return "queue_limit"
+	case SyncErrorReportSize:
+		return "report_size"
+	default:
+		return "report"
+	}
+}
+
+const endCauses = int(EndCauseSyncError) + 1
+
+// Metrics holds the runtime counters of an app. The zero value is ready
+// to use and all methods are safe for concurrent use.
+type Metrics struct {
+	ends       [endCauses]atomic.Uint64
+	syncErrors [syncErrorReasons]atomic.Uint64
+	rejected   [requestViolations]atomic.Uint64
+	limited    [rateLimitScopes]atomic.Uint64
+	cspDropped atomic.Uint64
+	flushes    atomic.Uint64
+	frameBytes atomic.Uint64
+	hooks      Histogram
+	rtt        Histogram
+}
+
+// InstanceEnded counts an instance that ended with cause.
+func (m *Metrics) InstanceEnded(cause EndCause) {
+	m.ends[cause].Add(1)
+}
+
+// SyncError counts a synchronization error.
+func (m *Metrics) SyncError(reason SyncErrorReason) {
+	m.syncErrors[reason].Add(1)
+}
+
+// RequestRejected counts a request rejected by the [RequestPolicy].
+func (m *Metrics) RequestRejected(reason RequestViolation) {
+	m.rejected[reason].Add(1)
+}
+
+// HookLimited counts a hook request rejected by a [RateLimit].
+func (m *Metrics) HookLimited(scope RateLimitScope) {
+	m.limited[scope].Add(1)
+}
+
+// CSPReportDropped counts a CSP report request rejected by the report rate
+// limit.
+func (m *Metrics) CSPReportDropped() {
+	m.cspDropped.Add(1)
+}
+
+// Flush counts a frame of size bytes written to a sync stream.
+func (m *Metrics) Flush(size int) {
+	m.flushes.Add(1)
+	m.frameBytes.Add(uint64(size))
+}
+
+// Hook records the time a hook handler took.
+func (m *Metrics) Hook(d time.Duration) {
+	m.hooks.Observe(d)
+}
+
+// RTT records a round trip time estimate of a sync connection.
+func (m *Metrics) RTT(d time.Duration) {
+	m.rtt.Observe(d)
+}
+
+// Ends returns the number of ended instances, indexed by [EndCause].
+func (m *Metrics) Ends() []uint64 {
+	ends := make([]uint64, endCauses)
+	for i := range m.ends {
+		ends[i] = m.ends[i].Load()
+	}
+	return ends
+}
+
+// SyncErrors returns the number of synchronization errors, indexed by
+// [SyncErrorReason].
+func (m *Metrics) SyncErrors() []uint64 {
+	errs := make([]uint64, syncErrorReasons)
+	for i := range m.syncErrors {
+		errs[i] = m.syncErrors[i].Load()
+	}
+	return errs
+}
+
+// Rejected returns the number of rejected requests, indexed by
+// [RequestViolation].
+func (m *Metrics) Rejected() []uint64 {
+	rejected := make([]uint64, requestViolations)
+	for i := range m.rejected {
+		rejected[i] = m.rejected[i].Load()
+	}
+	return rejected
+}
+
+// Limited returns the number of rate limited hook requests, indexed by
+// [RateLimitScope].
+func (m *Metrics) Limited() []uint64 {
+	limited := make([]uint64, rateLimitScopes)
+	for i := range m.limited {
+		limited[i] = m.limited[i].Load()
+	}
+	return limited
+}
+
+// CSPReportsDropped returns the number of CSP report requests rejected by
+// the report rate limit.
+func (m *Metrics) CSPReportsDropped() uint64 {
+	return m.cspDropped.Load()
+}
+
+// Flushes returns the number of frames and their total size in bytes.
+func (m *Metrics) Flushes() (frames uint64, bytes uint64) {
+	return m.flushes.Load(), m.frameBytes.Load()
+}
+
+// Hooks returns the hook latency histogram.
+func (m *Metrics) Hooks() HistogramSnapshot {
+	return m.hooks.Snapshot()
+}
+
+// RTTs returns the round trip time histogram.
+func (m *Metrics) RTTs() HistogramSnapshot {
+	return m.rtt.Snapshot()
+}
+
+// DurationBuckets are the upper bounds, in seconds, of [Histogram] buckets.
+var DurationBuckets = [...]float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram counts durations in [DurationBuckets].
+type Histogram struct {
+	counts [len(DurationBuckets) + 1]atomic.Uint64
+	sum    atomic.Int64
+}
+
+func (h *Histogram) Observe(d time.Duration) {
+	s := d.Seconds()
+	i := 0
+	for i < len(DurationBuckets) && s > DurationBuckets[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+// HistogramSnapshot holds cumulative bucket counts, matching
+// [DurationBuckets], plus the overall count and sum.
+type HistogramSnapshot struct {
+	Buckets []uint64
+	Count   uint64
+	Sum     time.Duration
+}
+
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Buckets: make([]uint64, len(DurationBuckets)),
+	}
+	for i := range h.counts {
+		s.Count += h.counts[i].Load()
+		if i < len(DurationBuckets) {
+			s.Buckets[i] = s.Count
+		}
+	}
+	s.Sum = time.Duration(h.sum.Load())
+	return s
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TokenHeader carries the instance token on hook, sync, and undo requests.
+const TokenHeader = "D0-Token"
+
+// TokenParam carries the instance token on WebSocket handshakes, which
+// can't set headers from the browser.
+const TokenParam = "k"
+
+// RequestPolicy configures the checks Doors runs on hook, sync, and undo
+// requests before handing them to the page instance.
+type RequestPolicy struct {
+	// Origins lists origins, such as "https://admin.example.com", that may
+	// call the app besides its own. Requests the browser marks as
+	// same-origin are always allowed.
+	Origins []string
+
+	// RequireToken issues a secret token to every page instance at render
+	// and rejects requests that don't carry it.
+	RequireToken bool
+}
+
+// RequestViolation classifies requests rejected by a [RequestPolicy].
+type RequestViolation int
+
+const (
+	// RequestViolationOrigin means the Origin header didn't match the app
+	// host or the allowed origins.
+	RequestViolationOrigin RequestViolation = iota
+	// RequestViolationFetchSite means Sec-Fetch-Site marked a request
+	// without an Origin header as same-site or cross-site.
+	RequestViolationFetchSite
+	// RequestViolationToken means the instance token was missing or wrong.
+	RequestViolationToken
+	requestViolations
+)
+
+func (v RequestViolation) String() string {
+	switch v {
+	case RequestViolationOrigin:
+		return "origin"
+	case RequestViolationFetchSite:
+		return "fetch_site"
+	default:
+		return "token"
+	}
+}
+
+// Check inspects the fetch metadata and Origin headers of r. Requests
+// without either, such as ones made by non-browser clients, pass.
+func (p *RequestPolicy) Check(r *http.Request) (RequestViolation, bool) {
+	site := r.Header.Get("Sec-Fetch-Site")
+	if site == "same-origin" || site == "none" {
+		return 0, true
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !p.allowOrigin(origin, r.Host) {
+			return RequestViolationOrigin, false
+		}
+		return 0, true
+	}
+	if site != "" {
+		return RequestViolationFetchSite, false
+	}
+	return 0, true
+}
+
+func (p *RequestPolicy) allowOrigin(origin string, host string) bool {
+	if u, err := url.Parse(origin); err == nil && u.Host != "" && strings.EqualFold(u.Host, host) {
+		return true
+	}
+	for _, allowed := range p.Origins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWebSocket reports whether r is a WebSocket opening handshake.
+func IsWebSocket(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		headerHasToken(r.Header, "Connection", "upgrade")
+}
+
+func headerHasToken(h http.Header, name string, token string) bool {
+	for _, v := range h.Values(name) {
+		for t := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequestToken returns the instance token sent with r.
+func RequestToken(r *http.Request) string {
+	if token := r.Header.Get(TokenHeader); token != "" {
+		return token
+	}
+	if r.Header.Get("Upgrade") != "" {
+		return r.URL.Query().Get(TokenParam)
+	}
+	return ""
+}
+
+// EqualToken compares tokens in constant time.
+func EqualToken(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket limit on hook requests: up to Burst requests
+// at once, with one more allowed every Every. The zero value is unlimited.
+type RateLimit struct {
+	// Every is the time it takes to regain one request.
+	Every time.Duration
+	// Burst is the number of requests allowed back to back. Default: 1.
+	Burst int
+}
+
+// Enabled reports whether the limit applies.
+func (l RateLimit) Enabled() bool {
+	return l.Every > 0
+}
+
+func (l RateLimit) burst() time.Duration {
+	return l.Every * time.Duration(max(l.Burst, 1))
+}
+
+// RateLimitScope tells which bucket rejected a hook request.
+type RateLimitScope int
+
+const (
+	// RateLimitSession is the bucket shared by all hooks of a session.
+	RateLimitSession RateLimitScope = iota
+	// RateLimitHook is the bucket of a single hook.
+	RateLimitHook
+	rateLimitScopes
+)
+
+func (s RateLimitScope) String() string {
+	if s == RateLimitSession {
+		return "session"
+	}
+	return "hook"
+}
+
+// HookAbuse describes a hook request rejected by a [RateLimit].
+type HookAbuse struct {
+	// Scope is the bucket that rejected the request.
+	Scope RateLimitScope
+	// InstanceID is the page instance the hook belongs to.
+	InstanceID string
+	// HookID is the rejected hook. It is zero for the session bucket.
+	HookID uint64
+	// Rejected is the number of requests rejected in a row by the bucket.
+	Rejected int
+	// RetryAfter is when the bucket allows the next request.
+	RetryAfter time.Duration
+	// Request is the rejected request. Its body is not read.
+	Request *http.Request
+}
+
+// RateBucket tracks a [RateLimit]. The zero value is a full bucket and it is
+// safe for concurrent use.
+type RateBucket struct {
+	mu       sync.Mutex
+	tat      time.Time
+	rejected int
+}
+
+// Take spends one request. When the limit is exceeded, it returns false,
+// the time until the next request is allowed, and the number of requests
+// rejected in a row.
+func (b *RateBucket) Take(l RateLimit, now time.Time) (bool, time.Duration, int) {
+	if !l.Enabled() {
+		return true, 0, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tat := b.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(l.Every)
+	if wait := next.Sub(now) - l.burst(); wait > 0 {
+		b.rejected += 1
+		return false, wait, b.rejected
+	}
+	b.tat = next
+	b.rejected = 0
+	return true, 0, 0
+}
+
+// WriteRateLimited responds with 429 and a Retry-After header.
+func WriteRateLimited(w http.ResponseWriter, retry time.Duration) {
+	seconds := int(math.Ceil(retry.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	w.WriteHeader(http.StatusTooManyRequests)
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "net/http"
+
+// SecurityHeaderOmit as a [SecurityHeaders] field value drops the header.
+const SecurityHeaderOmit = "-"
+
+// SecurityHeaders configures the security headers Doors sends. An empty
+// field uses the default noted on it, or sends nothing if it has none;
+// [SecurityHeaderOmit] drops the header.
+type SecurityHeaders struct {
+	// Strict-Transport-Security. Not sent unless set, because browsers
+	// then refuse plain HTTP for the host until max-age runs out. For
+	// example "max-age=63072000"; add includeSubDomains only when every
+	// subdomain serves HTTPS.
+	StrictTransportSecurity string
+
+	// Referrer-Policy. Default: "strict-origin-when-cross-origin".
+	ReferrerPolicy string
+
+	// X-Content-Type-Options. Default: "nosniff".
+	ContentTypeOptions string
+
+	// Cross-Origin-Opener-Policy. Default: "same-origin".
+	CrossOriginOpenerPolicy string
+
+	// Cross-Origin-Resource-Policy. It is sent on static files too, such
+	// as resources and files served with UseFS, so other origins can't
+	// embed them. Default: "same-origin".
+	CrossOriginResourcePolicy string
+
+	// Permissions-Policy.
+	// Default: "camera=(), microphone=(), geolocation=(), payment=(), usb=()".
+	PermissionsPolicy string
+}
+
+func (s *SecurityHeaders) headers() [6][3]string {
+	return [...][3]string{
+		{"Strict-Transport-Security", s.StrictTransportSecurity, ""},
+		{"Referrer-Policy", s.ReferrerPolicy, "strict-origin-when-cross-origin"},
+		{"X-Content-Type-Options", s.ContentTypeOptions, "nosniff"},
+		{"Cross-Origin-Opener-Policy", s.CrossOriginOpenerPolicy, "same-origin"},
+		{"Cross-Origin-Resource-Policy", s.CrossOriginResourcePolicy, "same-origin"},
+		{"Permissions-Policy", s.PermissionsPolicy, "camera=(), microphone=(), geolocation=(), payment=(), usb=()"},
+	}
+}
+
+// Apply sets the headers on h, using defaults for empty fields.
+func (s *SecurityHeaders) Apply(h http.Header) {
+	if s == nil {
+		return
+	}
+	for _, header := range s.headers() {
+		switch header[1] {
+		case SecurityHeaderOmit:
+			h.Del(header[0])
+		case "":
+			if header[2] != "" {
+				h.Set(header[0], header[2])
+			}
+		default:
+			h.Set(header[0], header[1])
+		}
+	}
+}
+
+// Override sets the non-empty fields on h, leaving the other headers as
+// they are.
+func (s *SecurityHeaders) Override(h http.Header) {
+	if s == nil {
+		return
+	}
+	for _, header := range s.headers() {
+		switch header[1] {
+		case SecurityHeaderOmit:
+			h.Del(header[0])
+		case "":
+		default:
+			h.Set(header[0], header[1])
+		}
+	}
+}
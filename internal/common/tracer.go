@@ -0,0 +1,70 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Tracer starts spans around runtime work: hook handlers, door renders,
+// beam propagation, and sync flushes.
+type Tracer interface {
+	// Start begins a span named name as a child of the span in ctx, if any.
+	// The returned span must be ended.
+	Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+}
+
+// Span is a timed unit of work started by a [Tracer].
+type Span interface {
+	// SetAttrs adds attributes to the span.
+	SetAttrs(attrs ...slog.Attr)
+	// End finishes the span. err is the failure of the traced work, or nil.
+	End(err error)
+}
+
+// Span names.
+const (
+	SpanHook       = "doors.hook"
+	SpanDoorRender = "doors.door.render"
+	SpanBeamSync   = "doors.beam.sync"
+	SpanSyncFlush  = "doors.sync.flush"
+)
+
+// Span attribute keys.
+const (
+	TraceSessionID    = "session_id"
+	TraceInstanceID   = "instance_id"
+	TraceDoorID       = "door_id"
+	TraceHookID       = "hook_id"
+	TraceFound        = "found"
+	TraceMode         = "mode"
+	TraceWatchers     = "watchers"
+	TraceCalls        = "calls"
+	TracePayloadBytes = "payload_bytes"
+)
+
+// NoopTracer starts spans that record nothing.
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttrs(attrs ...slog.Attr) {}
+
+func (noopSpan) End(err error) {}
@@ -17,6 +17,7 @@ package core
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"time"
@@ -46,6 +47,9 @@ type App interface {
 	ResourceRegistry() resources.Registry
 	Conf() *common.Conf
 	Draining() bool
+	Tracer() common.Tracer
+	HookLimited(ctx context.Context, abuse common.HookAbuse)
+	WriteMetrics(w io.Writer) error
 }
 
 type Session interface {
@@ -56,6 +60,8 @@ type Session interface {
 	LastSeen() time.Time
 	Context() context.Context
 	Store() ctex.Store
+	Tag(key string, value string)
+	Tags() map[string]string
 	Kill()
 }
 
@@ -63,6 +69,9 @@ type TitleMeta interface {
 	gox.Editor
 	UpdateTitle(value string, attrs gox.Attrs) context.CancelFunc
 	UpdateMeta(prop bool, name string, attrs gox.Attrs) context.CancelFunc
+	UpdateLang(lang string) context.CancelFunc
+	UpdateAlternate(hreflang string, attrs gox.Attrs) context.CancelFunc
+	Lang() string
 }
 
 type Instance interface {
@@ -71,6 +80,8 @@ type Instance interface {
 	Store() ctex.Store
 	UserCall(ctx context.Context, check func() bool, action action.Action, onResult func(json.RawMessage, error), onCancel func(), params action.CallParams)
 	CSPCollector() common.CSPCollector
+	CSPNonce() string
+	Token() string
 	ModuleRegistry() ModuleRegistry
 	ID() string
 	LastSeen() time.Time
@@ -78,9 +89,12 @@ type Instance interface {
 	NewID() uint64
 	Runtime() shredder.Runtime
 	SetStatus(int)
+	SetSecurityHeaders(common.SecurityHeaders)
+	Redirect(url string, status int) bool
 	Location() beam.Source[path.Location]
 	Kill()
 	TitleMeta() TitleMeta
+	PayloadCompression() common.Compression
 }
 
 type Door interface {
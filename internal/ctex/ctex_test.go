@@ -16,6 +16,7 @@ package ctex
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
 	"sync/atomic"
 	"testing"
@@ -253,3 +254,46 @@ func TestLogCanceled(t *testing.T) {
 	cancel()
 	LogCanceled(ctx, "update")
 }
+
+type testPersistentKey string
+
+func (k testPersistentKey) PersistentName() string {
+	return string(k)
+}
+
+func (k testPersistentKey) Decode(data []byte) (any, error) {
+	var v string
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+func TestPersistentStore(t *testing.T) {
+	var changes atomic.Int32
+	store := NewPersistentStore(func() {
+		changes.Add(1)
+	})
+	store.Save("plain", "skip")
+	store.Save(testPersistentKey("theme"), "dark")
+	if changes.Load() != 1 {
+		t.Fatalf("expected one change notification, got %d", changes.Load())
+	}
+	data, err := store.Persistent()
+	if err != nil {
+		t.Fatalf("unexpected persistence error: %v", err)
+	}
+	if len(data) != 1 || string(data["theme"]) != `"dark"` {
+		t.Fatalf("unexpected persisted data: %v", data)
+	}
+
+	restored := NewPersistentStore(nil)
+	restored.Restore(data)
+	if got, _ := restored.Persistent(); string(got["theme"]) != `"dark"` {
+		t.Fatalf("expected pending value to stay persisted, got %v", got)
+	}
+	if got := restored.Load(testPersistentKey("theme")); got != "dark" {
+		t.Fatalf("unexpected restored value: %#v", got)
+	}
+	if got := restored.Init(testPersistentKey("theme"), func() any { return "light" }); got != "dark" {
+		t.Fatalf("expected Init to return restored value, got %#v", got)
+	}
+}
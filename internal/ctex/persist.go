@@ -0,0 +1,141 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// PersistentKey is a store key whose value survives process restarts when
+// the store is persistent. Values are serialized with encoding/json.
+type PersistentKey interface {
+	// PersistentName returns the stable name the value is saved under.
+	PersistentName() string
+	// Decode restores a value previously serialized for this key.
+	Decode(data []byte) (any, error)
+}
+
+// NewPersistentStore creates a [Store] that tracks values saved under
+// [PersistentKey] keys. changed is called after such a value is created,
+// replaced, or removed.
+func NewPersistentStore(changed func()) Store {
+	return &store{
+		persist: &persistence{
+			changed: changed,
+		},
+	}
+}
+
+type persistence struct {
+	mu      sync.Mutex
+	pending map[string]json.RawMessage
+	changed func()
+}
+
+// Restore seeds the store with serialized values. Each value is decoded
+// lazily on first access through its key; values that fail to decode are
+// dropped.
+func (s Store) Restore(data map[string]json.RawMessage) {
+	if s.persist == nil || len(data) == 0 {
+		return
+	}
+	s.persist.mu.Lock()
+	defer s.persist.mu.Unlock()
+	if s.persist.pending == nil {
+		s.persist.pending = make(map[string]json.RawMessage, len(data))
+	}
+	for name, value := range data {
+		s.persist.pending[name] = value
+	}
+}
+
+// Persistent serializes values stored under persistent keys, including
+// restored values that were not accessed yet. Values still being created by
+// [Store.Init] are skipped.
+func (s Store) Persistent() (map[string]json.RawMessage, error) {
+	if s.persist == nil {
+		return nil, nil
+	}
+	data := make(map[string]json.RawMessage)
+	var errs []error
+	s.storage.Range(func(key, value any) bool {
+		pk, ok := key.(PersistentKey)
+		if !ok {
+			return true
+		}
+		if _, ok := value.(*cell); ok {
+			return true
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("store key %q: %w", pk.PersistentName(), err))
+			return true
+		}
+		data[pk.PersistentName()] = b
+		return true
+	})
+	s.persist.mu.Lock()
+	for name, value := range s.persist.pending {
+		if _, ok := data[name]; ok {
+			continue
+		}
+		data[name] = value
+	}
+	s.persist.mu.Unlock()
+	return data, errors.Join(errs...)
+}
+
+func (s Store) hydrate(key any) {
+	if s.persist == nil {
+		return
+	}
+	pk, ok := key.(PersistentKey)
+	if !ok {
+		return
+	}
+	s.persist.mu.Lock()
+	data, ok := s.persist.pending[pk.PersistentName()]
+	if ok {
+		delete(s.persist.pending, pk.PersistentName())
+	}
+	s.persist.mu.Unlock()
+	if !ok {
+		return
+	}
+	value, err := pk.Decode(data)
+	if err != nil {
+		return
+	}
+	s.storage.LoadOrStore(key, value)
+}
+
+func (s Store) touch(key any) {
+	if s.persist == nil {
+		return
+	}
+	pk, ok := key.(PersistentKey)
+	if !ok {
+		return
+	}
+	s.persist.mu.Lock()
+	delete(s.persist.pending, pk.PersistentName())
+	s.persist.mu.Unlock()
+	if s.persist.changed != nil {
+		s.persist.changed()
+	}
+}
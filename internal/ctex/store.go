@@ -28,10 +28,12 @@ func NewStore() Store {
 
 type store struct {
 	storage sync.Map
+	persist *persistence
 }
 
 // Load returns the value stored under key or nil if key is absent.
 func (s Store) Load(key any) any {
+	s.hydrate(key)
 	v, ok := s.storage.Load(key)
 	if !ok {
 		return nil
@@ -48,6 +50,7 @@ func (s Store) Load(key any) any {
 
 // Save stores value under key and returns the previous value.
 func (s Store) Save(key any, value any) any {
+	defer s.touch(key)
 	v, ok := s.storage.Swap(key, value)
 	if !ok {
 		return nil
@@ -64,6 +67,7 @@ func (s Store) Save(key any, value any) any {
 
 // Remove deletes the value stored under key and returns it.
 func (s Store) Remove(key any) any {
+	defer s.touch(key)
 	v, ok := s.storage.LoadAndDelete(key)
 	if !ok {
 		return nil
@@ -80,6 +84,7 @@ func (s Store) Remove(key any) any {
 
 // Init returns the value stored under key, creating it with new if needed.
 func (s Store) Init(key any, new func() any) any {
+	s.hydrate(key)
 beg:
 	stored, ok := s.storage.Load(key)
 	if ok {
@@ -120,6 +125,7 @@ retry:
 	stored = new()
 	newC.Put(stored)
 	s.storage.CompareAndSwap(key, newC, stored)
+	s.touch(key)
 	return stored
 }
 
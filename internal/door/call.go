@@ -42,6 +42,7 @@ type callKind int
 const (
 	callReplace callKind = iota
 	callUpdate
+	callSplice
 )
 
 type call struct {
@@ -49,6 +50,8 @@ type call struct {
 	task    *userTask
 	kind    callKind
 	id      uint64
+	order   []any
+	morph   bool
 	payload printer.Payload
 	logger  *slog.Logger
 }
@@ -79,11 +82,19 @@ func (c *call) Action() (action.Action, bool) {
 	case callReplace:
 		return action.DoorReplace{
 			ID:      c.id,
+			Morph:   c.morph,
 			Payload: payload,
 		}, true
 	case callUpdate:
 		return action.DoorUpdate{
 			ID:      c.id,
+			Morph:   c.morph,
+			Payload: payload,
+		}, true
+	case callSplice:
+		return action.DoorSplice{
+			ID:      c.id,
+			Order:   c.order,
 			Payload: payload,
 		}, true
 	default:
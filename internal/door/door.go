@@ -24,7 +24,12 @@ import (
 )
 
 type Door struct {
-	node atomic.Pointer[node]
+	// Morph patches the rendered DOM in place on updates instead of replacing
+	// it, keeping focus, input state, and scroll offsets of elements that
+	// survive the update. Set it before the door is rendered.
+	// Optional.
+	Morph bool
+	node  atomic.Pointer[node]
 }
 
 func (d *Door) proxy(p *pipe, el gox.Elem) {
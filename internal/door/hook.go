@@ -75,15 +75,17 @@ func (h *hook) wait() chan struct{} {
 	return ch
 }
 
-func (h *hook) trigger(w http.ResponseWriter, r *http.Request, track uint64) bool {
+// trigger runs the hook handler. It reports whether the hook was found
+// active, and the error the handler failed with, if any.
+func (h *hook) trigger(w http.ResponseWriter, r *http.Request, track uint64) (bool, error) {
 	ch := h.wait()
 	if h.tracker.Context().Err() != nil {
 		close(ch)
-		return false
+		return false, nil
 	}
 	if !h.state.CompareAndSwap(hookActive, hookProgress) {
 		close(ch)
-		return false
+		return false, nil
 	}
 	ctx, frame := ctex.AfterFrameInsert(h.tracker.Context())
 	defer frame.Activate()
@@ -109,7 +111,7 @@ func (h *hook) trigger(w http.ResponseWriter, r *http.Request, track uint64) boo
 	if done {
 		h.tracker.removeHook(h.id)
 	}
-	return true
+	return true, err
 }
 
 func (h *hook) performCancel() {
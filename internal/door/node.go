@@ -17,6 +17,7 @@ package door
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"strings"
 
 	"github.com/doors-dev/doors/internal/common"
@@ -36,6 +37,21 @@ const (
 	modeStatic
 )
 
+func (m nodeMode) String() string {
+	switch m {
+	case modeOuter:
+		return "outer"
+	case modeInner:
+		return "inner"
+	case modeBlend:
+		return "blend"
+	case modeStatic:
+		return "static"
+	default:
+		return "unknown"
+	}
+}
+
 type node struct {
 	guard   shredder.ValveFrame
 	door    *Door
@@ -73,6 +89,7 @@ func (n *node) sync(task *userTask) {
 		ownerTracker = n.tracker.parent
 		callGuard = &shredder.ValveFrame{}
 	}
+	span := n.tracker.StartSpan(common.SpanDoorRender, slog.String(common.TraceMode, n.mode.String()))
 	renderFrame := shredder.Join(ownerTracker.Context(), true, thread.Frame(), ownerTracker.writeFrame(ownerTracker.Context()), task.RenderFrame())
 	defer renderFrame.Release()
 	pip := newPipe(
@@ -109,17 +126,24 @@ func (n *node) sync(task *userTask) {
 	callFrame.Run(ownerTracker.ctx, ownerTracker.root.runtime(), func(b bool) {
 		defer callGuard.Activate()
 		if !b {
+			span.End(context.Canceled)
 			pip.Release()
 			task.Cancel()
 			return
 		}
 		var payload printer.Payload
 		if err == nil {
-			payload, err = pip.Render(ownerTracker.Instance().Session().App().Conf().ServerDisableGzip)
+			payload, err = pip.Render(payloadCompression(ownerTracker.Instance()))
+		}
+		if err == nil {
+			span.SetAttrs(slog.Int(common.TracePayloadBytes, payload.Payload().Len()))
 		}
+		span.End(err)
 		logger := ownerTracker.root.inst.Logger()
 		callCtx := ownerTracker.ctx
+		morph := n.door.Morph && n.mode != modeStatic
 		if err != nil {
+			morph = false
 			n.onErr(err)
 			task.Report(err)
 			payload = newError(err, logger)
@@ -131,6 +155,7 @@ func (n *node) sync(task *userTask) {
 			ctx:     callCtx,
 			kind:    callKind,
 			id:      n.tracker.id,
+			morph:   morph,
 			task:    task,
 			payload: payload,
 			logger:  logger,
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/doors-dev/doors/internal/common"
+	"github.com/doors-dev/doors/internal/core"
 	"github.com/doors-dev/doors/internal/front/action"
 	"github.com/doors-dev/doors/internal/printer"
 	"github.com/doors-dev/doors/internal/shredder"
@@ -66,9 +67,9 @@ func (p *pipe) Release() {
 	stack.Release()
 }
 
-func (p *pipe) Render(disableGzip bool) (printer.Payload, error) {
+func (p *pipe) Render(c common.Compression) (printer.Payload, error) {
 	stack := p.Collect()
-	pr := printer.NewPayloadPrinter(disableGzip)
+	pr := printer.NewPayloadPrinter(c)
 	err := stack.Print(pr)
 	if err != nil {
 		pr.Release()
@@ -78,6 +79,15 @@ func (p *pipe) Render(disableGzip bool) (printer.Payload, error) {
 	return pr, nil
 }
 
+// payloadCompression is the encoding for rendered door payloads sent to the
+// client of inst.
+func payloadCompression(inst core.Instance) common.Compression {
+	if inst.Session().App().Conf().ServerDisableGzip {
+		return common.Compression{}
+	}
+	return inst.PayloadCompression()
+}
+
 func (p *pipe) error(err error) {
 	p.buffer.Clear()
 	p.buffer.PushBack(gox.NewJobComp(context.Background(), newError(err, p.tracker.Instance().Logger())))
@@ -88,12 +88,12 @@ func (r *root) removeHook(id uint64) {
 	delete(r.hooks, id)
 }
 
-func (r *root) TriggerHook(id uint64, w http.ResponseWriter, rq *http.Request, track uint64) bool {
+func (r *root) TriggerHook(id uint64, w http.ResponseWriter, rq *http.Request, track uint64) (bool, error) {
 	r.mu.Lock()
 	hook, ok := r.hooks[id]
 	r.mu.Unlock()
 	if !ok {
-		return false
+		return false, nil
 	}
 	return hook.trigger(w, rq, track)
 }
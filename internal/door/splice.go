@@ -0,0 +1,146 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package door
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/doors-dev/doors/internal/ctex"
+	"github.com/doors-dev/doors/internal/printer"
+	"github.com/doors-dev/doors/internal/shredder"
+	"github.com/doors-dev/gox"
+)
+
+// Splice sets the content of a list door, whose content renders exactly the
+// doors in order. Instead of rendering the content again, it renders only the
+// doors not mounted in d yet and rearranges the mounted ones on the client.
+// Doors that left the list are not removed; unmount them separately.
+//
+// If d is not mounted, the content is stored for the next render. If d is
+// mounted with an outer replacement, it is rendered again.
+func Splice(ctx context.Context, d *Door, content any, order []*Door) <-chan error {
+	ctex.LogCanceled(ctx, "Door splice")
+	userTask, ch := newUserTask(ctx)
+	task := nodeSplice{
+		userTask: userTask,
+		content:  content,
+		order:    order,
+	}
+	d.schedule(ctx, task, userTask.InitFrame())
+	return ch
+}
+
+type nodeSplice struct {
+	*userTask
+	content any
+	order   []*Door
+}
+
+func (t nodeSplice) apply(next *node, prev *node) {
+	next.mode = prev.mode
+	next.outer = prev.outer
+	next.content = t.content
+	if !prev.isMounted() {
+		t.userTask.Accept()
+		return
+	}
+	if prev.mode != modeInner && prev.mode != modeBlend {
+		next.tracker = trackerInherit(next, prev.tracker, false)
+		next.sync(t.userTask)
+		return
+	}
+	next.tracker = prev.tracker
+	next.tracker.setNode(next)
+	next.splice(t.userTask, t.order)
+}
+
+var _ nodeTask = nodeSplice{}
+
+func (n *node) splice(task *userTask, order []*Door) {
+	thread := shredder.Thread{}
+	tracker := n.tracker
+	callGuard := &shredder.ValveFrame{}
+	span := tracker.StartSpan(common.SpanDoorRender, slog.String(common.TraceMode, "splice"))
+	renderFrame := shredder.Join(tracker.Context(), true, thread.Frame(), tracker.writeFrame(tracker.Context()), task.RenderFrame())
+	defer renderFrame.Release()
+	pip := newPipe(
+		tracker,
+		common.GetDequeBuffer(),
+		renderFrame,
+		callGuard,
+	)
+	var err error
+	var ids []any
+	pip.renderFrame.Submit(tracker.ctx, tracker.root.runtime(), func(b bool) {
+		if !b {
+			return
+		}
+		ids = tracker.spliceIDs(order)
+		cur := gox.NewCursor(tracker.Context(), pip)
+		for i, door := range order {
+			if ids[i] != nil {
+				continue
+			}
+			if err = cur.Editor(door); err != nil {
+				return
+			}
+		}
+	})
+	callFrame := shredder.Join(tracker.Context(), true, thread.Frame(), tracker.outerCallGuard, task.CallFrame())
+	defer callFrame.Release()
+	callFrame.Run(tracker.ctx, tracker.root.runtime(), func(b bool) {
+		defer callGuard.Activate()
+		if !b {
+			span.End(context.Canceled)
+			pip.Release()
+			task.Cancel()
+			return
+		}
+		var payload printer.Payload
+		if err == nil {
+			payload, err = pip.Render(payloadCompression(tracker.Instance()))
+		}
+		if err == nil {
+			span.SetAttrs(slog.Int(common.TracePayloadBytes, payload.Payload().Len()))
+		}
+		span.End(err)
+		logger := tracker.root.inst.Logger()
+		if err != nil {
+			n.onErr(err)
+			task.Report(err)
+			tracker.root.inst.Call(&call{
+				ctx:     tracker.parent.ctx,
+				kind:    callReplace,
+				id:      tracker.id,
+				task:    task,
+				payload: newError(err, logger),
+				logger:  logger,
+			})
+			return
+		}
+		task.Scheduled()
+		tracker.root.inst.Call(&call{
+			ctx:     tracker.ctx,
+			kind:    callSplice,
+			id:      tracker.id,
+			order:   ids,
+			task:    task,
+			payload: payload,
+			logger:  logger,
+		})
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"sync"
 
@@ -133,6 +134,7 @@ type tracker struct {
 	container      *containerTracker
 	hooks          common.Set[uint64]
 	children       common.Set[*tracker]
+	doors          map[*Door]uint64
 	onClean        []func()
 }
 
@@ -140,6 +142,17 @@ func (t *tracker) Instance() core.Instance {
 	return t.root.instance()
 }
 
+func (t *tracker) StartSpan(name string, attrs ...slog.Attr) common.Span {
+	inst := t.Instance()
+	attrs = append([]slog.Attr{
+		slog.String(common.TraceSessionID, inst.Session().ID()),
+		slog.String(common.TraceInstanceID, inst.ID()),
+		slog.Uint64(common.TraceDoorID, t.id),
+	}, attrs...)
+	_, span := inst.Session().App().Tracer().Start(t.ctx, name, attrs...)
+	return span
+}
+
 func (t *tracker) UserCall(ctx context.Context, check func() bool, action action.Action, onResult func(json.RawMessage, error), onCancel func(), params action.CallParams) {
 	frames := ctex.GetFrames(ctx)
 	callFrame := shredder.Join(ctx, true, frames.Call(), t.innerCallGuard)
@@ -185,6 +198,42 @@ func (t *tracker) addChild(child *tracker) {
 		t.children = common.NewSet[*tracker]()
 	}
 	t.children.Add(child)
+	if child.node != nil {
+		if t.doors == nil {
+			t.doors = make(map[*Door]uint64)
+		}
+		t.doors[child.node.door] = child.id
+	}
+}
+
+// spliceIDs returns the ids of the doors in order that are mounted as
+// children, with nil for the ones that are not, and forgets the doors that
+// are not in order.
+func (t *tracker) spliceIDs(order []*Door) []any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := make([]any, len(order))
+	keep := make(map[*Door]uint64, len(order))
+	for i, door := range order {
+		if id, ok := t.doors[door]; ok {
+			ids[i] = id
+			keep[door] = id
+		}
+	}
+	t.doors = keep
+	return ids
+}
+
+func (t *tracker) getNode() *node {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.node
+}
+
+func (t *tracker) setNode(n *node) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.node = n
 }
 
 func (t *tracker) removeChild(child *tracker) {
@@ -204,7 +253,7 @@ func (t *tracker) clean(cascade bool, cleanGuard shredder.SimpleFrame) {
 	}
 	if cascade {
 		t.container.clean(cleanGuard)
-		t.node.unmountedSelf()
+		t.getNode().unmountedSelf()
 	}
 	t.mu.Lock()
 	hooks := t.hooks
@@ -213,6 +262,7 @@ func (t *tracker) clean(cascade bool, cleanGuard shredder.SimpleFrame) {
 	t.onClean = nil
 	t.hooks = nil
 	t.children = nil
+	t.doors = nil
 	t.mu.Unlock()
 	for child := range children {
 		child.clean(true, cleanGuard)
@@ -295,21 +345,23 @@ func (t *tracker) removeHook(id uint64) {
 }
 
 func (t *tracker) Reload(ctx context.Context) {
-	if t.node == nil {
+	node := t.getNode()
+	if node == nil {
 		return
 	}
-	t.node.reload(ctx)
+	node.reload(ctx)
 }
 
 func (t *tracker) XReload(ctx context.Context) <-chan error {
 	ctex.LogFreeWarning(ctx, "Door", "XReload")
-	if t.node == nil {
+	node := t.getNode()
+	if node == nil {
 		ch := make(chan error, 1)
 		ch <- errors.New("root door cannot be reloaded")
 		close(ch)
 		return ch
 	}
-	return t.node.reload(ctx)
+	return node.reload(ctx)
 }
 
 func (t *tracker) RootCore() core.Core {
@@ -375,6 +427,10 @@ func (t *containerTracker) ID() uint64 {
 	return t.getTracker().ID()
 }
 
+func (t *containerTracker) StartSpan(name string, attrs ...slog.Attr) common.Span {
+	return t.getTracker().StartSpan(name, attrs...)
+}
+
 func (t *containerTracker) removeHook(id uint64) {
 	t.getTracker().root.removeHook(id)
 	t.mu.Lock()
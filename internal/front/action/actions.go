@@ -136,8 +136,11 @@ func (a SetPath) Invocation() Invocation {
 	}
 }
 
+// DoorReplace replaces the door element with Payload, or patches it in
+// place when Morph is set.
 type DoorReplace struct {
 	ID      uint64
+	Morph   bool
 	Payload Payload
 }
 
@@ -147,13 +150,16 @@ func (a DoorReplace) Log() string {
 func (a DoorReplace) Invocation() Invocation {
 	return Invocation{
 		name:    "door_replace",
-		arg:     []any{a.ID},
+		arg:     doorArgs(a.ID, a.Morph),
 		payload: a.Payload,
 	}
 }
 
+// DoorUpdate replaces the door children with Payload, or patches them in
+// place when Morph is set.
 type DoorUpdate struct {
 	ID      uint64
+	Morph   bool
 	Payload Payload
 }
 
@@ -163,7 +169,33 @@ func (a DoorUpdate) Log() string {
 func (a DoorUpdate) Invocation() Invocation {
 	return Invocation{
 		name:    "door_update",
-		arg:     []any{a.ID},
+		arg:     doorArgs(a.ID, a.Morph),
+		payload: a.Payload,
+	}
+}
+
+func doorArgs(id uint64, morph bool) []any {
+	if morph {
+		return []any{id, true}
+	}
+	return []any{id}
+}
+
+// DoorSplice rearranges the children of a door to Order: ids of mounted
+// child doors, with nil taking the next door from Payload.
+type DoorSplice struct {
+	ID      uint64
+	Order   []any
+	Payload Payload
+}
+
+func (a DoorSplice) Log() string {
+	return "door_splice"
+}
+func (a DoorSplice) Invocation() Invocation {
+	return Invocation{
+		name:    "door_splice",
+		arg:     []any{a.ID, a.Order},
 		payload: a.Payload,
 	}
 }
@@ -246,6 +278,52 @@ func (u UpdateMeta) Invocation() Invocation {
 	}
 }
 
+type UpdateLang struct {
+	Lang string
+}
+
+func (u UpdateLang) Log() string {
+	return "update_lang"
+}
+
+func (u UpdateLang) Invocation() Invocation {
+	return Invocation{
+		name: "update_lang",
+		arg:  []any{u.Lang},
+	}
+}
+
+type RemoveAlternate struct {
+	HrefLang string
+}
+
+func (u RemoveAlternate) Log() string {
+	return "remove_alternate"
+}
+
+func (u RemoveAlternate) Invocation() Invocation {
+	return Invocation{
+		name: "remove_alternate",
+		arg:  []any{u.HrefLang},
+	}
+}
+
+type UpdateAlternate struct {
+	HrefLang string
+	Attrs    map[string]string
+}
+
+func (u UpdateAlternate) Log() string {
+	return "update_alternate"
+}
+
+func (u UpdateAlternate) Invocation() Invocation {
+	return Invocation{
+		name: "update_alternate",
+		arg:  []any{u.HrefLang, u.Attrs},
+	}
+}
+
 type Test struct {
 	Arg any
 }
@@ -22,6 +22,9 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/klauspost/compress/zstd"
 )
 
 func gunzipBytes(t *testing.T, data []byte) []byte {
@@ -129,6 +132,22 @@ func TestActionLogsAndInvocations(t *testing.T) {
 			args:            []any{uint64(11)},
 			expectedPayload: textPayload,
 		},
+		{
+			name:            "door update morph",
+			action:          DoorUpdate{ID: 11, Morph: true, Payload: textPayload},
+			log:             "door_update",
+			invocationName:  "door_update",
+			args:            []any{uint64(11), true},
+			expectedPayload: textPayload,
+		},
+		{
+			name:            "door splice",
+			action:          DoorSplice{ID: 12, Order: []any{uint64(3), nil}, Payload: textPayload},
+			log:             "door_splice",
+			invocationName:  "door_splice",
+			args:            []any{uint64(12), []any{uint64(3), nil}},
+			expectedPayload: textPayload,
+		},
 		{
 			name:            "indicate",
 			action:          Indicate{Duration: 1500 * time.Millisecond, Indicate: map[string]any{"selector": "#id"}},
@@ -311,7 +330,7 @@ func TestPayloadMarshalHelpersAndIntoPayload(t *testing.T) {
 	}
 
 	var raw []byte
-	binaryPayload, err := IntoPayload(raw, false)
+	binaryPayload, err := IntoPayload(raw, common.Compression{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -322,7 +341,7 @@ func TestPayloadMarshalHelpersAndIntoPayload(t *testing.T) {
 		t.Fatalf("unexpected binary length: %d", got)
 	}
 
-	textPayload, err := IntoPayload("hello", false)
+	textPayload, err := IntoPayload("hello", common.Compression{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -330,7 +349,7 @@ func TestPayloadMarshalHelpersAndIntoPayload(t *testing.T) {
 		t.Fatalf("unexpected text type: %v", got)
 	}
 
-	textGZPayload, err := IntoPayload("hello", true)
+	textGZPayload, err := IntoPayload("hello", common.Compression{Encoding: common.EncodingGzip})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -345,7 +364,7 @@ func TestPayloadMarshalHelpersAndIntoPayload(t *testing.T) {
 		t.Fatalf("unexpected unzipped text: %q", got)
 	}
 
-	jsonPayload, err := IntoPayload(map[string]any{"ok": true}, false)
+	jsonPayload, err := IntoPayload(map[string]any{"ok": true}, common.Compression{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -364,7 +383,7 @@ func TestPayloadMarshalHelpersAndIntoPayload(t *testing.T) {
 		t.Fatal("expected json payload to round-trip")
 	}
 
-	jsonGZPayload, err := IntoPayload(map[string]any{"ok": true}, true)
+	jsonGZPayload, err := IntoPayload(map[string]any{"ok": true}, common.Compression{Encoding: common.EncodingGzip})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -382,7 +401,34 @@ func TestPayloadMarshalHelpersAndIntoPayload(t *testing.T) {
 		t.Fatal("expected gz json payload to round-trip")
 	}
 
-	if _, err := IntoPayload(make(chan int), false); err == nil {
+	zstdPayload, err := IntoPayload("hello", common.Compression{Encoding: common.EncodingZstd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := zstdPayload.Type(); got != PayloadTextZstd {
+		t.Fatalf("unexpected text zstd type: %v", got)
+	}
+	var zstdBuf bytes.Buffer
+	if err := zstdPayload.Output(&zstdBuf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zstd.NewReader(&zstdBuf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	var unzstd bytes.Buffer
+	if _, err := unzstd.ReadFrom(zr); err != nil {
+		t.Fatal(err)
+	}
+	if got := unzstd.String(); got != "hello" {
+		t.Fatalf("unexpected zstd text: %q", got)
+	}
+	if raw, err := zstdPayload.MarshalJSON(); err != nil || !bytes.HasPrefix(raw, []byte(`[51,"`)) {
+		t.Fatalf("unexpected zstd payload json: %s %v", raw, err)
+	}
+
+	if _, err := IntoPayload(make(chan int), common.Compression{}); err == nil {
 		t.Fatal("expected unsupported payload type error")
 	}
 }
@@ -16,9 +16,9 @@ package action
 
 import (
 	"bytes"
-	"compress/gzip"
 	"encoding/json"
 	"io"
+	"strconv"
 
 	"github.com/doors-dev/doors/internal/common"
 )
@@ -54,6 +54,21 @@ func NewBinaryGZ(v []byte) Payload {
 	return Payload{entity: BinaryGZ(v)}
 }
 
+// NewEncoded returns a payload of kind t ([PayloadBinary], [PayloadJSON], or
+// [PayloadText]) whose content v was compressed with encoding.
+func NewEncoded(t PayloadType, encoding common.Encoding, v []byte) Payload {
+	t = t&payloadKind | encodingFlag(encoding)
+	switch t {
+	case PayloadTextGZ:
+		return NewTextGZ(v)
+	case PayloadJSONGZ:
+		return NewJSONGZ(v)
+	case PayloadBinaryGZ:
+		return NewBinaryGZ(v)
+	}
+	return Payload{entity: Encoded{typ: t, data: v}}
+}
+
 func NewEmptyPayload() Payload {
 	return Payload{}
 }
@@ -80,6 +95,16 @@ type Binary []byte
 
 type BinaryGZ []byte
 
+// Encoded is a payload compressed with brotli or zstd.
+type Encoded struct {
+	typ  PayloadType
+	data []byte
+}
+
+func (e Encoded) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.data)
+}
+
 type Payload struct {
 	entity any
 }
@@ -92,7 +117,7 @@ func (p Payload) Type() PayloadType {
 	if p.entity == nil {
 		return PayloadNone
 	}
-	switch p.entity.(type) {
+	switch v := p.entity.(type) {
 	case Text, TextBytes:
 		return PayloadText
 	case TextGZ:
@@ -105,6 +130,8 @@ func (p Payload) Type() PayloadType {
 		return PayloadBinary
 	case BinaryGZ:
 		return PayloadBinaryGZ
+	case Encoded:
+		return v.typ
 	default:
 		panic("unknown payload type")
 	}
@@ -129,6 +156,8 @@ func (p Payload) Len() int {
 		return len(v)
 	case BinaryGZ:
 		return len(v)
+	case Encoded:
+		return len(v.data)
 	default:
 		panic("unknown payload type")
 	}
@@ -164,6 +193,9 @@ func (p Payload) Output(w io.Writer) error {
 	case BinaryGZ:
 		_, err := w.Write(v)
 		return err
+	case Encoded:
+		_, err := w.Write(v.data)
+		return err
 	default:
 		panic("unknown payload type")
 	}
@@ -171,67 +203,76 @@ func (p Payload) Output(w io.Writer) error {
 
 type PayloadType int
 
+// The low nibble of a payload type is its kind, the high nibble its encoding.
 const (
-	PayloadNone     PayloadType = 0x00
-	PayloadBinary   PayloadType = 0x01
-	PayloadJSON     PayloadType = 0x02
-	PayloadText     PayloadType = 0x03
-	PayloadBinaryGZ PayloadType = 0x11
-	PayloadJSONGZ   PayloadType = 0x12
-	PayloadTextGZ   PayloadType = 0x13
+	PayloadNone       PayloadType = 0x00
+	PayloadBinary     PayloadType = 0x01
+	PayloadJSON       PayloadType = 0x02
+	PayloadText       PayloadType = 0x03
+	PayloadBinaryGZ   PayloadType = 0x11
+	PayloadJSONGZ     PayloadType = 0x12
+	PayloadTextGZ     PayloadType = 0x13
+	PayloadBinaryBR   PayloadType = 0x21
+	PayloadJSONBR     PayloadType = 0x22
+	PayloadTextBR     PayloadType = 0x23
+	PayloadBinaryZstd PayloadType = 0x31
+	PayloadJSONZstd   PayloadType = 0x32
+	PayloadTextZstd   PayloadType = 0x33
 )
 
-func IntoPayload(v any, gz bool) (Payload, error) {
+const payloadKind PayloadType = 0x0F
+
+func encodingFlag(encoding common.Encoding) PayloadType {
+	switch encoding {
+	case common.EncodingGzip:
+		return 0x10
+	case common.EncodingBrotli:
+		return 0x20
+	case common.EncodingZstd:
+		return 0x30
+	default:
+		panic("unknown payload encoding " + strconv.Quote(string(encoding)))
+	}
+}
+
+// IntoPayload encodes v for a client call. []byte is sent as is, strings
+// as text, and anything else as JSON. Text and JSON are compressed with c
+// unless its Encoding is empty.
+func IntoPayload(v any, c common.Compression) (Payload, error) {
 	if bytes, ok := v.([]byte); ok {
 		if bytes == nil {
 			bytes = make([]byte, 0)
 		}
 		return NewBinary(bytes), nil
 	}
-	buf := &bytes.Buffer{}
-	var w io.Writer = buf
-	var wgz *gzip.Writer
-	if gz {
-		wgz = common.GetGzipWriter(buf)
-		defer common.PutGzipWriter(wgz)
-		w = wgz
-	}
-	var t PayloadType
-	if str, ok := v.(string); ok {
-		if gz {
-			io.WriteString(w, str)
-			t = PayloadTextGZ
-		} else {
-			t = PayloadText
+	if c.Encoding == "" {
+		if str, ok := v.(string); ok {
+			return NewText(str), nil
 		}
-	} else {
-		encoder := json.NewEncoder(common.NewJsonWriter(w))
-		encoder.SetEscapeHTML(false)
-		if err := encoder.Encode(v); err != nil {
+		buf := &bytes.Buffer{}
+		if err := encodeJSON(buf, v); err != nil {
 			return Payload{}, err
 		}
-		if gz {
-			t = PayloadJSONGZ
-		} else {
-			t = PayloadJSON
-		}
+		return NewJSON(buf.Bytes()), nil
 	}
-	if gz {
-		if err := wgz.Close(); err != nil {
-			return Payload{}, err
-		}
+	buf := &bytes.Buffer{}
+	cw := common.GetCompressor(buf, c, false)
+	defer common.PutCompressor(cw, c, false)
+	t := PayloadJSON
+	if str, ok := v.(string); ok {
+		io.WriteString(cw, str)
+		t = PayloadText
+	} else if err := encodeJSON(cw, v); err != nil {
+		return Payload{}, err
 	}
-	switch t {
-	case PayloadJSONGZ:
-		return NewJSONGZ(buf.Bytes()), nil
-	case PayloadJSON:
-		return NewJSON(buf.Bytes()), nil
-	case PayloadText:
-		return NewText(v.(string)), nil
-	case PayloadTextGZ:
-		return NewTextGZ(buf.Bytes()), nil
-	default:
-		panic("unexpected payload type")
+	if err := cw.Close(); err != nil {
+		return Payload{}, err
 	}
+	return NewEncoded(t, c.Encoding, buf.Bytes()), nil
+}
 
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(common.NewJsonWriter(w))
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
 }
@@ -59,6 +59,10 @@ func AttrsSetDoor(attrs gox.Attrs, id uint64, container bool) {
 
 }
 
+func AttrsSetPreserve(attrs gox.Attrs) {
+	attrs.Get("data-d0m").Set("preserve")
+}
+
 func AttrsSetActive(attrs gox.Attrs, active []any) {
 	val := jsonAttr{active}
 	attrs.Get("data-d0a").Set(val)
@@ -101,7 +105,7 @@ type payloadAttr struct {
 }
 
 func (j payloadAttr) Output(w io.Writer) error {
-	payload, err := action.IntoPayload(j.value, false)
+	payload, err := action.IntoPayload(j.value, common.Compression{})
 	if err != nil {
 		return err
 	}
@@ -132,3 +132,46 @@ func (c InputCapture) Name() string {
 func (c InputCapture) Listen() string {
 	return "input"
 }
+
+type DragCapture struct {
+	Event           string `json:"-"`
+	PreventDefault  bool   `json:"pd"`
+	StopPropagation bool   `json:"sp"`
+	ExactTarget     bool   `json:"et"`
+}
+
+func (c DragCapture) Name() string {
+	return "drag"
+}
+
+func (c DragCapture) Listen() string {
+	return c.Event
+}
+
+type DropCapture struct {
+	StopPropagation bool `json:"sp"`
+	ExactTarget     bool `json:"et"`
+}
+
+func (c DropCapture) Name() string {
+	return "drop"
+}
+
+func (c DropCapture) Listen() string {
+	return "drop"
+}
+
+type VisibleCapture struct {
+	Threshold  float64 `json:"th,omitempty"`
+	RootMargin string  `json:"rm,omitempty"`
+	Once       bool    `json:"oc,omitempty"`
+	Leave      bool    `json:"lv,omitempty"`
+}
+
+func (c VisibleCapture) Name() string {
+	return "visible"
+}
+
+func (c VisibleCapture) Listen() string {
+	return "d0-visible"
+}
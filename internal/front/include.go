@@ -71,6 +71,21 @@ func Include(inst core.Instance) gox.Elem {
 					return err
 				}
 			}
+			if conf.SolitaireWebSocket {
+				if err := cur.Set("data-ws", "true"); err != nil {
+					return err
+				}
+			}
+			if nonce := inst.CSPNonce(); nonce != "" {
+				if err := cur.Set("nonce", nonce); err != nil {
+					return err
+				}
+			}
+			if token := inst.Token(); token != "" {
+				if err := cur.Set("data-token", token); err != nil {
+					return err
+				}
+			}
 			if err := cur.Submit(); err != nil {
 				return err
 			}
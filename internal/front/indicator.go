@@ -23,6 +23,7 @@ const (
 	indicatorClass       indicatorKind = "class"
 	indicatorRemoveClass indicatorKind = "remove_class"
 	indicatorContent     indicatorKind = "content"
+	indicatorProgress    indicatorKind = "progress"
 )
 
 type SelectorMode string
@@ -107,6 +108,14 @@ func IndicatorContent(s Selector, content string) Indicator {
 	}
 }
 
+func IndicatorProgress(s Selector, attr string) Indicator {
+	return Indicator{
+		selector: s,
+		kind:     indicatorProgress,
+		param1:   attr,
+	}
+}
+
 func (i Indicator) MarshalJSON() ([]byte, error) {
 	return json.Marshal([]any{i.selector, i.kind, i.param1, i.param2})
 }
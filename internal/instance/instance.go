@@ -5,7 +5,6 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
 	"sync/atomic"
 	"time"
 
@@ -27,13 +26,17 @@ import (
 type Instance = *instance
 
 func newInstance(sess *session, loc path.Location) Instance {
-	return &instance{
+	inst := &instance{
 		id:       common.RandId(),
 		session:  sess,
 		store:    ctex.NewStore(),
 		location: beam.NewSource(loc, path.EqualLocation, false),
 		prime:    common.NewPrime(),
 	}
+	if policy := sess.app.RequestPolicy(); policy != nil && policy.RequireToken {
+		inst.token = common.RandId()
+	}
+	return inst
 }
 
 const (
@@ -58,8 +61,13 @@ type instance struct {
 	csp        common.CSPCollector
 	importMap  utils.ImportMap
 	pageStatus atomic.Int32
+	redirect   atomic.Pointer[pageRedirect]
 	titleMeta  core.TitleMeta
 	boot       atomic.Pointer[string]
+	payload    atomic.Pointer[common.Compression]
+	token      string
+	nonce      string
+	headers    atomic.Pointer[common.SecurityHeaders]
 }
 
 func (inst Instance) Logger() *slog.Logger {
@@ -74,8 +82,25 @@ func (inst Instance) TriggerHook(hookID uint64, w http.ResponseWriter, r *http.R
 	if inst.state.Load() != active {
 		return false
 	}
-	ok := inst.root.TriggerHook(hookID, w, r, track)
+	inst.headers.Load().Override(w.Header())
+	if ok, retry, rejected := inst.session.hookRate.Take(inst.session.app.Conf().SessionHookRateLimit, time.Now()); !ok {
+		common.WriteRateLimited(w, retry)
+		inst.session.app.HookLimited(inst.session.Context(), common.HookAbuse{
+			Scope:      common.RateLimitSession,
+			InstanceID: inst.id,
+			Rejected:   rejected,
+			RetryAfter: retry,
+			Request:    r,
+		})
+		return true
+	}
+	ctx, span := inst.StartSpan(r.Context(), common.SpanHook, slog.Uint64(common.TraceHookID, hookID))
+	start := time.Now()
+	ok, err := inst.root.TriggerHook(hookID, w, r.WithContext(ctx), track)
+	span.SetAttrs(slog.Bool(common.TraceFound, ok))
+	span.End(err)
 	if ok {
+		inst.Metrics().Hook(time.Since(start))
 		inst.session.limiter.TouchHeavy(inst.id)
 	}
 	return ok
@@ -94,10 +119,70 @@ func (inst Instance) Connect(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if e := r.URL.Query().Get("e"); e != "" {
+		c := common.NegotiatePayloadCompression(e, inst.session.app.Conf().ServerCompression)
+		inst.payload.Store(&c)
+	}
 	inst.killTimer.KeepAlive()
 	inst.solitaire.Connect(w, r)
 }
 
+func (inst Instance) StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, common.Span) {
+	attrs = append([]slog.Attr{
+		slog.String(common.TraceSessionID, inst.session.id),
+		slog.String(common.TraceInstanceID, inst.id),
+	}, attrs...)
+	return inst.session.app.Tracer().Start(ctx, name, attrs...)
+}
+
+func (inst Instance) Metrics() *common.Metrics {
+	return inst.session.app.Metrics()
+}
+
+// Stats is a snapshot of the state of an instance.
+type Stats struct {
+	// State is "new", "initializing", "active", or "killed".
+	State string
+	// Sync holds the synchronization state of an active instance.
+	Sync solitaire.Stats
+	// Runtime holds the worker usage of an active instance.
+	Runtime shredder.Stats
+}
+
+func (inst Instance) Stats() Stats {
+	switch inst.state.Load() {
+	case zero:
+		return Stats{State: "new"}
+	case initializing:
+		return Stats{State: "initializing"}
+	case active:
+		return Stats{
+			State:   "active",
+			Sync:    inst.solitaire.Stats(),
+			Runtime: inst.runtime.Stats(),
+		}
+	default:
+		return Stats{State: "killed"}
+	}
+}
+
+// Token returns the secret the client sends with its requests, or "" if
+// the request policy doesn't require one.
+func (inst Instance) Token() string {
+	return inst.token
+}
+
+// CSPNonce returns the CSP nonce of the page response, or "".
+func (inst Instance) CSPNonce() string {
+	return inst.nonce
+}
+
+// SetSecurityHeaders overrides the app security headers for the page
+// response and the hook responses of the instance.
+func (inst Instance) SetSecurityHeaders(h common.SecurityHeaders) {
+	inst.headers.Store(&h)
+}
+
 func (inst Instance) CSPCollector() common.CSPCollector {
 	return inst.csp
 }
@@ -130,8 +215,25 @@ func (inst *instance) SetStatus(s int) {
 	inst.pageStatus.Store(int32(s))
 }
 
+type pageRedirect struct {
+	url    string
+	status int
+}
+
+// Redirect replaces the initial page response with a redirect to url. It
+// returns false once the page has been served.
+func (inst *instance) Redirect(url string, status int) bool {
+	if inst.state.Load() != initializing {
+		return false
+	}
+	inst.redirect.CompareAndSwap(nil, &pageRedirect{url: url, status: status})
+	return true
+}
+
 func (inst *instance) SyncError(err error) {
-	inst.Logger().Debug("Instance synchronization error", "error", err, "type", "error", "instance_id", inst.id)
+	reason := solitaire.Reason(err)
+	inst.Logger().Debug("Instance synchronization error", "error", err, "reason", reason.String(), "type", "error", "instance_id", inst.id)
+	inst.Metrics().SyncError(reason)
 	inst.end(common.EndCauseSyncError)
 }
 
@@ -166,6 +268,16 @@ func (inst Instance) TitleMeta() core.TitleMeta {
 	return inst.titleMeta
 }
 
+// PayloadCompression returns the encoding for sync payloads, negotiated
+// with the encodings the client reported on connect. Until it connects, it
+// is gzip.
+func (inst Instance) PayloadCompression() common.Compression {
+	if c := inst.payload.Load(); c != nil {
+		return *c
+	}
+	return common.NegotiatePayloadCompression("", inst.session.app.Conf().ServerCompression)
+}
+
 type Page = func(ctx context.Context, w http.ResponseWriter, r *http.Request) gox.Comp
 
 type instanceComp struct {
@@ -199,6 +311,7 @@ func (inst Instance) Serve(w http.ResponseWriter, r *http.Request, page Page) (e
 	inst.root = door.NewRoot(inst)
 	inst.killTimer = utils.NewKillTimer(inst)
 	inst.csp = inst.session.app.CSP().NewCollector()
+	inst.nonce = inst.csp.Nonce()
 	inst.importMap = utils.NewImportMap()
 	inst.titleMeta = utils.NewTitleMeta(inst)
 	stack, err := inst.root.Render(r.Context(), instanceComp{
@@ -212,6 +325,14 @@ func (inst Instance) Serve(w http.ResponseWriter, r *http.Request, page Page) (e
 		inst.clean(common.EndCauseKilled)
 		return err, ok
 	}
+	if redirect := inst.redirect.Load(); redirect != nil {
+		ok := inst.state.CompareAndSwap(initializing, killed)
+		inst.clean(common.EndCauseKilled)
+		if ok {
+			http.Redirect(w, r, redirect.url, redirect.status)
+		}
+		return nil, ok
+	}
 	if !inst.state.CompareAndSwap(initializing, active) {
 		inst.clean(common.EndCauseSuspend)
 		return nil, false
@@ -232,32 +353,40 @@ func (inst Instance) Serve(w http.ResponseWriter, r *http.Request, page Page) (e
 }
 
 func (inst *instance) render(w http.ResponseWriter, r *http.Request, pipe door.Stack, static bool) error {
-	gz := !inst.session.App().Conf().ServerDisableGzip && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+	encodings := inst.session.App().Conf().Compression()
+	compression, compress := common.NegotiateCompression(r.Header.Get("Accept-Encoding"), encodings)
 	importMap, importHash := inst.importMap.Generate()
-	inst.renderHeaders(w, gz, importHash)
+	if len(encodings) != 0 {
+		w.Header().Set("Vary", "Accept-Encoding")
+	}
+	if compress {
+		w.Header().Set("Content-Encoding", string(compression.Encoding))
+	}
+	inst.renderHeaders(w, importHash)
 	var writer io.Writer = w
-	if gz {
-		wgz := common.GetGzipWriter(w)
-		defer common.PutGzipWriter(wgz)
-		defer wgz.Close()
-		writer = wgz
+	if compress {
+		cw := common.GetCompressor(w, compression, false)
+		defer common.PutCompressor(cw, compression, false)
+		defer cw.Close()
+		writer = cw
 	}
-	pr := printer.NewPagePrinter(writer, static, front.Include(inst), importMap, inst.titleMeta)
+	pr := printer.NewPagePrinter(writer, static, front.Include(inst), importMap, inst.nonce, inst.titleMeta)
 	return pipe.Print(pr)
 }
 
-func (inst *instance) renderHeaders(w http.ResponseWriter, gz bool, importHash []byte) {
+func (inst *instance) renderHeaders(w http.ResponseWriter, importHash []byte) {
+	inst.headers.Load().Override(w.Header())
 	if inst.csp != nil {
 		if importHash != nil {
 			inst.csp.ScriptHash(importHash)
 		}
 		header := inst.csp.Generate()
 		w.Header().Add("Content-Security-Policy", header)
+		if endpoints := inst.csp.ReportingEndpoints(); endpoints != "" {
+			w.Header().Set("Reporting-Endpoints", endpoints)
+		}
 		inst.csp = nil
 	}
-	if gz {
-		w.Header().Set("Content-Encoding", "gzip")
-	}
 	w.WriteHeader(inst.getStatus())
 }
 
@@ -277,6 +406,7 @@ func (inst *instance) end(cause common.EndCause) {
 }
 
 func (inst Instance) clean(cause common.EndCause) {
+	inst.Metrics().InstanceEnded(cause)
 	inst.session.removeInstance(inst.id)
 	inst.runtime.Cancel()
 	inst.solitaire.End(cause)
@@ -16,8 +16,14 @@ package instance
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"iter"
 	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,27 +38,65 @@ import (
 
 type App interface {
 	CSP() *common.CSP
+	RequestPolicy() *common.RequestPolicy
 	Conf() *common.Conf
 	PathMaker() path.PathMaker
 	RemoveSession(id string)
 	ResourceRegistry() resources.Registry
 	Logger() *slog.Logger
+	Tracer() common.Tracer
+	Metrics() *common.Metrics
 	InstanceCreated()
 	InstanceDeleted()
 	Draining() bool
+	PersistSessions() bool
+	SaveSession(SessionRecord)
+	HookLimited(ctx context.Context, abuse common.HookAbuse)
+	WriteMetrics(w io.Writer) error
+}
+
+// SessionRecord is the persisted state of a session.
+type SessionRecord struct {
+	ID     string                     `json:"id"`
+	Expire time.Time                  `json:"expire,omitzero"`
+	TTL    time.Time                  `json:"ttl"`
+	Store  map[string]json.RawMessage `json:"store,omitempty"`
+	Tags   map[string]string          `json:"tags,omitempty"`
+}
+
+// KillTime returns the moment the recorded session expires.
+func (r SessionRecord) KillTime() time.Time {
+	if r.Expire.IsZero() || r.TTL.Before(r.Expire) {
+		return r.TTL
+	}
+	return r.Expire
 }
 
 type Session = *session
 
 func NewSession(a App) Session {
+	return newSession(a, common.RandId())
+}
+
+// RestoreSession rebuilds a session from its persisted record.
+func RestoreSession(a App, rec SessionRecord) Session {
+	sess := newSession(a, rec.ID)
+	sess.expireTime = rec.Expire
+	sess.ttlTime = rec.TTL
+	sess.store.Restore(rec.Store)
+	sess.tags = maps.Clone(rec.Tags)
+	return sess
+}
+
+func newSession(a App, id string) Session {
 	ctx, cancel := context.WithCancel(context.Background())
 	sess := &session{
-		store:   ctex.NewStore(),
-		id:      common.RandId(),
+		id:      id,
 		app:     a,
 		limiter: utils.NewLimiter(a.Conf().SessionInstanceLimit),
 		cancel:  cancel,
 	}
+	sess.store = ctex.NewPersistentStore(sess.saveLater)
 	sess.ctx = context.WithValue(ctx, common.KeySession, sess)
 	return sess
 }
@@ -61,12 +105,17 @@ type session struct {
 	renewed    atomic.Int64
 	instances  sync.Map
 	mu         sync.Mutex
+	saveMu     sync.Mutex
 	store      ctex.Store
 	id         string
 	app        App
 	limiter    utils.Limiter
+	hookRate   common.RateBucket
+	saving     atomic.Bool
+	dirty      atomic.Bool
 	expireTime time.Time
 	ttlTime    time.Time
+	tags       map[string]string
 	killTimer  *time.Timer
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -103,7 +152,146 @@ func (sess *session) Expire(d time.Duration) {
 	} else {
 		sess.expireTime = time.Now().Add(d)
 	}
-	sess.resetKillTimer()
+	if !sess.resetKillTimer() {
+		return
+	}
+	sess.saveLater()
+}
+
+// Save hands the current session state to the app's session backend and
+// waits for it. Backend I/O runs outside sess.mu; saveMu keeps saves in
+// order.
+func (sess *session) Save() {
+	if !sess.app.PersistSessions() {
+		return
+	}
+	sess.saveMu.Lock()
+	defer sess.saveMu.Unlock()
+	sess.mu.Lock()
+	if sess.killed() {
+		sess.mu.Unlock()
+		return
+	}
+	record := sess.record()
+	sess.mu.Unlock()
+	sess.app.SaveSession(record)
+}
+
+// saveLater saves the session in the background, so request paths don't
+// wait for store serialization and backend I/O. Calls made while a save is
+// running are coalesced into one more save.
+func (sess *session) saveLater() {
+	if !sess.app.PersistSessions() {
+		return
+	}
+	sess.dirty.Store(true)
+	if !sess.saving.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		for {
+			for sess.dirty.Swap(false) {
+				sess.Save()
+			}
+			sess.saving.Store(false)
+			if !sess.dirty.Load() || !sess.saving.CompareAndSwap(false, true) {
+				return
+			}
+		}
+	}()
+}
+
+func (sess *session) record() SessionRecord {
+	store, err := sess.store.Persistent()
+	if err != nil {
+		sess.app.Logger().Error("session store serialization error", "error", err)
+	}
+	return SessionRecord{
+		ID:     sess.id,
+		Expire: sess.expireTime,
+		TTL:    sess.ttlTime,
+		Store:  store,
+		Tags:   maps.Clone(sess.tags),
+	}
+}
+
+// Tag is a session label: a key and its value.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Tag labels the session with key and value. An empty value removes the
+// label.
+func (sess *session) Tag(key string, value string) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.killed() {
+		return
+	}
+	if value == "" {
+		if _, ok := sess.tags[key]; !ok {
+			return
+		}
+		delete(sess.tags, key)
+	} else {
+		if sess.tags[key] == value {
+			return
+		}
+		if sess.tags == nil {
+			sess.tags = make(map[string]string)
+		}
+		sess.tags[key] = value
+	}
+	sess.saveLater()
+}
+
+// Tags returns a copy of the session labels.
+func (sess *session) Tags() map[string]string {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return maps.Clone(sess.tags)
+}
+
+func (sess *session) HasTag(tag Tag) bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	value, ok := sess.tags[tag.Key]
+	return ok && value == tag.Value
+}
+
+// SessionInfo is a snapshot of a live session.
+type SessionInfo struct {
+	ID        string
+	Tags      map[string]string
+	LastSeen  time.Time
+	Instances []InstanceInfo
+}
+
+// InstanceInfo is a snapshot of a live page instance.
+type InstanceInfo struct {
+	ID       string
+	LastSeen time.Time
+	Location path.Location
+}
+
+func (sess Session) Info() SessionInfo {
+	info := SessionInfo{
+		ID:       sess.id,
+		Tags:     sess.Tags(),
+		LastSeen: sess.LastSeen(),
+	}
+	for inst := range sess.Instances() {
+		info.Instances = append(info.Instances, InstanceInfo{
+			ID:       inst.ID(),
+			LastSeen: inst.LastSeen(),
+			Location: inst.Location().Get(),
+		})
+	}
+	slices.SortFunc(info.Instances, func(a, b InstanceInfo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return info
 }
 
 func (sess Session) ID() string {
@@ -132,6 +320,15 @@ func (sess Session) Instance(loc path.Location) (Instance, bool) {
 	return inst, !sess.killed()
 }
 
+// Instances iterates over the live instances of the session.
+func (sess Session) Instances() iter.Seq[Instance] {
+	return func(yield func(Instance) bool) {
+		sess.instances.Range(func(_, v any) bool {
+			return yield(v.(Instance))
+		})
+	}
+}
+
 func (sess Session) InstanceCount() (n int) {
 	sess.instances.Range(func(_, _ any) bool {
 		n++
@@ -166,6 +363,7 @@ func (sess *session) Renew(w http.ResponseWriter) bool {
 	if maxAge < sess.app.Conf().RequestTimeout {
 		return false
 	}
+	sess.saveLater()
 	cookie := &http.Cookie{
 		Name:     sess.app.PathMaker().SessionCookie(),
 		Value:    sess.id,
@@ -212,13 +410,10 @@ func (sess *session) resetKillTimer() bool {
 }
 
 func (sess *session) killTime() time.Time {
-	if sess.expireTime.IsZero() {
-		return sess.ttlTime
-	}
-	if sess.ttlTime.Before(sess.expireTime) {
-		return sess.ttlTime
-	}
-	return sess.expireTime
+	return SessionRecord{
+		Expire: sess.expireTime,
+		TTL:    sess.ttlTime,
+	}.KillTime()
 }
 
 func (sess *session) untillKill() time.Duration {
@@ -241,6 +436,16 @@ func (sess *session) GetInstance(id string) (Instance, bool) {
 	return inst.(Instance), !sess.killed()
 }
 
+// End kills the session and ends its live instances right away, so open
+// pages learn about it on their sync connection instead of on the next
+// request.
+func (sess Session) End() {
+	sess.Kill()
+	for inst := range sess.Instances() {
+		inst.Kill()
+	}
+}
+
 func (sess *session) Kill() {
 	sess.cancel()
 	sess.mu.Lock()
@@ -249,5 +454,8 @@ func (sess *session) Kill() {
 		sess.killTimer = nil
 	}
 	sess.mu.Unlock()
+	// a save already past its killed check must not land after the delete
+	sess.saveMu.Lock()
+	defer sess.saveMu.Unlock()
 	sess.app.RemoveSession(sess.id)
 }
@@ -2,6 +2,7 @@ package instance
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -17,6 +18,7 @@ type sessionTestApp struct {
 	conf       common.Conf
 	cookieName string
 	removed    chan string
+	metrics    common.Metrics
 }
 
 func newSessionTestApp() *sessionTestApp {
@@ -32,6 +34,10 @@ func (a *sessionTestApp) CSP() *common.CSP {
 	return &common.CSP{}
 }
 
+func (a *sessionTestApp) RequestPolicy() *common.RequestPolicy {
+	return nil
+}
+
 func (a *sessionTestApp) Conf() *common.Conf {
 	return &a.conf
 }
@@ -52,10 +58,26 @@ func (a *sessionTestApp) Logger() *slog.Logger {
 	return slog.Default()
 }
 
+func (a *sessionTestApp) Tracer() common.Tracer {
+	return common.NoopTracer{}
+}
+
+func (a *sessionTestApp) Metrics() *common.Metrics {
+	return &a.metrics
+}
+
 func (a *sessionTestApp) InstanceCreated() {}
 func (a *sessionTestApp) InstanceDeleted() {}
 func (a *sessionTestApp) Draining() bool   { return false }
 
+func (a *sessionTestApp) PersistSessions() bool { return false }
+
+func (a *sessionTestApp) SaveSession(rec SessionRecord) {}
+
+func (a *sessionTestApp) HookLimited(ctx context.Context, abuse common.HookAbuse) {}
+
+func (a *sessionTestApp) WriteMetrics(w io.Writer) error { return nil }
+
 func TestSessionKillCancelsContext(t *testing.T) {
 	app := newSessionTestApp()
 	sess := NewSession(app)
@@ -181,3 +203,30 @@ func (noopResponseWriter) Write([]byte) (int, error) {
 }
 
 func (noopResponseWriter) WriteHeader(int) {}
+
+func TestSessionTags(t *testing.T) {
+	app := newSessionTestApp()
+	sess := NewSession(app)
+	sess.Tag("user", "alice")
+	sess.Tag("role", "admin")
+	sess.Tag("role", "")
+	if !sess.HasTag(Tag{Key: "user", Value: "alice"}) || sess.HasTag(Tag{Key: "user", Value: "bob"}) {
+		t.Fatalf("unexpected tags %v", sess.Tags())
+	}
+	if _, ok := sess.Tags()["role"]; ok {
+		t.Fatal("expected empty value to remove the tag")
+	}
+	sess.Tags()["user"] = "mallory"
+	if sess.Tags()["user"] != "alice" {
+		t.Fatal("expected Tags to return a copy")
+	}
+
+	restored := RestoreSession(app, sess.record())
+	if !restored.HasTag(Tag{Key: "user", Value: "alice"}) {
+		t.Fatalf("expected tags to survive rehydration, got %v", restored.Tags())
+	}
+	info := restored.Info()
+	if info.ID != sess.ID() || info.Tags["user"] != "alice" {
+		t.Fatalf("unexpected session info %+v", info)
+	}
+}
@@ -73,11 +73,11 @@ func (n *navigator) push(ctx context.Context, l path.Location, replace bool) {
 	seq := n.seq.Add(1)
 	after, ok := ctex.AfterFrame(ctx)
 	if !ok {
-		n.call(l.String(), seq, replace)
+		n.call(n.inst.Session().App().PathMaker().URL(l), seq, replace)
 		return
 	}
 	after.After().Run(nil, nil, func(b bool) {
-		n.call(l.String(), seq, replace)
+		n.call(n.inst.Session().App().PathMaker().URL(l), seq, replace)
 	})
 }
 
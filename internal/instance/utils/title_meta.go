@@ -26,12 +26,14 @@ func NewTitleMeta(inst core.Instance) core.TitleMeta {
 }
 
 type titleMeta struct {
-	mu        sync.Mutex
-	rendered  bool
-	inst      core.Instance
-	title     cake[title]
-	metaProps metaMap
-	metaNames metaMap
+	mu         sync.Mutex
+	rendered   bool
+	inst       core.Instance
+	title      cake[title]
+	metaProps  metaMap
+	metaNames  metaMap
+	lang       cake[string]
+	alternates metaMap
 }
 
 func (t *titleMeta) Edit(cur gox.Cursor) error {
@@ -45,6 +47,14 @@ func (t *titleMeta) Edit(cur gox.Cursor) error {
 	metas := make([]metaEditor, 0, t.metaProps.Len()+t.metaNames.Len())
 	t.dumpMeta(&metas, t.metaNames, false)
 	t.dumpMeta(&metas, t.metaProps, true)
+	alternates := make([]alternateEditor, 0, t.alternates.Len())
+	for hreflang, c := range t.alternates.Iter() {
+		_, attrs := c.current()
+		alternates = append(alternates, alternateEditor{
+			hreflang: hreflang,
+			attrs:    attrs,
+		})
+	}
 	t.mu.Unlock()
 	if titleID != 0 {
 		if err := cur.Init("title"); err != nil {
@@ -73,9 +83,73 @@ func (t *titleMeta) Edit(cur gox.Cursor) error {
 			return err
 		}
 	}
+	for _, a := range alternates {
+		if err := a.Edit(cur); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// Lang returns the current value of the html lang attribute.
+func (t *titleMeta) Lang() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, lang := t.lang.current()
+	return lang
+}
+
+func (t *titleMeta) UpdateLang(lang string) (cancel context.CancelFunc) {
+	t.mu.Lock()
+	id := t.lang.put(lang)
+	call := t.rendered
+	t.mu.Unlock()
+	cancel = func() {
+		t.RemoveLang(id)
+	}
+	if !call {
+		return
+	}
+	t.callUpdateLang(id, lang)
+	return
+}
+
+func (t *titleMeta) RemoveLang(id uint) {
+	t.mu.Lock()
+	updated := t.lang.remove(id)
+	if !updated || !t.rendered {
+		t.mu.Unlock()
+		return
+	}
+	id, lang := t.lang.current()
+	t.mu.Unlock()
+	t.callUpdateLang(id, lang)
+}
+
+func (t *titleMeta) UpdateAlternate(hreflang string, attrs gox.Attrs) (cancel context.CancelFunc) {
+	id, call := t.updateMeta(&t.alternates, hreflang, attrs)
+	cancel = func() {
+		t.RemoveAlternate(hreflang, id)
+	}
+	if !call {
+		return
+	}
+	t.callUpdateAlternate(id, hreflang, attrs)
+	return
+}
+
+func (t *titleMeta) RemoveAlternate(hreflang string, id uint) {
+	id, attrs, call := t.removeMeta(&t.alternates, hreflang, id)
+	if !call {
+		return
+	}
+	if id == 0 {
+		t.callRemoveAlternate(hreflang)
+		return
+	}
+	t.callUpdateAlternate(id, hreflang, attrs)
+}
+
 func (t *titleMeta) dumpMeta(s *[]metaEditor, m metaMap, property bool) {
 	for name, c := range m.Iter() {
 		_, attrs := c.current()
@@ -248,6 +322,90 @@ func (t *titleMeta) callUpdateTitle(id uint, value string, attrs gox.Attrs) {
 	)
 }
 
+func (t *titleMeta) callUpdateLang(id uint, lang string) {
+	t.inst.UserCall(
+		context.Background(),
+		func() bool {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if id == 0 {
+				return t.lang.isEmpty()
+			}
+			return t.lang.isCurrent(id)
+		},
+		action.UpdateLang{
+			Lang: lang,
+		},
+		nil,
+		nil,
+		action.CallParams{},
+	)
+}
+
+func (t *titleMeta) callRemoveAlternate(hreflang string) {
+	t.inst.UserCall(
+		context.Background(),
+		func() bool {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			_, ok := t.alternates.Get(hreflang)
+			return !ok
+		},
+		action.RemoveAlternate{
+			HrefLang: hreflang,
+		},
+		nil,
+		nil,
+		action.CallParams{},
+	)
+}
+
+func (t *titleMeta) callUpdateAlternate(id uint, hreflang string, attrs gox.Attrs) {
+	t.inst.UserCall(
+		context.Background(),
+		func() bool {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			c, ok := t.alternates.Get(hreflang)
+			if !ok {
+				return false
+			}
+			return c.isCurrent(id)
+		},
+		action.UpdateAlternate{
+			HrefLang: hreflang,
+			Attrs:    common.AttrsToMap(attrs, t.inst.Logger()),
+		},
+		nil,
+		nil,
+		action.CallParams{},
+	)
+}
+
+type alternateEditor struct {
+	hreflang string
+	attrs    gox.Attrs
+}
+
+func (a alternateEditor) Edit(cur gox.Cursor) error {
+	if err := cur.InitVoid("link"); err != nil {
+		return err
+	}
+	if err := cur.Set("rel", "alternate"); err != nil {
+		return err
+	}
+	if err := cur.Set("hreflang", a.hreflang); err != nil {
+		return err
+	}
+	if err := cur.Modify(gox.ModifyFunc(func(ctx context.Context, tag string, attrs gox.Attrs) error {
+		attrs.Inherit(a.attrs)
+		return nil
+	})); err != nil {
+		return err
+	}
+	return cur.Submit()
+}
+
 type metaEditor struct {
 	name     string
 	property bool
@@ -40,6 +40,9 @@ func (a *modelAdapters) set(t reflect.Type, ad adapter) {
 	(*sync.Map)(a).Store(t, ad)
 }
 
+// Encode encodes m, a [Location], an [Encoder], or a path model, into a
+// Location. It has no app context, so the result never includes a base path
+// the app is mounted under; [PathMaker.URL] adds it.
 func Encode(m any) (Location, error) {
 	switch m := (any)(m).(type) {
 	case Location:
@@ -81,6 +84,7 @@ func get(sample any) (adapter, error) {
 		sample:     sample,
 		fields:     make(map[string]field),
 		queryField: -1,
+		textTypes:  make(map[reflect.Type]struct{}),
 	}.build()
 	if err != nil {
 		return adapter, err
@@ -112,9 +116,11 @@ func (ma ModelAdapter[M]) Encode(m *M) (Location, error) {
 }
 
 type adapter struct {
-	prefix     *branch
-	branches   []fieldBranch
-	queryField int
+	prefix       *branch
+	branches     []fieldBranch
+	queryField   int
+	queryDecoder *form.Decoder
+	queryEncoder *form.Encoder
 }
 
 func (a adapter) decode(l Location, ref any) bool {
@@ -134,13 +140,7 @@ func (a adapter) decode(l Location, ref any) bool {
 			continue
 		}
 		mutations = append(mutations, branch.setMarker(v))
-		if a.queryField == -1 {
-			mutations = append(mutations, func() {
-				if err := queryDecoder.Decode(ref, l.Query); err != nil {
-					slog.Error("Query decoding error", "error", err)
-				}
-			})
-		} else {
+		if a.queryField != -1 {
 			query := cloneQuery(l.Query)
 			mutations = append(mutations, func() {
 				v.Field(a.queryField).Set(reflect.ValueOf(query))
@@ -152,6 +152,14 @@ func (a adapter) decode(l Location, ref any) bool {
 		for _, mutation := range mutations {
 			mutation()
 		}
+		if a.queryField == -1 {
+			if err := a.queryDecoder.Decode(ref, l.Query); err != nil {
+				if isTextQueryError(err) {
+					return false
+				}
+				slog.Error("Query decoding error", "error", err)
+			}
+		}
 		return true
 	}
 	return false
@@ -182,7 +190,7 @@ func (a adapter) encode(m any) (Location, error) {
 		}
 		var query url.Values
 		if a.queryField == -1 {
-			query, err = queryEncoder.Encode(m)
+			query, err = a.queryEncoder.Encode(m)
 			if err != nil {
 				return Location{}, err
 			}
@@ -197,14 +205,19 @@ func (a adapter) encode(m any) (Location, error) {
 	return Location{}, errors.New("no path variant selected")
 }
 
-var queryDecoder *form.Decoder
-var queryEncoder *form.Encoder
+var queryDecoder = newQueryDecoder()
+var queryEncoder = newQueryEncoder()
+
+func newQueryDecoder() *form.Decoder {
+	dec := form.NewDecoder()
+	dec.SetMode(form.ModeExplicit)
+	dec.SetTagName("query")
+	return dec
+}
 
-func init() {
-	queryDecoder = form.NewDecoder()
-	queryDecoder.SetMode(form.ModeExplicit)
-	queryDecoder.SetTagName("query")
-	queryEncoder = form.NewEncoder()
-	queryEncoder.SetMode(form.ModeExplicit)
-	queryEncoder.SetTagName("query")
+func newQueryEncoder() *form.Encoder {
+	enc := form.NewEncoder()
+	enc.SetMode(form.ModeExplicit)
+	enc.SetTagName("query")
+	return enc
 }
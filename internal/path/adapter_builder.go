@@ -36,6 +36,7 @@ type adapterBuilder struct {
 	fields       map[string]field
 	multiPattern bool
 	queryField   int
+	textTypes    map[reflect.Type]struct{}
 }
 
 func (a adapterBuilder) build() (adapter, error) {
@@ -61,10 +62,16 @@ func (a adapterBuilder) build() (adapter, error) {
 		}
 		prefix = &branch
 	}
+	dec, enc := queryDecoder, queryEncoder
+	if len(a.textTypes) != 0 {
+		dec, enc = newQueryCodec(a.textTypes)
+	}
 	return adapter{
-		prefix:     prefix,
-		branches:   branches,
-		queryField: a.queryField,
+		prefix:       prefix,
+		branches:     branches,
+		queryField:   a.queryField,
+		queryDecoder: dec,
+		queryEncoder: enc,
 	}, nil
 }
 
@@ -122,6 +129,7 @@ func (a *adapterBuilder) scanFields() error {
 			if !f.IsExported() {
 				return errors.New("query field " + f.Name + " must be exported")
 			}
+			collectTextTypes(f.Type, a.textTypes)
 		}
 		if f.Type == reflect.TypeFor[url.Values]() {
 			if !f.IsExported() {
@@ -143,6 +151,14 @@ func (a *adapterBuilder) addField(f reflect.StructField, index int) {
 		return
 	}
 	var kind fieldKind
+	switch {
+	case isTextType(f.Type):
+		a.fields[f.Name] = newSingleField(index, kindText)
+		return
+	case f.Type.Kind() == reflect.Pointer && isTextType(f.Type.Elem()):
+		a.fields[f.Name] = newSingleField(index, kindTextPtr)
+		return
+	}
 	switch f.Type.Kind() {
 	case reflect.Slice:
 		if f.Type != reflect.TypeFor[[]string]() {
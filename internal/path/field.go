@@ -67,10 +67,12 @@ const (
 	kindUint
 	kindInt
 	kindFloat
+	kindText
 	kindStringPtr
 	kindUintPtr
 	kindIntPtr
 	kindFloatPtr
+	kindTextPtr
 )
 
 func (f fieldKind) isPtr() bool {
@@ -122,11 +124,26 @@ func (f singleField) get(m reflect.Value) (string, bool) {
 			return "", false
 		}
 		return strconv.FormatFloat(field.Elem().Float(), 'g', -1, 64), true
+	case kindText:
+		return textValue(field)
+	case kindTextPtr:
+		if field.IsNil() {
+			return "", false
+		}
+		return textValue(field.Elem())
 	default:
 		panic("unknown field type")
 	}
 }
 
+func textValue(field reflect.Value) (string, bool) {
+	val, err := marshalText(field)
+	if err != nil || val == "" {
+		return "", false
+	}
+	return val, true
+}
+
 func (f singleField) set(m reflect.Value, v string) (func(), bool) {
 	field := m.Field(f.index)
 	switch f.kind {
@@ -198,6 +215,25 @@ func (f singleField) set(m reflect.Value, v string) (func(), bool) {
 			}
 			field.Elem().SetFloat(num)
 		}, true
+	case kindText:
+		val, err := unmarshalText(field.Type(), v)
+		if err != nil {
+			return nil, false
+		}
+		return func() {
+			field.Set(val)
+		}, true
+	case kindTextPtr:
+		val, err := unmarshalText(field.Type().Elem(), v)
+		if err != nil {
+			return nil, false
+		}
+		return func() {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field.Elem().Set(val)
+		}, true
 	default:
 		panic("unknown field type")
 	}
@@ -62,7 +62,8 @@ func (l Location) Path() string {
 	return b.String()
 }
 
-// String returns l encoded as `/<segments>?<query>`.
+// String returns l encoded as `/<segments>?<query>`, without any base path
+// the app is mounted under; see [PathMaker.URL].
 func (l Location) String() string {
 	b := strings.Builder{}
 	b.WriteByte('/')
@@ -84,7 +85,8 @@ func EqualLocation(a, b Location) bool {
 	return slices.Equal(a.Segments, b.Segments) && maps.EqualFunc(a.Query, b.Query, slices.Equal)
 }
 
-// NewLocationFromEscapedURI parses s into a [Location].
+// NewLocationFromEscapedURI parses s into a [Location]. Like
+// [NewLocationFromURL], it keeps any base path in the result.
 func NewLocationFromEscapedURI(s string) Location {
 	u, err := url.Parse(s)
 	if err != nil {
@@ -93,14 +95,20 @@ func NewLocationFromEscapedURI(s string) Location {
 	return NewLocationFromURL(u)
 }
 
-// NewLocationFromURL decodes u into a [Location].
+// NewLocationFromURL decodes u into a [Location]. It doesn't know the base
+// path the app is mounted under and keeps it as leading segments; use
+// [PathMaker.Location] to decode a URL relative to the base path.
 func NewLocationFromURL(u *url.URL) Location {
+	return newLocation(u.EscapedPath(), u.Query())
+}
+
+func newLocation(escapedPath string, query url.Values) Location {
 	parts := make([]string, 0)
-	trimmed := strings.Trim(u.EscapedPath(), "/")
+	trimmed := strings.Trim(escapedPath, "/")
 	if trimmed == "" {
 		return Location{
 			Segments: parts,
-			Query:    u.Query(),
+			Query:    query,
 		}
 	}
 	for part := range strings.SplitSeq(trimmed, "/") {
@@ -113,6 +121,6 @@ func NewLocationFromURL(u *url.URL) Location {
 	}
 	return Location{
 		Segments: parts,
-		Query:    u.Query(),
+		Query:    query,
 	}
 }
@@ -37,6 +37,57 @@ type PathMaker struct {
 	serverID           string
 	sessionCookie      string
 	serverIDCookieName string
+	base               string
+}
+
+// WithBase returns a copy of pm that mounts the app under base. Page URLs
+// and system routes are prefixed with it, and request paths outside it do
+// not belong to the app.
+func (pm PathMaker) WithBase(base string) PathMaker {
+	base = strings.Trim(strings.TrimSpace(base), "/")
+	if base != "" {
+		base = "/" + base
+	}
+	pm.base = base
+	return pm
+}
+
+// Base returns the normalized base path: empty, or a leading slash and no
+// trailing one.
+func (pm PathMaker) Base() string {
+	return pm.base
+}
+
+func (pm PathMaker) trimBase(escapedPath string) (string, bool) {
+	if pm.base == "" {
+		return escapedPath, true
+	}
+	rest, ok := strings.CutPrefix(escapedPath, pm.base)
+	if !ok {
+		return "", false
+	}
+	if rest == "" {
+		return "/", true
+	}
+	if rest[0] != '/' {
+		return "", false
+	}
+	return rest, true
+}
+
+// URL returns the escaped URL of l within the app, including the base path.
+func (pm PathMaker) URL(l Location) string {
+	return pm.base + l.String()
+}
+
+// Location decodes u into a [Location] relative to the base path. It
+// returns false when u is outside of the base path.
+func (pm PathMaker) Location(u *url.URL) (Location, bool) {
+	path, ok := pm.trimBase(u.EscapedPath())
+	if !ok {
+		return Location{}, false
+	}
+	return newLocation(path, u.Query()), true
 }
 
 type HookMatch struct {
@@ -102,15 +153,15 @@ func (pm PathMaker) SessionCookie() string {
 const systemPrefix = "/~/"
 
 func (pm PathMaker) Prefix() string {
-	return systemPrefix + pm.serverID
+	return pm.base + systemPrefix + pm.serverID
 }
 
 func (pm PathMaker) IsSystem(r *http.Request) bool {
-	return strings.HasPrefix(r.URL.Path, systemPrefix)
+	return strings.HasPrefix(r.URL.Path, pm.base+systemPrefix)
 }
 
 func (pm PathMaker) Match(r *http.Request) (Match, bool) {
-	tilde, ok := strings.CutPrefix(r.URL.RequestURI(), systemPrefix)
+	tilde, ok := strings.CutPrefix(r.URL.RequestURI(), pm.base+systemPrefix)
 	if !ok {
 		return Match{}, false
 	}
@@ -159,8 +210,14 @@ func (pm PathMaker) Match(r *http.Request) (Match, bool) {
 	matches = undoPath.FindStringSubmatch(path)
 	if len(matches) != 0 {
 		instanceID := matches[1]
-		path := matches[2]
-		l := NewLocationFromEscapedURI(path)
+		u, err := url.Parse(matches[2])
+		if err != nil {
+			return Match{}, false
+		}
+		l, ok := pm.Location(u)
+		if !ok {
+			return Match{}, false
+		}
 		return Match{
 			entity: UndoPath{
 				Instance: instanceID,
@@ -171,6 +228,11 @@ func (pm PathMaker) Match(r *http.Request) (Match, bool) {
 	return Match{}, false
 }
 
+// CSPReport returns the path of the built-in CSP report endpoint.
+func (pm PathMaker) CSPReport() string {
+	return pm.Prefix() + "/c"
+}
+
 func (pm PathMaker) Hook(instanceId string, hookId uint64, name string) string {
 	builder := &strings.Builder{}
 	fmt.Fprintf(builder, "%s/h/%s/%d", pm.Prefix(), instanceId, hookId)
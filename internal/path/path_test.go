@@ -16,11 +16,14 @@ package path
 
 import (
 	"fmt"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNotStruct(t *testing.T) {
@@ -271,3 +274,92 @@ func TestQuery(t *testing.T) {
 	}
 
 }
+
+type textID int
+
+func (id textID) MarshalText() ([]byte, error) {
+	return []byte("id-" + strconv.Itoa(int(id))), nil
+}
+
+func (id *textID) UnmarshalText(text []byte) error {
+	num, ok := strings.CutPrefix(string(text), "id-")
+	if !ok {
+		return fmt.Errorf("invalid id %q", text)
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return err
+	}
+	*id = textID(n)
+	return nil
+}
+
+func TestTextFields(t *testing.T) {
+	type path struct {
+		V     bool `path:"/item/:ID/:Rev?"`
+		ID    textID
+		Rev   *textID
+		Tags  []textID  `query:"tag"`
+		After time.Time `query:"after"`
+		Pages []int     `query:"page"`
+	}
+	v, _ := testPath[path](t, "/item/id-7?after=2026-01-02T00%3A00%3A00Z&page=1&page=2&tag=id-1&tag=id-2", true)
+	if v.ID != 7 || v.Rev != nil {
+		t.Fatalf("unexpected path fields: %#v", v)
+	}
+	if !slices.Equal(v.Tags, []textID{1, 2}) || !slices.Equal(v.Pages, []int{1, 2}) {
+		t.Fatalf("unexpected slice query fields: %#v", v)
+	}
+	if !v.After.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected time query field: %v", v.After)
+	}
+	v, _ = testPath[path](t, "/item/id-7/id-8?after=2026-01-02T00%3A00%3A00Z", true)
+	if v.Rev == nil || *v.Rev != 8 {
+		t.Fatalf("unexpected optional text field: %#v", v.Rev)
+	}
+	testPath[path](t, "/item/7", false)
+	testPath[path](t, "/item/id-7?tag=bad", false)
+	testPath[path](t, "/item/id-7?after=yesterday", false)
+}
+
+func TestPathMakerBase(t *testing.T) {
+	pm := NewPathMaker("__Host-", "blue", "").WithBase("/admin/")
+	if pm.Base() != "/admin" || pm.Prefix() != "/admin/~/blue" {
+		t.Fatalf("unexpected base %q and prefix %q", pm.Base(), pm.Prefix())
+	}
+	loc := Location{Segments: []string{"docs"}, Query: url.Values{"tag": {"x"}}}
+	if got := pm.URL(loc); got != "/admin/docs?tag=x" {
+		t.Fatalf("unexpected url: %q", got)
+	}
+	for uri, want := range map[string]string{
+		"/admin":          "/",
+		"/admin/":         "/",
+		"/admin/docs?a=1": "/docs?a=1",
+	} {
+		u, _ := url.Parse(uri)
+		l, ok := pm.Location(u)
+		if !ok || l.String() != want {
+			t.Fatalf("location of %q = %q, %v", uri, l.String(), ok)
+		}
+	}
+	for _, uri := range []string{"/", "/docs", "/administrator"} {
+		u, _ := url.Parse(uri)
+		if _, ok := pm.Location(u); ok {
+			t.Fatalf("expected %q to be outside of the base path", uri)
+		}
+	}
+	if _, ok := pm.Match(httptest.NewRequest("GET", "/~/blue/s/inst1", nil)); ok {
+		t.Fatal("expected system path without base not to match")
+	}
+	match, ok := pm.Match(httptest.NewRequest("GET", pm.Prefix()+"/u/inst1/admin/docs?tag=x", nil))
+	if !ok {
+		t.Fatal("expected undo path to match")
+	}
+	undo, _ := match.Undo()
+	if undo.Instance != "inst1" || undo.Location.String() != "/docs?tag=x" {
+		t.Fatalf("unexpected undo match: %#v", undo)
+	}
+	if !pm.IsSystem(httptest.NewRequest("GET", pm.Hook("inst1", 1, ""), nil)) {
+		t.Fatal("expected hook path to be a system path")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package path
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+
+	"github.com/go-playground/form/v4"
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isTextType reports whether values of t can be both marshaled and
+// unmarshaled as text, with either value or pointer receivers.
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		return false
+	}
+	ptr := reflect.PointerTo(t)
+	if !ptr.Implements(textUnmarshalerType) {
+		return false
+	}
+	return t.Implements(textMarshalerType) || ptr.Implements(textMarshalerType)
+}
+
+func marshalText(v reflect.Value) (string, error) {
+	if !v.Type().Implements(textMarshalerType) {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+func unmarshalText(t reflect.Type, text string) (reflect.Value, error) {
+	ptr := reflect.New(t)
+	if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+		return reflect.Value{}, err
+	}
+	return ptr.Elem(), nil
+}
+
+// textQueryError marks query decode failures of text fields, which make the
+// location a non-match instead of being ignored.
+type textQueryError struct {
+	err error
+}
+
+func (e textQueryError) Error() string {
+	return e.err.Error()
+}
+
+func (e textQueryError) Unwrap() error {
+	return e.err
+}
+
+func isTextQueryError(err error) bool {
+	var decodeErrs form.DecodeErrors
+	if !errors.As(err, &decodeErrs) {
+		return false
+	}
+	for _, err := range decodeErrs {
+		if errors.As(err, &textQueryError{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectTextTypes adds text types reachable from a query field type through
+// pointers and slices.
+func collectTextTypes(t reflect.Type, types map[reflect.Type]struct{}) {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			t = t.Elem()
+			continue
+		}
+		break
+	}
+	if isTextType(t) {
+		types[t] = struct{}{}
+	}
+}
+
+// newQueryCodec returns a query decoder and encoder that handle the text
+// types in addition to what form supports natively. Slices of text types
+// are registered too, so they encode as repeated keys like other slices.
+func newQueryCodec(types map[reflect.Type]struct{}) (*form.Decoder, *form.Encoder) {
+	dec := newQueryDecoder()
+	enc := newQueryEncoder()
+	for t := range types {
+		dec.RegisterCustomTypeFunc(func(vals []string) (any, error) {
+			if vals[0] == "" {
+				return reflect.Zero(t).Interface(), nil
+			}
+			v, err := unmarshalText(t, vals[0])
+			if err != nil {
+				return nil, textQueryError{err: err}
+			}
+			return v.Interface(), nil
+		}, reflect.Zero(t).Interface())
+		enc.RegisterCustomTypeFunc(func(x any) ([]string, error) {
+			text, err := marshalText(reflect.ValueOf(x))
+			if err != nil {
+				return nil, err
+			}
+			return []string{text}, nil
+		}, reflect.Zero(t).Interface())
+		slice := reflect.SliceOf(t)
+		dec.RegisterCustomTypeFunc(func(vals []string) (any, error) {
+			s := reflect.MakeSlice(slice, 0, len(vals))
+			for _, val := range vals {
+				if val == "" {
+					continue
+				}
+				v, err := unmarshalText(t, val)
+				if err != nil {
+					return nil, textQueryError{err: err}
+				}
+				s = reflect.Append(s, v)
+			}
+			return s.Interface(), nil
+		}, reflect.Zero(slice).Interface())
+		enc.RegisterCustomTypeFunc(func(x any) ([]string, error) {
+			s := reflect.ValueOf(x)
+			vals := make([]string, 0, s.Len())
+			for i := range s.Len() {
+				text, err := marshalText(s.Index(i))
+				if err != nil {
+					return nil, err
+				}
+				vals = append(vals, text)
+			}
+			return vals, nil
+		}, reflect.Zero(slice).Interface())
+	}
+	return dec, enc
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"github.com/doors-dev/doors/internal/resources"
+)
+
+// ScriptAsset returns the asset a <script> with src and the given attributes
+// builds, so it can be built ahead of time.
+func ScriptAsset(src SourceStatic, module bool, bundle bool, inline bool, ts bool, profile string) (resources.Asset, error) {
+	output := scriptDefault
+	switch {
+	case bundle:
+		output = scriptBundle
+	case inline:
+		output = scriptInline
+	}
+	format, err := output.format(module)
+	if err != nil {
+		return resources.Asset{}, err
+	}
+	return resources.Asset{
+		Script:  src.scriptEntry(output == scriptInline, ts),
+		Format:  format,
+		Profile: profile,
+	}, nil
+}
+
+// StyleAsset returns the asset a stylesheet with src builds, so it can be
+// built ahead of time.
+func StyleAsset(src SourceStatic) resources.Asset {
+	return resources.Asset{
+		Style:  src.styleEntry(),
+		Minify: true,
+	}
+}
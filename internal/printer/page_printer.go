@@ -21,9 +21,15 @@ import (
 	"strings"
 )
 
-func NewPagePrinter(w io.Writer, static bool, include gox.Elem, importMap []byte, meta gox.Editor) gox.Printer {
+// Head renders the title and meta tags of a page and provides its html lang.
+type Head interface {
+	gox.Editor
+	Lang() string
+}
+
+func NewPagePrinter(w io.Writer, static bool, include gox.Elem, importMap []byte, nonce string, meta Head) gox.Printer {
 	cur := gox.NewCursor(context.Background(), defaultPrinter{w})
-	return &pagePrinter{cur: cur, static: static, include: include, importMap: importMap, meta: meta}
+	return &pagePrinter{cur: cur, static: static, include: include, importMap: importMap, nonce: nonce, meta: meta}
 }
 
 type pagePrinterState int
@@ -39,8 +45,9 @@ type pagePrinter struct {
 	static    bool
 	include   gox.Elem
 	importMap []byte
+	nonce     string
 	state     pagePrinterState
-	meta      gox.Editor
+	meta      Head
 	headID    uint64
 }
 
@@ -62,6 +69,12 @@ func (p *pagePrinter) scan(j gox.Job) error {
 	if !ok {
 		return p.cur.Printer().Send(j)
 	}
+	if strings.EqualFold(openJob.Tag, "html") {
+		if lang := p.meta.Lang(); lang != "" {
+			openJob.Attrs.Get("lang").Set(lang)
+		}
+		return p.cur.Printer().Send(j)
+	}
 	if strings.EqualFold(openJob.Tag, "head") {
 		p.headID = openJob.ID
 		p.state = pageHead
@@ -138,6 +151,11 @@ func (p *pagePrinter) insert() error {
 		if err := p.cur.Set("type", "importmap"); err != nil {
 			return err
 		}
+		if p.nonce != "" {
+			if err := p.cur.Set("nonce", p.nonce); err != nil {
+				return err
+			}
+		}
 		if err := p.cur.Submit(); err != nil {
 			return err
 		}
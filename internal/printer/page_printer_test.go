@@ -32,10 +32,19 @@ import (
 	"github.com/evanw/esbuild/pkg/api"
 )
 
-func noMeta() gox.Editor {
-	return gox.EditorFunc(func(cur gox.Cursor) error {
+type testHead struct {
+	gox.Editor
+	lang string
+}
+
+func (h testHead) Lang() string {
+	return h.lang
+}
+
+func noMeta() Head {
+	return testHead{Editor: gox.EditorFunc(func(cur gox.Cursor) error {
 		return nil
-	})
+	})}
 }
 
 func indexAny(s string, parts ...string) int {
@@ -78,7 +87,7 @@ func TestPagePrinterInsertsHeadBeforeBody(t *testing.T) {
 		return cur.Submit()
 	})
 
-	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"app":"/app.js"}}`), meta)
+	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"app":"/app.js"}}`), "", testHead{Editor: meta})
 
 	if err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "body", gox.NewAttrs())); err != nil {
 		t.Fatal(err)
@@ -125,7 +134,7 @@ func TestPagePrinterInsertsIntoExplicitHead(t *testing.T) {
 		return cur.Submit()
 	})
 
-	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"extra":"/extra.js"}}`), meta)
+	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"extra":"/extra.js"}}`), "", testHead{Editor: meta})
 
 	if err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "head", gox.NewAttrs())); err != nil {
 		t.Fatal(err)
@@ -155,7 +164,7 @@ func TestPagePrinterInsertsIntoExplicitHead(t *testing.T) {
 
 func TestPagePrinterInsertsBeforeFirstScript(t *testing.T) {
 	var out bytes.Buffer
-	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"boot":"/boot.js"}}`), noMeta())
+	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"boot":"/boot.js"}}`), "", noMeta())
 
 	if err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "script", gox.NewAttrs())); err != nil {
 		t.Fatal(err)
@@ -172,7 +181,7 @@ func TestPagePrinterInsertsBeforeFirstScript(t *testing.T) {
 
 func TestPagePrinterInsertsInsideHeadBeforeNestedScript(t *testing.T) {
 	var out bytes.Buffer
-	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"head":"/head.js"}}`), noMeta())
+	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"head":"/head.js"}}`), "", noMeta())
 
 	if err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "head", gox.NewAttrs())); err != nil {
 		t.Fatal(err)
@@ -203,7 +212,7 @@ func TestPagePrinterIncludesFrontAssetsWhenNotStatic(t *testing.T) {
 	ctx := context.WithValue(context.Background(), common.KeyCore, core.NewCore(titleDoor{inst: inst}))
 
 	var out bytes.Buffer
-	p := NewPagePrinter(&out, false, front.Include(inst), nil, noMeta())
+	p := NewPagePrinter(&out, false, front.Include(inst), nil, "", noMeta())
 	if err := p.Send(gox.NewJobHeadOpen(ctx, 1, gox.KindRegular, "body", gox.NewAttrs())); err != nil {
 		t.Fatal(err)
 	}
@@ -225,9 +234,9 @@ func TestPagePrinterIncludesFrontAssetsWhenNotStatic(t *testing.T) {
 
 func TestPagePrinterInsertedHeadPropagatesMetaError(t *testing.T) {
 	expected := errors.New("meta boom")
-	p := NewPagePrinter(&bytes.Buffer{}, true, nil, nil, gox.EditorFunc(func(gox.Cursor) error {
+	p := NewPagePrinter(&bytes.Buffer{}, true, nil, nil, "", testHead{Editor: gox.EditorFunc(func(gox.Cursor) error {
 		return expected
-	}))
+	})})
 
 	err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "body", gox.NewAttrs()))
 	if !errors.Is(err, expected) {
@@ -237,7 +246,7 @@ func TestPagePrinterInsertedHeadPropagatesMetaError(t *testing.T) {
 
 func TestPagePrinterWaitsForMatchingHeadClose(t *testing.T) {
 	var out bytes.Buffer
-	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"late":"/late.js"}}`), noMeta())
+	p := NewPagePrinter(&out, true, nil, []byte(`{"imports":{"late":"/late.js"}}`), "", noMeta())
 
 	if err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "head", gox.NewAttrs())); err != nil {
 		t.Fatal(err)
@@ -255,3 +264,16 @@ func TestPagePrinterWaitsForMatchingHeadClose(t *testing.T) {
 		t.Fatalf("expected importmap after matching close, got %q", out.String())
 	}
 }
+
+func TestPagePrinterSetsHTMLLang(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPagePrinter(&out, true, nil, nil, "", testHead{Editor: noMeta(), lang: "de"})
+	attrs := gox.NewAttrs()
+	attrs.Get("lang").Set("en")
+	if err := p.Send(gox.NewJobHeadOpen(context.Background(), 1, gox.KindRegular, "html", attrs)); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != `<html lang="de">` {
+		t.Fatalf("expected head lang to win, got %q", got)
+	}
+}
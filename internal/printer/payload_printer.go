@@ -15,7 +15,6 @@
 package printer
 
 import (
-	"compress/gzip"
 	"sync"
 
 	"github.com/doors-dev/doors/internal/common"
@@ -42,20 +41,24 @@ type Payload interface {
 }
 
 type PayloadPrinter struct {
-	buf     sliceWriter
-	gzip    *gzip.Writer
-	printer gox.Printer
+	buf         sliceWriter
+	compression common.Compression
+	compressor  common.Compressor
+	printer     gox.Printer
 }
 
 var _ Payload = (*PayloadPrinter)(nil)
 
-func NewPayloadPrinter(disableGzip bool) *PayloadPrinter {
+// NewPayloadPrinter returns a printer compressing its output with c, or
+// leaving it as is if c.Encoding is empty.
+func NewPayloadPrinter(c common.Compression) *PayloadPrinter {
 	b := &PayloadPrinter{
-		buf: bufferPrinterPool.Get().(sliceWriter),
+		buf:         bufferPrinterPool.Get().(sliceWriter),
+		compression: c,
 	}
-	if !disableGzip {
-		b.gzip = common.GetGzipWriter(&b.buf)
-		b.printer = defaultPrinter{b.gzip}
+	if c.Encoding != "" {
+		b.compressor = common.GetCompressor(&b.buf, c, false)
+		b.printer = defaultPrinter{b.compressor}
 	} else {
 		b.printer = defaultPrinter{&b.buf}
 	}
@@ -63,16 +66,16 @@ func NewPayloadPrinter(disableGzip bool) *PayloadPrinter {
 }
 
 func (b *PayloadPrinter) Payload() action.Payload {
-	if b.gzip != nil {
-		return action.NewTextGZ(b.buf)
+	if b.compressor != nil {
+		return action.NewEncoded(action.PayloadText, b.compression.Encoding, b.buf)
 	}
 	return action.NewTextBytes(b.buf)
 }
 
 func (b *PayloadPrinter) Release() {
-	if b.gzip != nil {
-		common.PutGzipWriter(b.gzip)
-		b.gzip = nil
+	if b.compressor != nil {
+		common.PutCompressor(b.compressor, b.compression, false)
+		b.compressor = nil
 	}
 	if b.buf == nil {
 		return
@@ -83,10 +86,10 @@ func (b *PayloadPrinter) Release() {
 }
 
 func (b *PayloadPrinter) Finalize() {
-	if b.gzip == nil {
+	if b.compressor == nil {
 		return
 	}
-	b.gzip.Close()
+	b.compressor.Close()
 }
 
 func (b *PayloadPrinter) Send(job gox.Job) error {
@@ -192,6 +192,7 @@ func (r *resourceProps) readSource(attr gox.Attr) {
 }
 
 func (r *resourceProps) resourceURL(core core.Core, res *resources.Resource) (string, error) {
+	core.Door().Clean(core.App().ResourceRegistry().Hold(res))
 	mode := r.mode
 	switch mode {
 	case resources.ModeHost:
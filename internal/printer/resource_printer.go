@@ -59,6 +59,7 @@ func (p *resourcePrinter) scan(job gox.Job) error {
 	case strings.EqualFold(openJob.Tag, "meta"):
 		return p.processMeta(openJob)
 	case strings.EqualFold(openJob.Tag, "script"):
+		setNonce(openJob)
 		props := newScriptProps(false)
 		return p.processProps(openJob, props)
 	case strings.EqualFold(openJob.Tag, "link"):
@@ -71,15 +72,21 @@ func (p *resourcePrinter) scan(job gox.Job) error {
 			return p.scanGenericSrc(openJob)
 		}
 		if strings.EqualFold(str, "stylesheet") {
+			setNonce(openJob)
 			props := newStyleProps(true)
 			return p.processProps(openJob, props)
 		}
 		if strings.EqualFold(str, "modulepreload") {
+			setNonce(openJob)
 			props := newScriptProps(true)
 			return p.processProps(openJob, props)
 		}
+		if strings.EqualFold(str, "alternate") && openJob.Attrs.Has("hreflang") {
+			return p.processAlternate(openJob)
+		}
 		return p.scanGenericSrc(openJob)
 	case strings.EqualFold(openJob.Tag, "style"):
+		setNonce(openJob)
 		props := newStyleProps(false)
 		return p.processProps(openJob, props)
 	default:
@@ -90,6 +97,22 @@ func (p *resourcePrinter) scan(job gox.Job) error {
 	}
 }
 
+// setNonce adds the CSP nonce of the page to a script or style tag that
+// doesn't set its own.
+func setNonce(open *gox.JobHeadOpen) {
+	core, ok := open.Context().Value(common.KeyCore).(core.Core)
+	if !ok {
+		return
+	}
+	nonce := core.Instance().CSPNonce()
+	if nonce == "" {
+		return
+	}
+	if attr := open.Attrs.Get("nonce"); !attr.IsSet() {
+		attr.Set(nonce)
+	}
+}
+
 func (p *resourcePrinter) processProps(open *gox.JobHeadOpen, props props) error {
 	match, err := props.Read(open.Attrs)
 	if err != nil {
@@ -135,6 +158,24 @@ func (p *resourcePrinter) processMeta(openJob *gox.JobHeadOpen) error {
 	return nil
 }
 
+func (p *resourcePrinter) processAlternate(openJob *gox.JobHeadOpen) error {
+	if openJob.Kind != gox.KindVoid {
+		return errors.New("<link> must be a void element")
+	}
+	attr := openJob.Attrs.Get("hreflang")
+	b := &bytes.Buffer{}
+	if err := attr.OutputValue(b); err != nil {
+		return err
+	}
+	attr.Unset()
+	openJob.Attrs.Get("rel").Unset()
+	core := openJob.Context().Value(common.KeyCore).(core.Core)
+	cancel := core.Instance().TitleMeta().UpdateAlternate(b.String(), openJob.Attrs.Clone())
+	core.Door().Clean(cancel)
+	gox.Release(openJob)
+	return nil
+}
+
 func (r *resourcePrinter) processTitle(j gox.Job, tit *title) error {
 	if _, ok := j.(*gox.JobHeadOpen); ok {
 		return errors.New("<title> cannot contain nested tags")
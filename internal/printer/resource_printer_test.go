@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -46,8 +47,10 @@ type titleInstance struct {
 	registry   resources.Registry
 	conf       common.Conf
 	csp        common.CSPCollector
+	nonce      string
 	modules    *testModuleRegistry
 	metas      []testMetaUpdate
+	alternates []testMetaUpdate
 	session    *titleSession
 	location   beam.Source[path.Location]
 }
@@ -65,22 +68,27 @@ func (t *titleInstance) CSPCollector() common.CSPCollector {
 	}
 	return (&common.CSP{}).NewCollector()
 }
-func (t *titleInstance) ModuleRegistry() core.ModuleRegistry  { return t.modules }
-func (t *titleInstance) ID() string                           { return "instance" }
-func (t *titleInstance) LastSeen() time.Time                  { return time.Time{} }
-func (t *titleInstance) RootID() uint64                       { return 1 }
-func (t *titleInstance) NewID() uint64                        { return 1 }
-func (t *titleInstance) Runtime() shredder.Runtime            { return nil }
-func (t *titleInstance) SetStatus(int)                        {}
-func (t *titleInstance) Session() core.Session                { return t.session }
-func (t *titleInstance) Store() ctex.Store                    { return ctex.NewStore() }
-func (t *titleInstance) Location() beam.Source[path.Location] { return t.location }
-func (t *titleInstance) Kill()                                {}
-func (t *titleInstance) TitleMeta() core.TitleMeta            { return t }
-func (t *titleInstance) Logger() *slog.Logger                 { return slog.Default() }
-func (t *titleInstance) PathMaker() path.PathMaker            { return t.session.app.PathMaker() }
-func (t *titleInstance) Edit(cur gox.Cursor) error            { return nil }
-func (t *titleInstance) Main() gox.Elem                       { return nil }
+func (t *titleInstance) ModuleRegistry() core.ModuleRegistry       { return t.modules }
+func (t *titleInstance) ID() string                                { return "instance" }
+func (t *titleInstance) Token() string                             { return "" }
+func (t *titleInstance) CSPNonce() string                          { return t.nonce }
+func (t *titleInstance) LastSeen() time.Time                       { return time.Time{} }
+func (t *titleInstance) RootID() uint64                            { return 1 }
+func (t *titleInstance) NewID() uint64                             { return 1 }
+func (t *titleInstance) Runtime() shredder.Runtime                 { return nil }
+func (t *titleInstance) SetStatus(int)                             {}
+func (t *titleInstance) SetSecurityHeaders(common.SecurityHeaders) {}
+func (t *titleInstance) Redirect(string, int) bool                 { return false }
+func (t *titleInstance) Session() core.Session                     { return t.session }
+func (t *titleInstance) Store() ctex.Store                         { return ctex.NewStore() }
+func (t *titleInstance) Location() beam.Source[path.Location]      { return t.location }
+func (t *titleInstance) Kill()                                     {}
+func (t *titleInstance) TitleMeta() core.TitleMeta                 { return t }
+func (t *titleInstance) PayloadCompression() common.Compression    { return common.Compression{} }
+func (t *titleInstance) Logger() *slog.Logger                      { return slog.Default() }
+func (t *titleInstance) PathMaker() path.PathMaker                 { return t.session.app.PathMaker() }
+func (t *titleInstance) Edit(cur gox.Cursor) error                 { return nil }
+func (t *titleInstance) Main() gox.Elem                            { return nil }
 func (t *titleInstance) UpdateTitle(content string, attrs gox.Attrs) context.CancelFunc {
 	t.title = content
 	t.titleAttrs = attrs
@@ -90,6 +98,14 @@ func (t *titleInstance) UpdateMeta(property bool, name string, attrs gox.Attrs)
 	t.metas = append(t.metas, testMetaUpdate{name: name, property: property, attrs: attrs})
 	return func() {}
 }
+func (t *titleInstance) UpdateAlternate(hreflang string, attrs gox.Attrs) context.CancelFunc {
+	t.alternates = append(t.alternates, testMetaUpdate{name: hreflang, attrs: attrs})
+	return func() {}
+}
+func (t *titleInstance) UpdateLang(lang string) context.CancelFunc {
+	return func() {}
+}
+func (t *titleInstance) Lang() string { return "" }
 
 type titleApp struct {
 	conf     *common.Conf
@@ -116,6 +132,16 @@ func (a titleApp) Draining() bool {
 	return false
 }
 
+func (a titleApp) WriteMetrics(w io.Writer) error {
+	return nil
+}
+
+func (a titleApp) Tracer() common.Tracer {
+	return common.NoopTracer{}
+}
+
+func (a titleApp) HookLimited(ctx context.Context, abuse common.HookAbuse) {}
+
 type titleSession struct {
 	app titleApp
 }
@@ -129,7 +155,11 @@ func (s titleSession) Store() ctex.Store    { return ctex.NewStore() }
 func (s titleSession) ID() string           { return "session" }
 func (s titleSession) LastSeen() time.Time  { return time.Time{} }
 func (s titleSession) Expire(time.Duration) {}
-func (s titleSession) Kill()                {}
+func (s titleSession) Tag(string, string)   {}
+func (s titleSession) Tags() map[string]string {
+	return nil
+}
+func (s titleSession) Kill() {}
 
 type titleDoor struct {
 	inst *titleInstance
@@ -458,6 +488,36 @@ func TestProcessMetaBranches(t *testing.T) {
 	}
 }
 
+func TestProcessAlternate(t *testing.T) {
+	ctx, inst, _, _ := newPrinterCore(t, true)
+	var out bytes.Buffer
+	rp := &resourcePrinter{printer: defaultPrinter{&out}}
+
+	attrs := gox.NewAttrs()
+	attrs.Get("rel").Set("alternate")
+	attrs.Get("hreflang").Set("de")
+	attrs.Get("href").Set("/de/docs")
+	if err := rp.scan(gox.NewJobHeadOpen(ctx, 1, gox.KindVoid, "link", attrs)); err != nil {
+		t.Fatal(err)
+	}
+	if len(inst.alternates) != 1 || inst.alternates[0].name != "de" {
+		t.Fatalf("unexpected alternates %#v", inst.alternates)
+	}
+	if inst.alternates[0].attrs.Has("rel") || !inst.alternates[0].attrs.Has("href") {
+		t.Fatalf("unexpected stored alternate attrs")
+	}
+
+	feed := gox.NewAttrs()
+	feed.Get("rel").Set("alternate")
+	feed.Get("type").Set("application/rss+xml")
+	if err := rp.scan(gox.NewJobHeadOpen(ctx, 2, gox.KindVoid, "link", feed)); err != nil {
+		t.Fatal(err)
+	}
+	if len(inst.alternates) != 1 || !strings.Contains(out.String(), "application/rss+xml") {
+		t.Fatalf("expected alternate without hreflang to pass through, got %q", out.String())
+	}
+}
+
 func TestScanGenericSrcBranches(t *testing.T) {
 	ctx, _, _, _ := newPrinterCore(t, true)
 
@@ -968,6 +1028,50 @@ func TestResourcePrinterScanAndModulePreloadBranches(t *testing.T) {
 	})
 }
 
+func TestResourcePrinterNonce(t *testing.T) {
+	ctx, inst, _, _ := newPrinterCore(t, true)
+	inst.nonce = "abc"
+	for i, c := range []struct {
+		tag   string
+		attrs map[string]string
+		kind  gox.HeadKind
+	}{
+		{"script", map[string]string{"src": "/app.js"}, gox.KindRegular},
+		{"script", map[string]string{"src": "/own.js", "nonce": "own"}, gox.KindRegular},
+		{"link", map[string]string{"rel": "stylesheet", "href": "/app.css"}, gox.KindVoid},
+		{"link", map[string]string{"rel": "icon", "href": "/favicon.ico"}, gox.KindVoid},
+	} {
+		var out bytes.Buffer
+		rp := NewResourcePrinter(defaultPrinter{&out})
+		attrs := gox.NewAttrs()
+		for name, value := range c.attrs {
+			attrs.Get(name).Set(value)
+		}
+		id := uint64(10 + i)
+		if err := rp.Send(gox.NewJobHeadOpen(ctx, id, c.kind, c.tag, attrs)); err != nil {
+			t.Fatal(err)
+		}
+		if c.kind == gox.KindRegular {
+			if err := rp.Send(gox.NewJobHeadClose(ctx, id, c.kind, c.tag)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := `nonce="abc"`
+		switch {
+		case c.attrs["nonce"] != "":
+			want = `nonce="own"`
+		case c.attrs["rel"] == "icon":
+			if strings.Contains(out.String(), "nonce") {
+				t.Fatalf("unexpected nonce on %q", out.String())
+			}
+			continue
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %s in %q", want, out.String())
+		}
+	}
+}
+
 func plainAttrWithValue(value any) gox.Attr {
 	attrs := gox.NewAttrs()
 	attr := attrs.Get("value")
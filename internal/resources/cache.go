@@ -0,0 +1,240 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Stats is a snapshot of registry cache usage.
+type Stats struct {
+	// Entries is the number of cached resources.
+	Entries int
+	// Bytes is the total size of cached resources, compressed variants
+	// included.
+	Bytes int64
+	// Pinned is the number of cached resources held by live doors.
+	Pinned int
+	// Hits counts lookups served from the cache.
+	Hits uint64
+	// Misses counts lookups that had to read or build the resource.
+	Misses uint64
+	// Evictions counts resources dropped to stay within limits.
+	Evictions uint64
+}
+
+// cache is an LRU of built resources bounded by total size, compressed
+// variants included, and entry count. Entries held by live doors are pinned and never evicted.
+type cache struct {
+	mu        sync.Mutex
+	entries   map[[16]byte]*cacheEntry
+	hosted    map[string]*cacheEntry
+	lru       list.List
+	bytes     int64
+	pinned    int
+	maxBytes  int64
+	maxCount  int
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+type cacheEntry struct {
+	cache   *cache
+	key     [16]byte
+	res     *Resource
+	size    int64
+	hosted  bool
+	refs    int
+	evicted bool
+	elem    *list.Element
+}
+
+func newCache(maxBytes int64, maxCount int) *cache {
+	return &cache{
+		entries:  make(map[[16]byte]*cacheEntry),
+		hosted:   make(map[string]*cacheEntry),
+		maxBytes: maxBytes,
+		maxCount: maxCount,
+	}
+}
+
+func (c *cache) get(key [16]byte, host bool) *Resource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		c.misses += 1
+		return nil
+	}
+	c.hits += 1
+	c.lru.MoveToFront(e.elem)
+	if host {
+		c.host(e)
+	}
+	return e.res
+}
+
+func (c *cache) lookup(id string) *Resource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.hosted[id]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(e.elem)
+	return e.res
+}
+
+func (c *cache) add(key [16]byte, res *Resource, host bool) *Resource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e.elem)
+		if host {
+			c.host(e)
+		}
+		return e.res
+	}
+	e := &cacheEntry{
+		cache: c,
+		key:   key,
+		res:   res,
+		size:  int64(len(res.content)),
+	}
+	res.cached = e
+	e.elem = c.lru.PushFront(e)
+	c.entries[key] = e
+	c.bytes += e.size
+	if host {
+		c.host(e)
+	}
+	c.evict(e)
+	return res
+}
+
+func (c *cache) host(e *cacheEntry) {
+	e.hosted = true
+	c.hosted[e.res.id] = e
+}
+
+func (c *cache) over() bool {
+	if c.maxBytes > 0 && c.bytes > c.maxBytes {
+		return true
+	}
+	return c.maxCount > 0 && len(c.entries) > c.maxCount
+}
+
+func (c *cache) evict(keep *cacheEntry) {
+	elem := c.lru.Back()
+	for elem != nil && c.over() {
+		e := elem.Value.(*cacheEntry)
+		elem = elem.Prev()
+		if e == keep || e.refs > 0 {
+			continue
+		}
+		c.remove(e)
+		c.evictions += 1
+	}
+}
+
+func (c *cache) remove(e *cacheEntry) {
+	e.evicted = true
+	c.lru.Remove(e.elem)
+	delete(c.entries, e.key)
+	c.bytes -= e.size
+	if e.hosted && c.hosted[e.res.id] == e {
+		delete(c.hosted, e.res.id)
+	}
+}
+
+// grow accounts for a compressed variant built for the entry's resource.
+func (c *cache) grow(e *cacheEntry, n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.size += n
+	if e.evicted {
+		return
+	}
+	c.bytes += n
+	c.evict(e)
+}
+
+// restore puts an evicted entry back, so a resource rendered right after its
+// eviction is still served.
+func (c *cache) restore(e *cacheEntry) {
+	e.evicted = false
+	e.elem = c.lru.PushFront(e)
+	c.entries[e.key] = e
+	c.bytes += e.size
+	if e.hosted {
+		c.hosted[e.res.id] = e
+	}
+	c.evict(e)
+}
+
+func (c *cache) hold(res *Resource) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := res.cached
+	if e == nil {
+		return func() {}
+	}
+	if e.evicted {
+		if current, ok := c.entries[e.key]; ok {
+			// rebuilt under the same key, so it has the same content and id
+			if e.hosted {
+				c.host(current)
+			}
+			e = current
+		} else {
+			c.restore(e)
+		}
+	}
+	e.refs += 1
+	if e.refs == 1 {
+		c.pinned += 1
+	}
+	return sync.OnceFunc(func() {
+		c.release(e)
+	})
+}
+
+func (c *cache) release(e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refs -= 1
+	if e.refs != 0 {
+		return
+	}
+	c.pinned -= 1
+	if !e.evicted {
+		c.evict(nil)
+	}
+}
+
+func (c *cache) stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Entries:   len(c.entries),
+		Bytes:     c.bytes,
+		Pinned:    c.pinned,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
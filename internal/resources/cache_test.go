@@ -0,0 +1,104 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/doors-dev/doors/internal/common"
+)
+
+func TestRegistryEvictsUnpinnedResources(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	conf.ServerResourceCacheEntries = 2
+	rg := NewRegistry(resourceTestSettings{conf: &conf})
+
+	first, err := rg.Static(StaticString{Content: "first"}, "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := rg.Hold(first)
+	second, _ := rg.Static(StaticString{Content: "second"}, "text/plain")
+	third, _ := rg.Static(StaticString{Content: "third"}, "text/plain")
+
+	stats := rg.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.Pinned != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	serve := func(res *Resource) int {
+		rec := httptest.NewRecorder()
+		rg.Serve(res.ID(), rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+	if code := serve(first); code != http.StatusOK {
+		t.Fatalf("pinned resource status = %d", code)
+	}
+	if code := serve(second); code != http.StatusNotFound {
+		t.Fatalf("evicted resource status = %d", code)
+	}
+	if code := serve(third); code != http.StatusOK {
+		t.Fatalf("newest resource status = %d", code)
+	}
+
+	if again, _ := rg.Static(StaticString{Content: "first"}, "text/plain"); again != first {
+		t.Fatal("expected cache hit for pinned resource")
+	}
+	release()
+	release()
+	if stats := rg.Stats(); stats.Pinned != 0 || stats.Hits != 1 || stats.Misses != 3 {
+		t.Fatalf("unexpected stats after release: %+v", stats)
+	}
+}
+
+func TestRegistryCountsVariantsAndRestoresOnHold(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	conf.ServerResourceCacheEntries = 1
+	rg := NewRegistry(resourceTestSettings{conf: &conf})
+
+	content := strings.Repeat("compressible ", 100)
+	res, err := rg.Static(StaticString{Content: content}, "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rg.Serve(res.ID(), httptest.NewRecorder(), req)
+	if stats := rg.Stats(); stats.Bytes <= int64(len(content)) {
+		t.Fatalf("expected gzip variant to be counted, got %d bytes", stats.Bytes)
+	}
+
+	// evicted between lookup and Hold, as when a render races another one
+	other, _ := rg.Static(StaticString{Content: "other"}, "text/plain")
+	if stats := rg.Stats(); stats.Entries != 1 || stats.Evictions != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	release := rg.Hold(res)
+	defer release()
+	rec := httptest.NewRecorder()
+	rg.Serve(res.ID(), rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("held resource status = %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	rg.Serve(other.ID(), rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unpinned resource status = %d", rec.Code)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/doors-dev/doors/internal"
+	"github.com/evanw/esbuild/pkg/api"
+	"github.com/zeebo/xxh3"
+)
+
+// ManifestFile is the name of the manifest written by [Registry.Export].
+const ManifestFile = "manifest.json"
+
+// Asset is a script or style entry to build ahead of time. Exactly one of
+// Script and Style is set.
+type Asset struct {
+	Script  ScriptEntry
+	Format  ScriptFormat
+	Profile string
+	Style   StyleEntry
+	Minify  bool
+}
+
+func (a Asset) key(rs Registry) [16]byte {
+	if a.Script != nil {
+		return rs.scriptKey(a.Script, a.Format, a.Profile)
+	}
+	return styleKey(a.Style)
+}
+
+func (a Asset) build(rs Registry) (*Resource, error) {
+	if a.Script != nil {
+		if a.Format == nil {
+			return nil, errors.New("asset script has no format")
+		}
+		return rs.Script(a.Script, a.Format, a.Profile, ModeHost)
+	}
+	if a.Style == nil {
+		return nil, errors.New("asset has neither script nor style")
+	}
+	return rs.Style(a.Style, a.Minify, ModeHost)
+}
+
+type manifest struct {
+	fsys    fs.FS
+	entries map[[16]byte]manifestEntry
+}
+
+type manifestEntry struct {
+	File        string `json:"file"`
+	ContentType string `json:"type"`
+}
+
+type manifestJSON struct {
+	Entries map[string]manifestEntry `json:"entries"`
+}
+
+func readManifest(fsys fs.FS) (*manifest, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	var m manifestJSON
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid asset manifest: %w", err)
+	}
+	entries := make(map[[16]byte]manifestEntry, len(m.Entries))
+	for name, entry := range m.Entries {
+		raw, err := hex.DecodeString(name)
+		if err != nil || len(raw) != 16 {
+			return nil, fmt.Errorf("invalid asset manifest key %q", name)
+		}
+		if !fs.ValidPath(entry.File) {
+			return nil, fmt.Errorf("invalid asset manifest file %q", entry.File)
+		}
+		entries[[16]byte(raw)] = entry
+	}
+	return &manifest{
+		fsys:    fsys,
+		entries: entries,
+	}, nil
+}
+
+func (m *manifest) read(key [16]byte) ([]byte, string, bool, error) {
+	if m == nil {
+		return nil, "", false, nil
+	}
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, "", false, nil
+	}
+	content, err := fs.ReadFile(m.fsys, entry.File)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return content, entry.ContentType, true, nil
+}
+
+// clientKey identifies the client bundle by the hash of its sources and of
+// the esbuild profile it is built with, so a manifest built for another doors
+// version or profile is not used.
+func clientKey(opt *api.BuildOptions) [16]byte {
+	h := xxh3.New()
+	h.WriteString("client")
+	err := fs.WalkDir(internal.ClientSrc, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(internal.ClientSrc, path)
+		if err != nil {
+			return err
+		}
+		h.WriteString(path)
+		h.Write(content)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	profileID(h, opt)
+	return h.Sum128().Bytes()
+}
+
+// profileID writes the esbuild options that change the client output.
+// Plugins and other function values are left out since they have no stable
+// identity.
+func profileID(w idWriter, opt *api.BuildOptions) {
+	fmt.Fprintf(w, "|%d|%v|%d|%d|%t%t%t|%d|%d|%d|%d|%d|%s",
+		opt.Target,
+		opt.Engines,
+		opt.Platform,
+		opt.Sourcemap,
+		opt.MinifyWhitespace,
+		opt.MinifyIdentifiers,
+		opt.MinifySyntax,
+		opt.Charset,
+		opt.LegalComments,
+		opt.Drop,
+		opt.JSX,
+		opt.TreeShaking,
+		opt.SourceRoot,
+	)
+	for _, m := range []map[string]string{opt.Define, opt.Banner, opt.Footer} {
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			fmt.Fprintf(w, "|%s=%s", k, m[k])
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(opt.Supported)) {
+		fmt.Fprintf(w, "|%s=%t", k, opt.Supported[k])
+	}
+	fmt.Fprintf(w, "|%v|%v", opt.Pure, opt.DropLabels)
+}
+
+// LoadManifest makes the registry serve resources prebuilt by
+// [Registry.Export] from fsys instead of building them on first use. Entries
+// missing from the manifest are still built lazily.
+func (rs Registry) LoadManifest(fsys fs.FS) error {
+	m, err := readManifest(fsys)
+	if err != nil {
+		return err
+	}
+	rs.manifest = m
+	return nil
+}
+
+// BuildClient builds the client bundle unless already built, and returns the
+// build error instead of panicking. A failed build is not retried; every call
+// returns its error.
+func (rs Registry) BuildClient() error {
+	rs.initGuard.Do(rs.init)
+	return rs.clientErr
+}
+
+// Build builds assets into the cache and returns the joined build errors.
+// Assets found in a loaded manifest are read from it instead.
+func (rs Registry) Build(assets []Asset) error {
+	var errs []error
+	for _, asset := range assets {
+		if _, err := asset.build(rs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Export builds the client bundle and assets and writes them to dir with a
+// manifest that [Registry.LoadManifest] accepts. Output files are named by
+// content hash. It stops at the first build error.
+func (rs Registry) Export(dir string, assets []Asset) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	m := manifestJSON{
+		Entries: make(map[string]manifestEntry),
+	}
+	write := func(key [16]byte, res *Resource, ext string) error {
+		file := res.id + ext
+		if err := os.WriteFile(filepath.Join(dir, file), res.content, 0o644); err != nil {
+			return err
+		}
+		m.Entries[hex.EncodeToString(key[:])] = manifestEntry{
+			File:        file,
+			ContentType: res.contentType,
+		}
+		return nil
+	}
+	if err := rs.BuildClient(); err != nil {
+		return err
+	}
+	if err := write(rs.clientKey, rs.MainScript(), ".js"); err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		res, err := asset.build(rs)
+		if err != nil {
+			return err
+		}
+		ext := ".css"
+		if asset.Script != nil {
+			ext = ".js"
+		}
+		if err := write(asset.key(rs), res, ext); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644)
+}
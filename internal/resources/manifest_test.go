@@ -0,0 +1,185 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/evanw/esbuild/pkg/api"
+)
+
+func TestRegistryExportAndLoadManifest(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	script := Asset{
+		Script: ScriptString{Content: `console.log("prebuilt")`, Kind: KindJS},
+		Format: FormatCommon{},
+	}
+	style := Asset{
+		Style:  StyleString{Content: "body { color: red; }"},
+		Minify: true,
+	}
+	dir := t.TempDir()
+	built := NewRegistry(resourceTestSettings{conf: &conf})
+	if err := built.Export(dir, []Asset{script, style}); err != nil {
+		t.Fatal(err)
+	}
+	scriptRes, _ := built.Script(script.Script, script.Format, "", ModeHost)
+	scriptFile := filepath.Join(dir, scriptRes.ID()+".js")
+	if err := os.WriteFile(scriptFile, []byte("/* from manifest */"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clientFile := filepath.Join(dir, built.MainScript().ID()+".js")
+	if err := os.WriteFile(clientFile, []byte("/* client */"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rg := NewRegistry(resourceTestSettings{conf: &conf})
+	if err := rg.LoadManifest(os.DirFS(dir)); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(rg.MainScript().Content()); got != "/* client */" {
+		t.Fatalf("client content = %q", got)
+	}
+	res, err := rg.Script(script.Script, script.Format, "", ModeHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(res.Content()); got != "/* from manifest */" {
+		t.Fatalf("script content = %q", got)
+	}
+	styleRes, err := rg.Style(style.Style, true, ModeHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtStyle, _ := built.Style(style.Style, true, ModeHost)
+	if styleRes.ID() != builtStyle.ID() {
+		t.Fatal("expected style to be read from the manifest")
+	}
+	lazy, err := rg.Script(ScriptString{Content: `console.log("lazy")`, Kind: KindJS}, FormatCommon{}, "", ModeHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lazy.Content()) == 0 {
+		t.Fatal("expected missing entry to be built lazily")
+	}
+
+	if err := rg.LoadManifest(os.DirFS(t.TempDir())); err == nil {
+		t.Fatal("expected missing manifest error")
+	}
+	if err := rg.Build([]Asset{{Script: errScriptEntry{applyErr: os.ErrInvalid}, Format: FormatCommon{}}}); err == nil {
+		t.Fatal("expected build error")
+	}
+}
+
+type profileTestSettings struct {
+	resourceTestSettings
+	opt api.BuildOptions
+}
+
+func (s profileTestSettings) ESProfile(string) api.BuildOptions {
+	return s.opt
+}
+
+func TestClientKeyIncludesProfile(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	dir := t.TempDir()
+	if err := NewRegistry(resourceTestSettings{conf: &conf}).Export(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	same := NewRegistry(resourceTestSettings{conf: &conf})
+	other := NewRegistry(profileTestSettings{
+		resourceTestSettings: resourceTestSettings{conf: &conf},
+		opt:                  api.BuildOptions{MinifyWhitespace: true},
+	})
+	for _, rg := range []Registry{same, other} {
+		if err := rg.LoadManifest(os.DirFS(dir)); err != nil {
+			t.Fatal(err)
+		}
+		if err := rg.BuildClient(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if same.clientKey == other.clientKey {
+		t.Fatal("expected profiles to produce different client keys")
+	}
+	if same.MainScript().ID() == other.MainScript().ID() {
+		t.Fatal("expected the other profile to build its own client")
+	}
+}
+
+func TestScriptKeyIncludesProfile(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	script := Asset{
+		Script: ScriptString{Content: `console.log("prebuilt")`, Kind: KindJS},
+		Format: FormatCommon{},
+	}
+	dir := t.TempDir()
+	built := NewRegistry(resourceTestSettings{conf: &conf})
+	if err := built.Export(dir, []Asset{script}); err != nil {
+		t.Fatal(err)
+	}
+	res, _ := built.Script(script.Script, script.Format, "", ModeHost)
+	if err := os.WriteFile(filepath.Join(dir, res.ID()+".js"), []byte("/* from manifest */"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	same := NewRegistry(resourceTestSettings{conf: &conf})
+	other := NewRegistry(profileTestSettings{
+		resourceTestSettings: resourceTestSettings{conf: &conf},
+		opt:                  api.BuildOptions{MinifyWhitespace: true},
+	})
+	for _, tc := range []struct {
+		rg       Registry
+		prebuilt bool
+	}{{same, true}, {other, false}} {
+		if err := tc.rg.LoadManifest(os.DirFS(dir)); err != nil {
+			t.Fatal(err)
+		}
+		res, err := tc.rg.Script(script.Script, script.Format, "", ModeHost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(res.Content()) == "/* from manifest */"; got != tc.prebuilt {
+			t.Fatalf("expected manifest entry used=%v, content %q", tc.prebuilt, res.Content())
+		}
+	}
+}
+
+func TestBuildClientKeepsError(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	rg := NewRegistry(profileTestSettings{
+		resourceTestSettings: resourceTestSettings{conf: &conf},
+		opt:                  api.BuildOptions{Define: map[string]string{"window": "(("}},
+	})
+	first := rg.BuildClient()
+	if first == nil {
+		t.Fatal("expected client build error")
+	}
+	if err := rg.BuildClient(); err != first {
+		t.Fatalf("expected the same error on every call, got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected MainScript to panic after a failed build")
+		}
+	}()
+	rg.MainScript()
+}
@@ -19,6 +19,7 @@ import (
 	"io/fs"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/evanw/esbuild/pkg/api"
@@ -34,6 +35,10 @@ type StaticEntry interface {
 	entryID(h idWriter)
 }
 
+type modTimeEntry interface {
+	ModTime() time.Time
+}
+
 type StaticFS struct {
 	FS   fs.FS
 	Path string
@@ -44,6 +49,14 @@ func (e StaticFS) Read() ([]byte, error) {
 	return fs.ReadFile(e.FS, e.Path)
 }
 
+func (e StaticFS) ModTime() time.Time {
+	info, err := fs.Stat(e.FS, e.Path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
 func (e StaticFS) entryID(w idWriter) {
 	w.WriteString("fs")
 	w.WriteString(e.Path)
@@ -64,6 +77,14 @@ func (e StaticPath) Read() ([]byte, error) {
 	return os.ReadFile(e.Path)
 }
 
+func (e StaticPath) ModTime() time.Time {
+	info, err := os.Stat(e.Path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
 func (e StaticPath) entryID(w idWriter) {
 	w.WriteString("path")
 	w.WriteString(e.Path)
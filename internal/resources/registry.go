@@ -19,6 +19,7 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/doors-dev/doors/internal"
 	"github.com/doors-dev/doors/internal/common"
@@ -36,21 +37,30 @@ type Registry = *registry
 
 func NewRegistry(app App) Registry {
 	return &registry{
-		app: app,
+		app:   app,
+		cache: newCache(int64(app.Conf().ServerResourceCacheLimit), app.Conf().ServerResourceCacheEntries),
 	}
 }
 
 type registry struct {
 	initGuard  sync.Once
 	app        App
-	cache      sync.Map
-	lookup     sync.Map
+	cache      *cache
+	manifest   *manifest
 	mainScript *Resource
 	mainStyle  *Resource
+	clientKey  [16]byte
+	clientErr  error
 }
 
 func (rs *registry) init() {
+	rs.mainStyle = NewResource(internal.ClientStyles, "text/css", rs.defaultSettings())
 	opt := rs.app.ESProfile("")
+	rs.clientKey = clientKey(&opt)
+	if content, _, ok := rs.prebuilt(rs.clientKey); ok {
+		rs.mainScript = NewResource(content, "application/javascript", rs.defaultSettings())
+		return
+	}
 	ScriptFS{
 		FS:   internal.ClientSrc,
 		Path: "index.ts",
@@ -67,18 +77,26 @@ func (rs *registry) init() {
 	content, err := build(&opt)
 	if err != nil {
 		rs.app.Logger().Error("esbuild error", "error", err)
-		panic(errors.Join(errors.New("client JS build error"), err))
+		rs.clientErr = errors.Join(errors.New("client JS build error"), err)
+		return
 	}
 	rs.mainScript = NewResource(content, "application/javascript", rs.defaultSettings())
-	rs.lookup.Store(rs.mainScript.id, rs.mainScript)
-	rs.mainStyle = NewResource(internal.ClientStyles, "text/css", rs.defaultSettings())
-	rs.lookup.Store(rs.mainStyle.id, rs.mainStyle)
+}
+
+func (rs *registry) prebuilt(key [16]byte) ([]byte, string, bool) {
+	content, contentType, ok, err := rs.manifest.read(key)
+	if err != nil {
+		rs.app.Logger().Error("asset manifest read error", "error", err)
+		return nil, "", false
+	}
+	return content, contentType, ok
 }
 
 func (rs *registry) defaultSettings() resourceSettings {
 	return resourceSettings{
 		cacheControl: rs.app.Conf().ServerCacheControl,
 		disableGzip:  rs.app.Conf().ServerDisableGzip,
+		compression:  rs.app.Conf().ServerCompression,
 	}
 }
 
@@ -87,38 +105,50 @@ func (rs Registry) MainStyle() *Resource {
 	return rs.mainStyle
 }
 
+// MainScript returns the client bundle. It panics with the build error if
+// the bundle failed to build; use [Registry.BuildClient] to get the error.
 func (rs Registry) MainScript() *Resource {
 	rs.initGuard.Do(rs.init)
+	if rs.clientErr != nil {
+		panic(rs.clientErr)
+	}
 	return rs.mainScript
 }
 
 func (rs Registry) Serve(id string, w http.ResponseWriter, r *http.Request) {
-	s, ok := rs.lookup.Load(id)
-	if !ok {
+	for _, main := range []*Resource{rs.MainScript(), rs.MainStyle()} {
+		if main.id == id {
+			main.Serve(w, r)
+			return
+		}
+	}
+	res := rs.cache.lookup(id)
+	if res == nil {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	s.(*Resource).Serve(w, r)
+	res.Serve(w, r)
 }
 
-func (rs *registry) create(key [16]byte, content []byte, lookup bool, contentType string) *Resource {
+// Hold pins a cached resource so it is not evicted, and returns the function
+// that releases the pin. Uncached resources are not affected.
+func (rs Registry) Hold(res *Resource) func() {
+	return rs.cache.hold(res)
+}
+
+// Stats returns a snapshot of the registry cache usage.
+func (rs Registry) Stats() Stats {
+	return rs.cache.stats()
+}
+
+func (rs *registry) create(key [16]byte, content []byte, lookup bool, contentType string, modTime time.Time) *Resource {
 	s := NewResource(content, contentType, rs.defaultSettings())
-	existing, existed := rs.cache.LoadOrStore(key, s)
-	if existed {
-		s = existing.(*Resource)
-	}
-	if lookup {
-		rs.lookup.Store(s.id, s)
-	}
-	return s
+	s.modTime = modTime
+	return rs.cache.add(key, s, lookup)
 }
 
-func (rs *registry) get(key [16]byte) *Resource {
-	entry, ok := rs.cache.Load(key)
-	if !ok {
-		return nil
-	}
-	return entry.(*Resource)
+func (rs *registry) get(key [16]byte, lookup bool) *Resource {
+	return rs.cache.get(key, lookup)
 }
 
 func (rs Registry) Static(entry StaticEntry, contentType string) (*Resource, error) {
@@ -126,15 +156,19 @@ func (rs Registry) Static(entry StaticEntry, contentType string) (*Resource, err
 	h.WriteString("resource")
 	entry.entryID(h)
 	key := h.Sum128().Bytes()
-	res := rs.get(key)
+	res := rs.get(key, true)
 	if res != nil {
 		return res, nil
 	}
+	var modTime time.Time
+	if entry, ok := entry.(modTimeEntry); ok {
+		modTime = entry.ModTime()
+	}
 	content, err := entry.Read()
 	if err != nil {
 		return nil, err
 	}
-	res = rs.create(key, content, true, contentType)
+	res = rs.create(key, content, true, contentType, modTime)
 	return res, nil
 }
 
@@ -142,16 +176,14 @@ func (rs Registry) Script(entry ScriptEntry, format ScriptFormat, profile string
 	var res *Resource
 	var key [16]byte
 	if mode != ModeNoCache {
-		h := xxh3.New()
-		h.WriteString("script")
-		h.WriteString(profile)
-		entry.entryID(h)
-		format.formatID(h)
-		key = h.Sum128().Bytes()
-		res = rs.get(key)
-	}
-	if res != nil {
-		return res, nil
+		key = rs.scriptKey(entry, format, profile)
+		res = rs.get(key, mode == ModeHost)
+		if res != nil {
+			return res, nil
+		}
+		if content, contentType, ok := rs.prebuilt(key); ok {
+			return rs.create(key, content, mode == ModeHost, contentType, time.Time{}), nil
+		}
 	}
 	var content []byte
 	var err error
@@ -173,7 +205,7 @@ func (rs Registry) Script(entry ScriptEntry, format ScriptFormat, profile string
 		return nil, err
 	}
 	if mode != ModeNoCache {
-		res = rs.create(key, content, mode == ModeHost, "application/javascript")
+		res = rs.create(key, content, mode == ModeHost, "application/javascript", time.Time{})
 	} else {
 		res = NewResource(content, "application/javascript", rs.defaultSettings())
 	}
@@ -184,14 +216,14 @@ func (rs Registry) Style(entry StyleEntry, minify bool, mode ResourceMode) (*Res
 	var res *Resource
 	var key [16]byte
 	if mode != ModeNoCache {
-		h := xxh3.New()
-		h.WriteString("style")
-		entry.entryID(h)
-		key = h.Sum128().Bytes()
-		res = rs.get(key)
-	}
-	if res != nil {
-		return res, nil
+		key = styleKey(entry)
+		res = rs.get(key, mode == ModeHost)
+		if res != nil {
+			return res, nil
+		}
+		if content, contentType, ok := rs.prebuilt(key); ok {
+			return rs.create(key, content, mode == ModeHost, contentType, time.Time{}), nil
+		}
 	}
 	var content, err = entry.Read()
 	if err == nil && minify {
@@ -201,13 +233,34 @@ func (rs Registry) Style(entry StyleEntry, minify bool, mode ResourceMode) (*Res
 		return nil, err
 	}
 	if mode != ModeNoCache {
-		res = rs.create(key, content, mode == ModeHost, "text/css")
+		res = rs.create(key, content, mode == ModeHost, "text/css", time.Time{})
 	} else {
 		res = NewResource(content, "text/css", rs.defaultSettings())
 	}
 	return res, nil
 }
 
+// scriptKey identifies a script by its entry, format, and the esbuild
+// options profile resolves to, so a manifest built with other options is
+// not used.
+func (rs Registry) scriptKey(entry ScriptEntry, format ScriptFormat, profile string) [16]byte {
+	h := xxh3.New()
+	h.WriteString("script")
+	h.WriteString(profile)
+	opt := rs.app.ESProfile(profile)
+	profileID(h, &opt)
+	entry.entryID(h)
+	format.formatID(h)
+	return h.Sum128().Bytes()
+}
+
+func styleKey(entry StyleEntry) [16]byte {
+	h := xxh3.New()
+	h.WriteString("style")
+	entry.entryID(h)
+	return h.Sum128().Bytes()
+}
+
 type ResourceMode int
 
 const (
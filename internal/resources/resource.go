@@ -15,9 +15,10 @@
 package resources
 
 import (
+	"bytes"
 	"net/http"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/zeebo/xxh3"
@@ -26,6 +27,17 @@ import (
 type resourceSettings struct {
 	cacheControl string
 	disableGzip  bool
+	compression  []common.Compression
+}
+
+func (s resourceSettings) encodings() []common.Compression {
+	if s.disableGzip {
+		return nil
+	}
+	if s.compression == nil {
+		return common.DefaultCompression()
+	}
+	return s.compression
 }
 
 func NewResource(content []byte, contentType string, s resourceSettings) *Resource {
@@ -41,10 +53,31 @@ func NewResource(content []byte, contentType string, s resourceSettings) *Resour
 type Resource struct {
 	id          string
 	settings    resourceSettings
-	once        sync.Once
+	encoded     sync.Map
 	content     []byte
-	gzipped     []byte
 	contentType string
+	modTime     time.Time
+	cached      *cacheEntry
+}
+
+type encodedContent struct {
+	once    sync.Once
+	content []byte
+}
+
+func (s *Resource) encode(c common.Compression) []byte {
+	v, ok := s.encoded.Load(c.Encoding)
+	if !ok {
+		v, _ = s.encoded.LoadOrStore(c.Encoding, &encodedContent{})
+	}
+	e := v.(*encodedContent)
+	e.once.Do(func() {
+		e.content = common.Compress(s.content, c)
+		if s.cached != nil {
+			s.cached.cache.grow(s.cached, int64(len(e.content)))
+		}
+	})
+	return e.content
 }
 
 func (s *Resource) ID() string {
@@ -64,16 +97,22 @@ func (s *Resource) ServeCache(w http.ResponseWriter, r *http.Request, cache bool
 	} else {
 		w.Header().Set("Cache-Control", "no-cache")
 	}
-	if !s.settings.disableGzip && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
-		s.once.Do(func() {
-			s.gzipped = common.Zip(s.content)
-		})
-		w.Header().Set("Content-Encoding", "gzip")
+	content := s.content
+	etag := s.id
+	encodings := s.settings.encodings()
+	if len(encodings) != 0 {
 		w.Header().Set("Vary", "Accept-Encoding")
-		w.Write(s.gzipped)
-		return
 	}
-	w.Write(s.content)
+	// ranges are served from the identity representation so media can seek
+	if r.Header.Get("Range") == "" {
+		if c, ok := common.NegotiateCompression(r.Header.Get("Accept-Encoding"), encodings); ok {
+			w.Header().Set("Content-Encoding", string(c.Encoding))
+			content = s.encode(c)
+			etag += "-" + string(c.Encoding)
+		}
+	}
+	w.Header().Set("ETag", `"`+etag+`"`)
+	http.ServeContent(w, r, "", s.modTime, bytes.NewReader(content))
 }
 func (s *Resource) Serve(w http.ResponseWriter, r *http.Request) {
 	s.ServeCache(w, r, true)
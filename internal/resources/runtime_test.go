@@ -23,8 +23,11 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/evanw/esbuild/pkg/api"
@@ -132,6 +135,73 @@ func TestResourceContentAndServeCache(t *testing.T) {
 	}
 }
 
+func TestResourceConditionalAndRange(t *testing.T) {
+	res := NewResource([]byte("0123456789"), "text/plain", resourceSettings{
+		cacheControl: "public, max-age=60",
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	res.Serve(rec, req)
+	etag := rec.Header().Get("ETag")
+	if etag != `"`+res.ID()+`"` {
+		t.Fatalf("ETag = %q", etag)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	res.Serve(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("conditional status = %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	res.Serve(rec, req)
+	if got := rec.Header().Get("ETag"); got != `"`+res.ID()+`-gzip"` {
+		t.Fatalf("encoded ETag = %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=2-4")
+	rec = httptest.NewRecorder()
+	res.Serve(rec, req)
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("range status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("unexpected encoding for range = %q", got)
+	}
+	if rec.Body.String() != "234" {
+		t.Fatalf("range body = %q", rec.Body.String())
+	}
+}
+
+func TestRegistryStaticPathLastModified(t *testing.T) {
+	conf := common.Conf{}
+	common.InitDefaults(&conf)
+	rg := NewRegistry(resourceTestSettings{conf: &conf})
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path, []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	res, err := rg.Static(StaticPath{Path: path}, "text/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	res.Serve(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Last-Modified"); got != modTime.Format(http.TimeFormat) {
+		t.Fatalf("Last-Modified = %q", got)
+	}
+}
+
 func TestBuildErrorsAndBuild(t *testing.T) {
 	errs := BuildErrors{
 		{Text: "plain error"},
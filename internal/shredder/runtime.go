@@ -20,6 +20,7 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+	"sync/atomic"
 
 	"github.com/gammazero/deque"
 )
@@ -54,6 +55,30 @@ type runtime struct {
 	pool        []chan task
 	cold        chan task
 	hot         chan int
+	workers     atomic.Int32
+	busy        atomic.Int32
+	queued      atomic.Int32
+}
+
+// Stats is a snapshot of runtime worker usage.
+type Stats struct {
+	// Limit is the maximum number of workers.
+	Limit int
+	// Workers is the number of started workers.
+	Workers int
+	// Busy is the number of workers running a task.
+	Busy int
+	// Queued is the number of tasks waiting for a free worker.
+	Queued int
+}
+
+func (r Runtime) Stats() Stats {
+	return Stats{
+		Limit:   r.workerLimit,
+		Workers: int(r.workers.Load()),
+		Busy:    int(r.busy.Load()),
+		Queued:  int(r.queued.Load()),
+	}
 }
 
 func (r Runtime) Context() context.Context {
@@ -226,15 +251,19 @@ func (r *runtime) loop() {
 				}
 				if workerCount == r.workerLimit {
 					queue.PushBack(f)
+					r.queued.Add(1)
 					continue
 				}
 				ch := make(chan task)
 				id := workerCount
 				workerCount += 1
+				r.workers.Store(int32(workerCount))
 				r.pool[id] = ch
 				go func() {
 					for t := range ch {
+						r.busy.Add(1)
 						t.run(r)
+						r.busy.Add(-1)
 						r.hot <- id
 					}
 				}()
@@ -244,11 +273,13 @@ func (r *runtime) loop() {
 		}
 		if done {
 			num := <-r.hot
+			r.queued.Add(-1)
 			r.pool[num] <- queue.PopFront()
 			continue
 		}
 		num, ok := r.getHotNum()
 		if ok {
+			r.queued.Add(-1)
 			r.pool[num] <- queue.PopFront()
 			continue
 		}
@@ -258,7 +289,9 @@ func (r *runtime) loop() {
 			done = true
 		case f := <-r.cold:
 			queue.PushBack(f)
+			r.queued.Add(1)
 		case num := <-r.hot:
+			r.queued.Add(-1)
 			r.pool[num] <- queue.PopFront()
 		}
 	}
@@ -260,7 +260,7 @@ func (d *deck) checkQueueLength() error {
 	if d.inner.Len()+len(d.issued) < d.conf.Queue {
 		return nil
 	}
-	return errors.New("call queue limit reached")
+	return errQueueLimit
 }
 
 func (d *deck) restore(seq uint64, c *inner.Call) error {
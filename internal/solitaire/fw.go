@@ -2,8 +2,10 @@ package solitaire
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -30,6 +32,8 @@ type writeController struct {
 	Flusher    http.Flusher
 	Writer     io.Writer
 	Conf       *common.SolitaireConf
+	Inst       Instance
+	Ctx        context.Context
 	frameStart time.Time
 	buffer     bytes.Buffer
 	stashed    []header
@@ -78,6 +82,7 @@ func (f *writeController) Submit(signal signal) ([]header, error) {
 	if signal == signalSync && f.buffer.Len() == 0 {
 		return nil, nil
 	}
+	_, span := f.Inst.StartSpan(f.Ctx, common.SpanSyncFlush, slog.Int(common.TraceCalls, len(f.stashed)))
 	if signal == signalRoll {
 		if err := f.buffer.WriteByte(byte(signalRoll)); err != nil {
 			panic(err)
@@ -95,7 +100,10 @@ func (f *writeController) Submit(signal signal) ([]header, error) {
 	if err := f.buffer.WriteByte(terminator); err != nil {
 		panic(err)
 	}
+	size := f.buffer.Len()
+	span.SetAttrs(slog.Int(common.TracePayloadBytes, size))
 	if _, err := f.buffer.WriteTo(f.Writer); err != nil {
+		span.End(err)
 		h := f.stashed
 		f.stashed = nil
 		f.buffer.Reset()
@@ -105,6 +113,8 @@ func (f *writeController) Submit(signal signal) ([]header, error) {
 	if signal == signalSync {
 		f.Flusher.Flush()
 	}
+	span.End(nil)
+	f.Inst.Metrics().Flush(size)
 	return nil, nil
 }
 
@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"testing"
 	"time"
@@ -103,6 +104,7 @@ func (s *stubExpireHandler) Expire() {
 type stubInstance struct {
 	syncErrors []error
 	touches    int
+	metrics    common.Metrics
 }
 
 func (s *stubInstance) SyncError(err error) {
@@ -113,6 +115,14 @@ func (s *stubInstance) Touch() {
 	s.touches += 1
 }
 
+func (s *stubInstance) Metrics() *common.Metrics {
+	return &s.metrics
+}
+
+func (s *stubInstance) StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, common.Span) {
+	return common.NoopTracer{}.Start(ctx, name, attrs...)
+}
+
 func testSolitaireConf() *common.SolitaireConf {
 	conf := &common.Conf{
 		SolitaireQueue:     8,
@@ -131,6 +141,8 @@ func newTestWriteController(conf *common.SolitaireConf, w *stubRW) *writeControl
 		Writer:  w,
 		Flusher: w,
 		Conf:    conf,
+		Inst:    &stubInstance{},
+		Ctx:     context.Background(),
 	}
 }
 
@@ -0,0 +1,224 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solitaire
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// socket is a minimal server side WebSocket connection (RFC 6455). It
+// carries the sync stream in binary messages and receives reports as
+// whole messages. Extensions and subprotocols are not supported.
+type socket struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	mu   sync.Mutex
+	head [14]byte
+}
+
+const socketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	opContinuation byte = 0x0
+	opText         byte = 0x1
+	opBinary       byte = 0x2
+	opClose        byte = 0x8
+	opPing         byte = 0x9
+	opPong         byte = 0xA
+)
+
+var (
+	errSocketClosed   = errors.New("socket closed")
+	errSocketProtocol = errors.New("socket protocol error")
+	errSocketTooBig   = errors.New("socket message too big")
+)
+
+// acceptSocket completes the opening handshake. On failure it writes the
+// error response and returns nil. Headers already set on w, such as session
+// cookies, are sent with the handshake response. The Origin header is
+// checked by the app before the sync request reaches the instance: against
+// its RequestPolicy, or same-origin only when none is set.
+func acceptSocket(w http.ResponseWriter, r *http.Request) *socket {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		w.WriteHeader(http.StatusBadRequest)
+		return nil
+	}
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		return nil
+	}
+	sum := sha1.Sum([]byte(key + socketGUID))
+	header := w.Header().Clone()
+	header.Del("Content-Type")
+	header.Set("Upgrade", "websocket")
+	header.Set("Connection", "Upgrade")
+	header.Set("Sec-WebSocket-Accept", base64.StdEncoding.EncodeToString(sum[:]))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	header.Write(rw)
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil
+	}
+	return &socket{
+		conn: conn,
+		rw:   rw,
+	}
+}
+
+// Write sends p as one binary message.
+func (s *socket) Write(p []byte) (int, error) {
+	if err := s.write(opBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush is a no-op, every write is sent right away.
+func (s *socket) Flush() {}
+
+func (s *socket) write(op byte, p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	head := s.head[:2]
+	head[0] = 0x80 | op
+	switch {
+	case len(p) < 126:
+		head[1] = byte(len(p))
+	case len(p) <= 0xFFFF:
+		head[1] = 126
+		head = binary.BigEndian.AppendUint16(head, uint16(len(p)))
+	default:
+		head[1] = 127
+		head = binary.BigEndian.AppendUint64(head, uint64(len(p)))
+	}
+	if _, err := s.rw.Write(head); err != nil {
+		return err
+	}
+	if _, err := s.rw.Write(p); err != nil {
+		return err
+	}
+	return s.rw.Flush()
+}
+
+// Read returns the next data message. Control frames are handled on the
+// way. It returns errSocketClosed once the client closed the connection and
+// errSocketTooBig if the message exceeds limit bytes.
+func (s *socket) Read(limit int) ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, op, payload, err := s.readFrame(limit - len(message))
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := s.write(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := payload
+			if len(code) > 2 {
+				code = code[:2]
+			}
+			s.write(opClose, code)
+			return nil, errSocketClosed
+		case opText, opBinary:
+			if started {
+				return nil, errSocketProtocol
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, errSocketProtocol
+			}
+		default:
+			return nil, errSocketProtocol
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (s *socket) readFrame(limit int) (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(s.rw, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	op := head[0] & 0x0F
+	if head[0]&0x70 != 0 || head[1]&0x80 == 0 {
+		return false, 0, nil, errSocketProtocol
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(s.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(s.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	control := op&0x8 != 0
+	if control && (length > 125 || !fin) {
+		return false, 0, nil, errSocketProtocol
+	}
+	if !control && length > uint64(max(limit, 0)) {
+		return false, 0, nil, errSocketTooBig
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(s.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(s.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// Close sends a normal closure and closes the connection.
+func (s *socket) Close() error {
+	s.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	s.write(opClose, []byte{0x03, 0xE8})
+	return s.conn.Close()
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solitaire
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doors-dev/doors/internal/common"
+	"github.com/doors-dev/doors/internal/front/action"
+)
+
+type testSocketClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialTestSocket(t *testing.T, addr string, origin string) (*testSocketClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	req := "GET /s/inst HTTP/1.1\r\n" +
+		"Host: " + addr + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Origin: " + origin + "\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		sum := sha1.Sum([]byte(key + socketGUID))
+		if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+			t.Fatalf("unexpected accept key %q", resp.Header.Get("Sec-WebSocket-Accept"))
+		}
+	}
+	return &testSocketClient{conn: conn, r: r}, resp
+}
+
+func (c *testSocketClient) send(t *testing.T, op byte, payload []byte) {
+	t.Helper()
+	frame := []byte{0x80 | op}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, 0x80|byte(len(payload)))
+	default:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *testSocketClient) read(t *testing.T) (byte, []byte) {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var head [2]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	if head[1]&0x80 != 0 {
+		t.Fatal("server frames must not be masked")
+	}
+	length := int(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(c.r, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(c.r, ext[:])
+		length = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+func newSocketTestServer(t *testing.T, conf *common.SolitaireConf) (Solitaire, *httptest.Server) {
+	s := NewSolitaire(&stubInstance{}, conf)
+	srv := httptest.NewServer(http.HandlerFunc(s.Connect))
+	t.Cleanup(func() {
+		s.End(common.EndCauseKilled)
+		srv.Close()
+	})
+	return s, srv
+}
+
+func TestSocketCarriesCallsAndReports(t *testing.T) {
+	conf := testSolitaireConf()
+	conf.WebSocket = true
+	s, srv := newSocketTestServer(t, conf)
+	addr := strings.TrimPrefix(srv.URL, "http://")
+	s.Call(&stubSyncCall{act: action.Test{Arg: "socket"}})
+
+	client, resp := dialTestSocket(t, addr, srv.URL)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected switching protocols, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Cache-Control") != "no-store" {
+		t.Fatalf("expected handshake to keep response headers, got %v", resp.Header)
+	}
+	var stream []byte
+	for !bytes.Contains(stream, []byte(`"socket"`)) {
+		op, payload := client.read(t)
+		if op != opBinary {
+			t.Fatalf("expected binary message, got opcode %d", op)
+		}
+		stream = append(stream, payload...)
+	}
+	if stream[0] != signalAction {
+		t.Fatalf("expected stream to start with an action, got %v", stream[:1])
+	}
+	if s.deck.PendingCount() != 1 {
+		t.Fatalf("expected sent call to be pending, got %d", s.deck.PendingCount())
+	}
+
+	client.send(t, opPing, []byte("hi"))
+	if op, payload := client.read(t); op != opPong || string(payload) != "hi" {
+		t.Fatalf("expected pong, got opcode %d %q", op, payload)
+	}
+
+	client.send(t, opBinary, []byte(`[1,0,{"1":[{"ok":true},null]},[]]`))
+	deadline := time.Now().Add(5 * time.Second)
+	for s.deck.PendingCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected socket report to resolve the pending call")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	client.send(t, opClose, []byte{0x03, 0xE8})
+	for {
+		op, _ := client.read(t)
+		if op == opClose {
+			break
+		}
+	}
+}
+
+func TestSocketRejected(t *testing.T) {
+	conf := testSolitaireConf()
+	_, srv := newSocketTestServer(t, conf)
+	addr := strings.TrimPrefix(srv.URL, "http://")
+	if _, resp := dialTestSocket(t, addr, srv.URL); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected disabled socket transport to be rejected, got %d", resp.StatusCode)
+	}
+
+	conf = testSolitaireConf()
+	conf.WebSocket = true
+	_, srv = newSocketTestServer(t, conf)
+	addr = strings.TrimPrefix(srv.URL, "http://")
+	if _, resp := dialTestSocket(t, addr, "ws://"+addr); resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected enabled socket transport to accept the handshake, got %d", resp.StatusCode)
+	}
+}
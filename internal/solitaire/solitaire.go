@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -35,6 +36,38 @@ type Solitaire = *solitaire
 type Instance interface {
 	SyncError(error)
 	Touch()
+	StartSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, common.Span)
+	Metrics() *common.Metrics
+}
+
+var (
+	errSyncTimeout = errors.New("sync timeout")
+	errQueueLimit  = errors.New("call queue limit reached")
+	errReportSize  = errors.New("solitaire report is too long")
+)
+
+// Reason classifies an error passed to [Instance.SyncError].
+func Reason(err error) common.SyncErrorReason {
+	switch {
+	case errors.Is(err, errSyncTimeout):
+		return common.SyncErrorTimeout
+	case errors.Is(err, errQueueLimit):
+		return common.SyncErrorQueueLimit
+	case errors.Is(err, errReportSize):
+		return common.SyncErrorReportSize
+	default:
+		return common.SyncErrorReport
+	}
+}
+
+// Stats is a snapshot of the synchronization state of an instance.
+type Stats struct {
+	// Queue is the number of calls waiting to be sent.
+	Queue int
+	// Pending is the number of sent calls waiting for a report.
+	Pending int
+	// RTT is the current round trip time estimate.
+	RTT time.Duration
 }
 
 func NewSolitaire(inst Instance, conf *common.SolitaireConf) Solitaire {
@@ -70,7 +103,15 @@ func (s Solitaire) Call(call action.Call) {
 }
 
 func (d *solitaire) Expire() {
-	d.inst.SyncError(errors.New("sync timeout"))
+	d.inst.SyncError(errSyncTimeout)
+}
+
+func (s Solitaire) Stats() Stats {
+	return Stats{
+		Queue:   s.deck.QueueLength(),
+		Pending: s.deck.PendingCount(),
+		RTT:     s.sync.RTT(),
+	}
 }
 
 func (s Solitaire) End(cause common.EndCause) {
@@ -90,6 +131,15 @@ func (s Solitaire) Connect(w http.ResponseWriter, r *http.Request) {
 		cancelTimer()
 	}
 	w.Header().Set("Cache-Control", "no-store")
+	if common.IsWebSocket(r) {
+		defer cancel(nil)
+		if !s.conf.WebSocket {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.connectSocket(w, r, ctx, cancel)
+		return
+	}
 	if r.Method == http.MethodGet {
 		s.inst.Touch()
 		fw := &writeController{
@@ -97,6 +147,8 @@ func (s Solitaire) Connect(w http.ResponseWriter, r *http.Request) {
 			Writer:  w,
 			Flusher: w.(http.Flusher),
 			Conf:    s.conf,
+			Inst:    s.inst,
+			Ctx:     r.Context(),
 		}
 		sender := newSender(s.inst, s.deck, fw, ctx, cancel, s.conf)
 		prev := s.sender.Swap(sender)
@@ -118,6 +170,49 @@ func (s Solitaire) Connect(w http.ResponseWriter, r *http.Request) {
 	receiver.Run()
 }
 
+// connectSocket serves one roll of the sync stream over a WebSocket. Reports
+// arrive on the same connection as whole messages.
+func (s Solitaire) connectSocket(w http.ResponseWriter, r *http.Request, ctx context.Context, cancel func(error)) {
+	ws := acceptSocket(w, r)
+	if ws == nil {
+		return
+	}
+	defer ws.Close()
+	s.inst.Touch()
+	fw := &writeController{
+		Sync:    &s.sync,
+		Writer:  ws,
+		Flusher: ws,
+		Conf:    s.conf,
+		Inst:    s.inst,
+		Ctx:     r.Context(),
+	}
+	sender := newSender(s.inst, s.deck, fw, ctx, cancel, s.conf)
+	prev := s.sender.Swap(sender)
+	if prev != nil {
+		<-prev.Roll()
+	}
+	go func() {
+		defer cancel(nil)
+		for {
+			message, err := ws.Read(s.conf.ReportSize)
+			if errors.Is(err, errSocketTooBig) {
+				s.inst.SyncError(errReportSize)
+				return
+			}
+			if err != nil {
+				return
+			}
+			rep := report{}
+			if err := json.Unmarshal(message, &rep); err != nil {
+				return
+			}
+			sender.onReport(&s.sync, rep)
+		}
+	}()
+	sender.Run()
+}
+
 func newReceiver(inst Instance, d *deck, s *frameSyncer, r *http.Request, w http.ResponseWriter, ctx context.Context, cancel func(error), conf *common.SolitaireConf) *receiver {
 	rv := &receiver{
 		conn: conn{
@@ -162,7 +257,7 @@ func (rv *receiver) Run() {
 			rv.w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		rv.onReport(rep)
+		rv.onReport(rv.sync, rep)
 	case <-time.After(rv.conf.ReportTimeout):
 		rv.w.WriteHeader(http.StatusRequestTimeout)
 		return
@@ -183,7 +278,7 @@ func (rv *receiver) readStream() {
 		}
 		length := binary.BigEndian.Uint32(lengthBuffer[:])
 		if length > uint32(rv.conf.ReportSize) {
-			rv.inst.SyncError(errors.New("solitaire report is too long"))
+			rv.inst.SyncError(errReportSize)
 			rv.w.WriteHeader(http.StatusRequestEntityTooLarge)
 			return
 		}
@@ -206,7 +301,7 @@ func (rv *receiver) readStream() {
 			rv.w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		rv.onReport(rep)
+		rv.onReport(rv.sync, rep)
 	}
 }
 
@@ -252,22 +347,23 @@ func (rv *receiver) reader() {
 	}
 }
 
-func (rv *receiver) onReport(rep report) {
-	rv.sync.Report(rep.ID, rep.TS)
-	err := rv.deck.CollectResults(rep.Results)
+func (c *conn) onReport(sync *frameSyncer, rep report) {
+	sync.Report(rep.ID, rep.TS)
+	c.inst.Metrics().RTT(sync.RTT())
+	err := c.deck.CollectResults(rep.Results)
 	if err != nil {
 		if err == context.Canceled {
 			return
 		}
-		rv.inst.SyncError(err)
+		c.inst.SyncError(err)
 		return
 	}
-	err = rv.deck.FillGaps(rep.Gaps)
+	err = c.deck.FillGaps(rep.Gaps)
 	if err != nil {
 		if err == context.Canceled {
 			return
 		}
-		rv.inst.SyncError(err)
+		c.inst.SyncError(err)
 		return
 	}
 }
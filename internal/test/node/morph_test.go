@@ -0,0 +1,149 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package door
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/doors-dev/doors"
+	"github.com/doors-dev/doors/internal/test"
+	"github.com/doors-dev/gox"
+	"github.com/go-rod/rod"
+)
+
+type FragmentMorph struct {
+	door doors.Door
+	n    int
+	test.NoBeam
+}
+
+func (f *FragmentMorph) input(id string, n int, preserve bool) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if err := cur.InitVoid("input"); err != nil {
+			return err
+		}
+		if err := cur.Set("id", id); err != nil {
+			return err
+		}
+		if err := cur.Set("type", "text"); err != nil {
+			return err
+		}
+		if err := cur.Set("data-render", fmt.Sprint(n)); err != nil {
+			return err
+		}
+		if preserve {
+			if err := cur.Modify(doors.A(cur.Context(), doors.APreserve{})); err != nil {
+				return err
+			}
+		}
+		return cur.Submit()
+	}
+}
+
+func (f *FragmentMorph) content(n int) gox.Elem {
+	return func(cur gox.Cursor) error {
+		if err := cur.Init("p"); err != nil {
+			return err
+		}
+		if err := cur.Set("id", "morph-label"); err != nil {
+			return err
+		}
+		if err := cur.Submit(); err != nil {
+			return err
+		}
+		if err := cur.Text(fmt.Sprintf("render %d", n)); err != nil {
+			return err
+		}
+		if err := cur.Close(); err != nil {
+			return err
+		}
+		if err := cur.Any(f.input("morph-plain", n, false)); err != nil {
+			return err
+		}
+		return cur.Any(f.input("morph-kept", n, true))
+	}
+}
+
+func (f *FragmentMorph) Main() gox.Elem {
+	return func(cur gox.Cursor) error {
+		f.door.Inner(cur.Context(), f.content(0))
+		if err := cur.Any(&f.door); err != nil {
+			return err
+		}
+		return cur.Any(test.Button("morph-update", func(ctx context.Context) bool {
+			f.n += 1
+			f.door.Inner(ctx, f.content(f.n))
+			return false
+		}))
+	}
+}
+
+// morphUpdate clicks the update button from script, so focus stays where it is.
+func morphUpdate(page *rod.Page) {
+	page.MustEval(`() => document.getElementById("morph-update").click()`)
+	<-time.After(200 * time.Millisecond)
+}
+
+// morphState reports the value, caret, focus, and identity of the input with id.
+func morphState(page *rod.Page, id string) string {
+	return page.MustEval(`(id) => {
+		const el = document.getElementById(id)
+		return [el.value, el.selectionStart, document.activeElement === el, el.morphMark === true].join()
+	}`, id).String()
+}
+
+func TestDoorMorph(t *testing.T) {
+	bro := test.NewFragmentBro(browser, func() test.Fragment {
+		return &FragmentMorph{
+			door: doors.Door{Morph: true},
+		}
+	})
+	page := bro.Page(t, "/")
+	defer bro.Close()
+	defer page.Close()
+
+	test.TestInput(t, page, "#morph-plain", "hello")
+	page.MustElement("#morph-plain").MustEval(`function() {
+		this.morphMark = true
+		this.setSelectionRange(2, 2)
+	}`)
+	morphUpdate(page)
+	test.TestContent(t, page, "#morph-label", "render 1")
+	test.TestAttr(t, page, "#morph-plain", "data-render", "1")
+	test.TestAttr(t, page, "#morph-kept", "data-render", "1")
+	if state := morphState(page, "morph-plain"); state != "hello,2,true,true" {
+		t.Fatal("morph: expected value, caret, focus, and element to survive, fact: ", state)
+	}
+
+	test.TestInput(t, page, "#morph-kept", "kept")
+	morphUpdate(page)
+	test.TestContent(t, page, "#morph-label", "render 2")
+	test.TestAttr(t, page, "#morph-plain", "data-render", "2")
+	test.TestAttr(t, page, "#morph-kept", "data-render", "1")
+	if state := morphState(page, "morph-kept"); state != "kept,4,true,false" {
+		t.Fatal("preserve: expected focused input to keep its state, fact: ", state)
+	}
+
+	page.MustEval(`() => document.activeElement.blur()`)
+	morphUpdate(page)
+	test.TestContent(t, page, "#morph-label", "render 3")
+	test.TestAttr(t, page, "#morph-kept", "data-render", "3")
+	if state := morphState(page, "morph-kept"); state != "kept,4,false,false" {
+		t.Fatal("preserve: expected blurred input to keep its typed value, fact: ", state)
+	}
+}
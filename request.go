@@ -59,6 +59,9 @@ type RequestForm[D any] interface {
 	RequestAfter
 	// Data returns the parsed form payload.
 	Data() D
+	// Errors returns the field errors of a validated submission, or nil
+	// when it is valid or [ASubmit.Validate] is off.
+	Errors() FormErrors
 }
 
 // RequestRawForm is the request context passed to raw multipart form handlers.
@@ -75,6 +78,11 @@ type RequestRawForm interface {
 	// Reader returns a multipart reader for streaming form parts. The body
 	// is bounded by the most recently set request body limit.
 	Reader() (*multipart.Reader, error)
+	// Stream returns a [MultipartStream] over the body, with reads of each
+	// part bounded by partLimit bytes. Negative values disable the part
+	// limit. The body is bounded by the request body limit; raise it with
+	// SetRequestBodyLimit for large uploads.
+	Stream(partLimit int) (*MultipartStream, error)
 	// ParseForm parses the form data with a memory limit.
 	// The body is bounded by the request body limit.
 	ParseForm(maxMemory int) (ParsedForm, error)
@@ -107,6 +115,15 @@ type RequestRawHook interface {
 	Body() io.ReadCloser
 }
 
+// RequestDrop is the request context passed to [ADrop] handlers.
+type RequestDrop interface {
+	RequestEvent[DragEvent]
+	// Files returns a [MultipartStream] over the dropped files, one "file"
+	// part each in the order of [DragEvent].Files, with reads of each part
+	// bounded by partLimit bytes. Negative values disable the part limit.
+	Files(partLimit int) *MultipartStream
+}
+
 // Request is the request context passed to main app handler.
 //
 // Use it for cookies, request headers, and response headers.
@@ -175,6 +192,17 @@ func (r *request) Reader() (*multipart.Reader, error) {
 	return r.r.MultipartReader()
 }
 
+func (r *request) Stream(partLimit int) (*MultipartStream, error) {
+	reader, err := r.Reader()
+	if err != nil {
+		return nil, err
+	}
+	return &MultipartStream{
+		reader: reader,
+		limit:  int64(partLimit),
+	}, nil
+}
+
 func (r *request) FormValues() url.Values {
 	return r.r.Form
 }
@@ -218,9 +246,14 @@ func (e *eventRequest[E]) Event() E {
 
 type formHookRequest[D any] struct {
 	request
-	data *D
+	data   *D
+	errors FormErrors
 }
 
 func (d *formHookRequest[D]) Data() D {
 	return *d.data
 }
+
+func (d *formHookRequest[D]) Errors() FormErrors {
+	return d.errors
+}
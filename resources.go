@@ -22,6 +22,7 @@ import (
 	"github.com/doors-dev/doors/internal/common"
 	"github.com/doors-dev/doors/internal/core"
 	"github.com/doors-dev/doors/internal/printer"
+	"github.com/doors-dev/doors/internal/resources"
 )
 
 // Resource describes content or a URL that can be attached to HTML resource
@@ -93,6 +94,11 @@ func ResourceProxy(url string) Resource {
 	return printer.SourceProxy(url)
 }
 
+// ResourceStats reports resource registry cache usage: cached entries and
+// content bytes, entries pinned by live doors, and hit, miss, and eviction
+// counters.
+type ResourceStats = resources.Stats
+
 // NewHook registers a resource handler as a hook and returns a URL path
 // that triggers it.
 //
@@ -26,7 +26,9 @@ import (
 // Location is a parsed or generated URL path plus query string.
 type Location = path.Location
 
-// NewLocation encodes model into a [Location].
+// NewLocation encodes model into a [Location]. The result is relative to
+// the app and doesn't include the base path set with [WithBasePath]; use
+// [Href] for a URL within the app.
 //
 // model may be a [Location], a path-model struct, a pointer to a path-model
 // struct, or a value implementing [LocationEncoder].
@@ -34,6 +36,18 @@ func NewLocation(model any) (Location, error) {
 	return path.Encode(model)
 }
 
+// Href encodes model like [NewLocation] and returns its URL within the app,
+// including the base path set with [WithBasePath]. Use it for links and
+// redirects that are not rendered with [ALink].
+func Href(ctx context.Context, model any) (string, error) {
+	loc, err := path.Encode(model)
+	if err != nil {
+		return "", err
+	}
+	core := ctx.Value(common.KeyCore).(core.Core)
+	return core.App().PathMaker().URL(loc), nil
+}
+
 // LocationEncoder is implemented by custom navigation models that can encode
 // themselves as a [Location].
 type LocationEncoder = path.Encoder
@@ -22,8 +22,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/doors-dev/gox"
 )
@@ -132,3 +134,483 @@ func TestAppStaticMiddlewareServing(t *testing.T) {
 		t.Fatalf("unexpected file cache-control: %q", headers.Get("Cache-Control"))
 	}
 }
+
+func TestUseMetrics(t *testing.T) {
+	app := NewApp(func(context.Context, Request) gox.Comp {
+		return testPage("page")
+	})
+	app.Use(UseMetrics("metrics"))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	if status, _, _ := readURL(t, server, "/"); status != http.StatusOK {
+		t.Fatalf("unexpected page status %d", status)
+	}
+	for range 2 {
+		status, headers, body := readURL(t, server, "/metrics")
+		if status != http.StatusOK {
+			t.Fatalf("unexpected metrics status %d", status)
+		}
+		if !strings.HasPrefix(headers.Get("Content-Type"), "text/plain; version=0.0.4") {
+			t.Fatalf("unexpected metrics content-type: %q", headers.Get("Content-Type"))
+		}
+		if len(headers.Values("Set-Cookie")) != 0 {
+			t.Fatalf("metrics response sets cookies: %v", headers.Values("Set-Cookie"))
+		}
+		for _, line := range []string{
+			"doors_sessions 1\n",
+			"doors_instances{state=\"active\"} 1\n",
+			"doors_sync_errors_total{reason=\"timeout\"} 0\n",
+			"# TYPE doors_hook_duration_seconds histogram\n",
+			"doors_hook_duration_seconds_bucket{le=\"+Inf\"} 0\n",
+		} {
+			if !strings.Contains(body, line) {
+				t.Fatalf("metrics miss %q:\n%s", line, body)
+			}
+		}
+	}
+	if app.SessionCount() != 1 {
+		t.Fatalf("unexpected session count %d", app.SessionCount())
+	}
+}
+
+func TestEndSessions(t *testing.T) {
+	var pages atomic.Int32
+	app := NewApp(func(ctx context.Context, _ Request) gox.Comp {
+		user := "alice"
+		if pages.Add(1) == 3 {
+			user = "bob"
+		}
+		SessionTag(ctx, "user", user)
+		return testPage(user)
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	for range 3 {
+		if status, _, _ := readURL(t, server, "/docs"); status != http.StatusOK {
+			t.Fatalf("unexpected page status %d", status)
+		}
+	}
+	sessions := app.Sessions()
+	if len(sessions) != 3 {
+		t.Fatalf("unexpected sessions %+v", sessions)
+	}
+	for _, sess := range sessions {
+		if len(sess.Instances) != 1 || sess.Instances[0].Location.String() != "/docs" {
+			t.Fatalf("unexpected session instances %+v", sess.Instances)
+		}
+		if sess.Tags["user"] == "" || sess.LastSeen.IsZero() {
+			t.Fatalf("unexpected session info %+v", sess)
+		}
+	}
+
+	if n := app.EndSessions(Tag{Key: "user", Value: "alice"}); n != 2 {
+		t.Fatalf("expected two ended sessions, got %d", n)
+	}
+	if app.SessionCount() != 1 || app.InstanceCount() != 1 {
+		t.Fatalf("unexpected counts after ending sessions: %d sessions, %d instances", app.SessionCount(), app.InstanceCount())
+	}
+	if sessions := app.Sessions(); sessions[0].Tags["user"] != "bob" {
+		t.Fatalf("unexpected remaining session %+v", sessions[0])
+	}
+}
+
+func TestRequestPolicy(t *testing.T) {
+	app := NewApp(func(context.Context, Request) gox.Comp {
+		return testPage("page")
+	}, WithRequestPolicy(RequestPolicy{
+		Origins:      []string{"https://admin.example.com"},
+		RequireToken: true,
+	}))
+	app.Use(UseMetrics("metrics"))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	attr := func(name string) string {
+		_, rest, ok := strings.Cut(string(body), name+`="`)
+		if !ok {
+			t.Fatalf("page misses %s:\n%s", name, body)
+		}
+		value, _, _ := strings.Cut(rest, `"`)
+		return value
+	}
+	id, prefix, token := attr(" id"), attr("data-prefix"), attr("data-token")
+
+	hook := func(headers map[string]string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+prefix+"/h/"+id+"/999?t=1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range resp.Cookies() {
+			req.AddCookie(c)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	for _, c := range []struct {
+		headers map[string]string
+		status  int
+	}{
+		{map[string]string{"Sec-Fetch-Site": "cross-site", "D0-Token": token}, http.StatusForbidden},
+		{map[string]string{"Origin": "https://evil.example.com", "D0-Token": token}, http.StatusForbidden},
+		{map[string]string{"Origin": "null", "D0-Token": token}, http.StatusForbidden},
+		{map[string]string{"Sec-Fetch-Site": "same-origin"}, http.StatusForbidden},
+		{map[string]string{"Sec-Fetch-Site": "same-origin", "D0-Token": "wrong"}, http.StatusForbidden},
+		{map[string]string{"Sec-Fetch-Site": "same-origin", "D0-Token": token}, http.StatusNotFound},
+		{map[string]string{"Sec-Fetch-Site": "same-site", "Origin": "https://admin.example.com", "D0-Token": token}, http.StatusNotFound},
+		{map[string]string{"Origin": server.URL, "D0-Token": token}, http.StatusNotFound},
+	} {
+		if status := hook(c.headers); status != c.status {
+			t.Fatalf("unexpected status %d for %v", status, c.headers)
+		}
+	}
+
+	_, _, metrics := readURL(t, server, "/metrics")
+	for _, line := range []string{
+		"doors_requests_rejected_total{reason=\"origin\"} 2\n",
+		"doors_requests_rejected_total{reason=\"fetch_site\"} 1\n",
+		"doors_requests_rejected_total{reason=\"token\"} 2\n",
+	} {
+		if !strings.Contains(metrics, line) {
+			t.Fatalf("metrics miss %q:\n%s", line, metrics)
+		}
+	}
+}
+
+func TestSocketOrigin(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		options []With
+		status  map[string]int
+	}{
+		{"default", nil, map[string]int{
+			"":                          http.StatusSwitchingProtocols,
+			"self":                      http.StatusSwitchingProtocols,
+			"https://evil.example.com":  http.StatusForbidden,
+			"https://admin.example.com": http.StatusForbidden,
+		}},
+		{"policy", []With{WithRequestPolicy(RequestPolicy{Origins: []string{"https://admin.example.com"}})}, map[string]int{
+			"https://evil.example.com":  http.StatusForbidden,
+			"https://admin.example.com": http.StatusSwitchingProtocols,
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			app := NewApp(func(context.Context, Request) gox.Comp {
+				return testPage("page")
+			}, append(c.options, WithConf(Conf{SolitaireWebSocket: true}))...)
+			server := httptest.NewServer(app)
+			defer server.Close()
+			resp, err := http.Get(server.URL + "/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			attr := func(name string) string {
+				_, rest, ok := strings.Cut(string(body), name+`="`)
+				if !ok {
+					t.Fatalf("page misses %s:\n%s", name, body)
+				}
+				value, _, _ := strings.Cut(rest, `"`)
+				return value
+			}
+			id, prefix := attr(" id"), attr("data-prefix")
+			for origin, status := range c.status {
+				if origin == "self" {
+					origin = server.URL
+				}
+				req, err := http.NewRequest(http.MethodGet, server.URL+prefix+"/s/"+id+"?t=1", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, c := range resp.Cookies() {
+					req.AddCookie(c)
+				}
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+				req.Header.Set("Sec-WebSocket-Version", "13")
+				req.Header.Set("Sec-WebSocket-Key", "MDEyMzQ1Njc4OWFiY2RlZg==")
+				if origin != "" {
+					req.Header.Set("Origin", origin)
+				}
+				res, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				res.Body.Close()
+				if res.StatusCode != status {
+					t.Fatalf("unexpected handshake status %d for origin %q", res.StatusCode, origin)
+				}
+			}
+		})
+	}
+}
+
+func TestCSPNonceAndReports(t *testing.T) {
+	reports := make(chan CSPReport, 1)
+	app := NewApp(func(context.Context, Request) gox.Comp {
+		return testPage("page")
+	}, WithCSP(CSP{
+		Nonce:          true,
+		CollectReports: true,
+		OnReport: func(_ *http.Request, report CSPReport) {
+			reports <- report
+		},
+	}))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	status, headers, body := readURL(t, server, "/")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected page status %d", status)
+	}
+	policy := headers.Get("Content-Security-Policy")
+	_, nonce, ok := strings.Cut(policy, "'nonce-")
+	if !ok {
+		t.Fatalf("expected nonce in %q", policy)
+	}
+	nonce, _, _ = strings.Cut(nonce, "'")
+	if !strings.Contains(body, `nonce="`+nonce+`"`) {
+		t.Fatalf("expected doors script to carry nonce %q:\n%s", nonce, body)
+	}
+	endpoint := "/~/doors/c"
+	if !strings.Contains(policy, "report-to doors-csp") || !strings.Contains(policy, "report-uri "+endpoint) {
+		t.Fatalf("unexpected report directives in %q", policy)
+	}
+	if got := headers.Get("Reporting-Endpoints"); got != `doors-csp="`+endpoint+`"` {
+		t.Fatalf("unexpected Reporting-Endpoints %q", got)
+	}
+
+	resp, err := http.Post(server.URL+endpoint, "application/reports+json", strings.NewReader(
+		`[{"type":"csp-violation","body":{"documentURL":"`+server.URL+`/","blockedURL":"inline","effectiveDirective":"script-src-elem"}}]`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || len(resp.Cookies()) != 0 {
+		t.Fatalf("unexpected report response: status=%d cookies=%v", resp.StatusCode, resp.Cookies())
+	}
+	if report := <-reports; report.BlockedURL != "inline" || report.EffectiveDirective != "script-src-elem" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if app.SessionCount() != 1 {
+		t.Fatalf("report created a session: %d sessions", app.SessionCount())
+	}
+}
+
+func TestCSPReportRateLimit(t *testing.T) {
+	var reports atomic.Int32
+	app := NewApp(func(context.Context, Request) gox.Comp {
+		return testPage("page")
+	}, WithCSP(CSP{
+		CollectReports:  true,
+		ReportRateLimit: RateLimit{Every: time.Hour, Burst: 2},
+		OnReport: func(*http.Request, CSPReport) {
+			reports.Add(1)
+		},
+	}))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	report := `[{"type":"csp-violation","body":{"documentURL":"` + server.URL + `/","blockedURL":"inline","effectiveDirective":"script-src-elem"}}]`
+	for i, want := range []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		resp, err := http.Post(server.URL+"/~/doors/c", "application/reports+json", strings.NewReader(report))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("report %d: expected status %d, got %d", i, want, resp.StatusCode)
+		}
+	}
+	if n := reports.Load(); n != 2 {
+		t.Fatalf("expected 2 reports passed on, got %d", n)
+	}
+	var metrics strings.Builder
+	if err := app.WriteMetrics(&metrics); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(metrics.String(), "doors_csp_reports_dropped_total 2\n") {
+		t.Fatalf("expected 2 dropped reports in metrics:\n%s", metrics.String())
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	var override atomic.Bool
+	app := NewApp(func(ctx context.Context, r Request) gox.Comp {
+		if !override.Load() {
+			return testPage("page")
+		}
+		return gox.Elem(func(cur gox.Cursor) error {
+			if err := OverrideSecurityHeaders(SecurityHeaders{
+				PermissionsPolicy:       "camera=(self)",
+				CrossOriginOpenerPolicy: SecurityHeaderOmit,
+				StrictTransportSecurity: "max-age=60",
+			}).Edit(cur); err != nil {
+				return err
+			}
+			return testPage("camera").Main()(cur)
+		})
+	}, WithSecurityHeaders(SecurityHeaders{
+		CrossOriginResourcePolicy: "cross-origin",
+		ReferrerPolicy:            SecurityHeaderOmit,
+	}))
+	app.Use(UseResource("asset.txt", ResourceBytes([]byte("asset")), "text/plain"))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	for _, path := range []string{"/", "/asset.txt", "/~/doors/h/missing/1"} {
+		_, headers, _ := readURL(t, server, path)
+		for name, value := range map[string]string{
+			"Strict-Transport-Security":    "",
+			"X-Content-Type-Options":       "nosniff",
+			"Cross-Origin-Opener-Policy":   "same-origin",
+			"Cross-Origin-Resource-Policy": "cross-origin",
+			"Permissions-Policy":           "camera=(), microphone=(), geolocation=(), payment=(), usb=()",
+			"Referrer-Policy":              "",
+		} {
+			if got := headers.Get(name); got != value {
+				t.Fatalf("unexpected %s on %s: %q", name, path, got)
+			}
+		}
+	}
+	override.Store(true)
+	_, headers, body := readURL(t, server, "/camera")
+	if !strings.Contains(body, "camera") || headers.Get("Permissions-Policy") != "camera=(self)" {
+		t.Fatalf("expected route override, got %q", headers.Get("Permissions-Policy"))
+	}
+	if _, ok := headers["Cross-Origin-Opener-Policy"]; ok || headers.Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatalf("unexpected headers with route override: %v", headers)
+	}
+
+	resp, err := http.Get(server.URL + "/camera")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	attr := func(name string) string {
+		_, rest, ok := strings.Cut(string(page), name+`="`)
+		if !ok {
+			t.Fatalf("page misses %s:\n%s", name, page)
+		}
+		value, _, _ := strings.Cut(rest, `"`)
+		return value
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL+attr("data-prefix")+"/h/"+attr(" id")+"/999?t=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range resp.Cookies() {
+		req.AddCookie(c)
+	}
+	req.Header.Set("Sec-Fetch-Site", "same-origin")
+	hook, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook.Body.Close()
+	if hook.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected hook status %d", hook.StatusCode)
+	}
+	if got := hook.Header.Get("Permissions-Policy"); got != "camera=(self)" || hook.Header.Get("Strict-Transport-Security") != "max-age=60" {
+		t.Fatalf("expected route override on hook response, got %v", hook.Header)
+	}
+	if _, ok := hook.Header["Cross-Origin-Opener-Policy"]; ok || hook.Header.Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatalf("unexpected hook headers with route override: %v", hook.Header)
+	}
+}
+
+func TestAppBasePath(t *testing.T) {
+	app := NewApp(func(ctx context.Context, r Request) gox.Comp {
+		href, err := Href(ctx, Location{Segments: []string{"docs"}})
+		if err != nil {
+			t.Error(err)
+		}
+		return testPage(href)
+	}, WithBasePath("/admin/"))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	status, _, body := readURL(t, server, "/admin/guide")
+	if status != http.StatusOK || !strings.Contains(body, "/admin/docs") {
+		t.Fatalf("unexpected page response: status=%d body=%q", status, body)
+	}
+	status, _, _ = readURL(t, server, "/guide")
+	if status != http.StatusNotFound {
+		t.Fatalf("unexpected status outside of base path: %d", status)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected WithBasePath to reject escaped base")
+		}
+	}()
+	_ = WithBasePath("/a b")
+}
+
+func TestRouteModelGuard(t *testing.T) {
+	type oldPath struct {
+		Old bool `path:"/old"`
+	}
+	type newPath struct {
+		New bool `path:"/new"`
+	}
+	type adminPath struct {
+		Admin bool `path:"/admin"`
+	}
+	app := NewApp(func(ctx context.Context, r Request) gox.Comp {
+		return Route(
+			RouteModel(func(Source[oldPath]) gox.Comp {
+				return testPage("old")
+			}).Guard(func(ctx context.Context, _ oldPath) (any, bool) {
+				return RouteRedirect{Model: newPath{New: true}, Status: http.StatusMovedPermanently}, false
+			}),
+			RouteModel(func(Source[newPath]) gox.Comp {
+				return testPage("new")
+			}),
+			RouteModel(func(Source[adminPath]) gox.Comp {
+				return testPage("admin")
+			}).Guard(func(ctx context.Context, _ adminPath) (any, bool) {
+				return nil, false
+			}),
+			RouteDefault(func(Source[Location]) gox.Comp {
+				return testPage("fallback")
+			}),
+		)
+	}, WithBasePath("/app"))
+	server := httptest.NewServer(app)
+	defer server.Close()
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Get(server.URL + "/app/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMovedPermanently || resp.Header.Get("Location") != "/app/new" {
+		t.Fatalf("unexpected guard redirect: status=%d location=%q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	status, _, body := readURL(t, server, "/app/admin")
+	if status != http.StatusOK || !strings.Contains(body, "fallback") || strings.Contains(body, "admin") {
+		t.Fatalf("unexpected guarded fallback: status=%d body=%q", status, body)
+	}
+}
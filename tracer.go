@@ -0,0 +1,143 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/doors-dev/doors/internal/common"
+)
+
+// Span attribute keys used by Doors.
+const (
+	TraceSessionID    = common.TraceSessionID
+	TraceInstanceID   = common.TraceInstanceID
+	TraceDoorID       = common.TraceDoorID
+	TraceHookID       = common.TraceHookID
+	TracePayloadBytes = common.TracePayloadBytes
+)
+
+// RecordedSpan is a span captured by [TraceRecorder].
+type RecordedSpan struct {
+	// Name is the span name, such as [SpanHook].
+	Name string
+	// Attrs holds the attributes in the order they were set.
+	Attrs []slog.Attr
+	// Start is the time the span was started.
+	Start time.Time
+	// End is the time the span was ended.
+	End time.Time
+	// Err is the error the span was ended with, or nil.
+	Err error
+}
+
+// Attr returns the value of the last attribute with key.
+func (s RecordedSpan) Attr(key string) (slog.Value, bool) {
+	for _, attr := range slices.Backward(s.Attrs) {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// TraceRecorder is a [Tracer] that keeps ended spans in memory.
+// It is meant for tests. Its methods are safe for concurrent use.
+type TraceRecorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewTraceRecorder creates an empty [TraceRecorder].
+func NewTraceRecorder() *TraceRecorder {
+	return &TraceRecorder{}
+}
+
+var _ Tracer = (*TraceRecorder)(nil)
+
+// Start implements [Tracer].
+func (r *TraceRecorder) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	return ctx, &recordedSpan{
+		recorder: r,
+		span: RecordedSpan{
+			Name:  name,
+			Attrs: slices.Clone(attrs),
+			Start: time.Now(),
+		},
+	}
+}
+
+// Spans returns the ended spans in the order they ended.
+func (r *TraceRecorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.spans)
+}
+
+// Named returns the ended spans with name in the order they ended.
+func (r *TraceRecorder) Named(name string) []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var spans []RecordedSpan
+	for _, span := range r.spans {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Reset drops the recorded spans.
+func (r *TraceRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+type recordedSpan struct {
+	recorder *TraceRecorder
+	mu       sync.Mutex
+	span     RecordedSpan
+	ended    bool
+}
+
+func (s *recordedSpan) SetAttrs(attrs ...slog.Attr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.span.Attrs = append(s.span.Attrs, attrs...)
+}
+
+func (s *recordedSpan) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.span.End = time.Now()
+	s.span.Err = err
+	span := s.span
+	s.mu.Unlock()
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.spans = append(s.recorder.spans, span)
+}
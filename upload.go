@@ -0,0 +1,166 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// UploadProgress is the progress of a request body as received by the
+// server. See the Progress field of [ASubmit], [ARawSubmit], and [ARawHook].
+type UploadProgress struct {
+	// Read is the number of body bytes received so far.
+	Read int64
+	// Total is the declared body size, or -1 when unknown.
+	Total int64
+	// Done reports that the handler finished reading the body.
+	Done bool
+}
+
+// Percent returns the received share of the body in whole percent, or -1
+// when the total is unknown.
+func (p UploadProgress) Percent() int {
+	if p.Total < 0 {
+		if p.Done {
+			return 100
+		}
+		return -1
+	}
+	if p.Total == 0 || p.Read >= p.Total {
+		return 100
+	}
+	return int(p.Read * 100 / p.Total)
+}
+
+// progressUnknownStep is how often progress of a body with unknown size is
+// reported.
+const progressUnknownStep = 1 << 20
+
+type progressReader struct {
+	io.ReadCloser
+	ctx      context.Context
+	source   Source[UploadProgress]
+	progress UploadProgress
+	reported UploadProgress
+}
+
+// trackUpload reports reads of the request body to source until finish is
+// called. It is a no-op when source is nil.
+func trackUpload(ctx context.Context, r *http.Request, source Source[UploadProgress]) (finish func()) {
+	if source == nil {
+		return func() {}
+	}
+	p := &progressReader{
+		ReadCloser: r.Body,
+		ctx:        ctx,
+		source:     source,
+		progress: UploadProgress{
+			Total: r.ContentLength,
+		},
+	}
+	r.Body = p
+	p.report()
+	return p.finish
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	p.progress.Read += int64(n)
+	if err == io.EOF {
+		p.finish()
+	} else if p.due() {
+		p.report()
+	}
+	return n, err
+}
+
+func (p *progressReader) due() bool {
+	if p.progress.Total < 0 {
+		return p.progress.Read-p.reported.Read >= progressUnknownStep
+	}
+	return p.progress.Percent() != p.reported.Percent()
+}
+
+func (p *progressReader) finish() {
+	if p.progress.Done {
+		return
+	}
+	p.progress.Done = true
+	p.report()
+}
+
+func (p *progressReader) report() {
+	p.reported = p.progress
+	p.source.Update(p.ctx, p.progress)
+}
+
+// ErrPartTooLarge is returned by reads of a [StreamPart] beyond the part
+// limit.
+var ErrPartTooLarge = errors.New("doors: multipart part too large")
+
+// MultipartStream reads multipart parts as they arrive, without buffering the
+// body in memory or on disk. Obtain it from [RequestRawForm.Stream].
+type MultipartStream struct {
+	reader *multipart.Reader
+	limit  int64
+}
+
+// NextPart returns the next part, or io.EOF after the last one. The unread
+// remainder of the previous part is skipped.
+func (s *MultipartStream) NextPart() (*StreamPart, error) {
+	part, err := s.reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	return &StreamPart{
+		Part:  part,
+		limit: s.limit,
+	}, nil
+}
+
+// StreamPart is a multipart part whose reads are bounded by the part limit
+// of its [MultipartStream].
+type StreamPart struct {
+	*multipart.Part
+	limit int64
+	read  int64
+}
+
+// Read reads the part content. It fails with [ErrPartTooLarge] once the
+// part turns out to be larger than the limit.
+func (p *StreamPart) Read(b []byte) (int, error) {
+	if p.limit < 0 {
+		return p.Part.Read(b)
+	}
+	remaining := p.limit - p.read
+	if remaining <= 0 {
+		var probe [1]byte
+		n, err := p.Part.Read(probe[:])
+		if n > 0 {
+			return 0, ErrPartTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(b)) > remaining {
+		b = b[:remaining]
+	}
+	n, err := p.Part.Read(b)
+	p.read += int64(n)
+	return n, err
+}
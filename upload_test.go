@@ -0,0 +1,84 @@
+// Copyright 2026 doors dev LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMultipartStreamPartLimit(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("small", "abc")
+	mw.WriteField("large", strings.Repeat("x", 10))
+	mw.WriteField("exact", "12345")
+	mw.Close()
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	req := &request{w: httptest.NewRecorder(), r: r, limit: -1}
+
+	stream, err := req.Stream(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := map[string]error{}
+	for {
+		part, err := stream.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = io.ReadAll(part)
+		results[part.FormName()] = err
+	}
+	if results["small"] != nil || results["exact"] != nil {
+		t.Fatalf("unexpected part errors: %v", results)
+	}
+	if !errors.Is(results["large"], ErrPartTooLarge) {
+		t.Fatalf("expected part limit error, got %v", results["large"])
+	}
+}
+
+func TestTrackUpload(t *testing.T) {
+	const size = 1000
+	r := httptest.NewRequest("POST", "/", strings.NewReader(strings.Repeat("x", size)))
+	progress := NewSourceNoSkip(UploadProgress{})
+	finish := trackUpload(context.Background(), r, progress)
+	if p := progress.Get(); p.Total != size || p.Read != 0 || p.Done {
+		t.Fatalf("unexpected initial progress: %+v", p)
+	}
+	buf := make([]byte, 300)
+	r.Body.Read(buf)
+	if p := progress.Get(); p.Read != 300 || p.Percent() != 30 {
+		t.Fatalf("unexpected progress: %+v", p)
+	}
+	io.ReadAll(r.Body)
+	finish()
+	if p := progress.Get(); p.Read != size || !p.Done || p.Percent() != 100 {
+		t.Fatalf("unexpected final progress: %+v", p)
+	}
+	if p := (UploadProgress{Read: 10, Total: -1}); p.Percent() != -1 {
+		t.Fatalf("expected unknown percent, got %d", p.Percent())
+	}
+}
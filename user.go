@@ -16,6 +16,7 @@ package doors
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/doors-dev/doors/internal/common"
@@ -95,6 +96,25 @@ func SessionLastSeen(ctx context.Context) time.Time {
 	return sess.LastSeen()
 }
 
+// SessionTag labels the current session with key and value, for example
+// the signed-in user ID. An empty value removes the label. Tags are saved
+// with the session, show up in [App.Sessions], and let [App.EndSessions]
+// end every session of a user:
+//
+//	doors.SessionTag(ctx, "user", user.ID)
+//	// later, after a password change
+//	app.EndSessions(doors.Tag{Key: "user", Value: user.ID})
+func SessionTag(ctx context.Context, key string, value string) {
+	sess := ctx.Value(common.KeySession).(core.Session)
+	sess.Tag(key, value)
+}
+
+// SessionTags returns a copy of the labels of the current session.
+func SessionTags(ctx context.Context) map[string]string {
+	sess := ctx.Value(common.KeySession).(core.Session)
+	return sess.Tags()
+}
+
 // Store is goroutine-safe key-value storage used for session and instance
 // data.
 type Store = ctex.Store
@@ -105,6 +125,38 @@ func SessionStore(ctx context.Context) Store {
 	return sess.Store()
 }
 
+// SessionKey is a typed [Store] key whose value is persisted by the app's
+// [SessionBackend] and restored when the session is rehydrated after a
+// restart.
+//
+// Values must round-trip through encoding/json. Only [SessionStore] persists
+// them; in [InstanceStore] a SessionKey behaves like any other key.
+type SessionKey[T any] struct {
+	name string
+}
+
+// NewSessionKey returns a [SessionKey] saved under name. Names must be unique
+// per app and stable across deploys.
+func NewSessionKey[T any](name string) SessionKey[T] {
+	return SessionKey[T]{name: name}
+}
+
+// PersistentName returns the name the value is saved under.
+func (k SessionKey[T]) PersistentName() string {
+	return k.name
+}
+
+// Decode restores a value serialized for this key.
+func (k SessionKey[T]) Decode(data []byte) (any, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+var _ ctex.PersistentKey = SessionKey[int]{}
+
 // InstanceStore returns storage scoped to the current instance only.
 func InstanceStore(ctx context.Context) Store {
 	core := ctx.Value(common.KeyCore).(core.Core)
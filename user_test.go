@@ -18,7 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"testing"
 	"time"
@@ -154,6 +156,17 @@ func (h *helperInstance) CSPCollector() common.CSPCollector {
 	return (&common.CSP{}).NewCollector()
 }
 
+func (h *helperInstance) CSPNonce() string {
+	return ""
+}
+
+func (h *helperInstance) SetSecurityHeaders(common.SecurityHeaders) {
+}
+
+func (h *helperInstance) Token() string {
+	return ""
+}
+
 func (h *helperInstance) ModuleRegistry() core.ModuleRegistry {
 	return nil
 }
@@ -184,6 +197,8 @@ func (h *helperInstance) Runtime() shredder.Runtime {
 
 func (h *helperInstance) SetStatus(int) {}
 
+func (h *helperInstance) Redirect(string, int) bool { return false }
+
 func (h *helperInstance) Session() core.Session {
 	return h.session
 }
@@ -206,6 +221,10 @@ func (h *helperInstance) TitleMeta() core.TitleMeta {
 	return nil
 }
 
+func (h *helperInstance) PayloadCompression() common.Compression {
+	return common.Compression{Encoding: common.EncodingGzip}
+}
+
 type helperApp struct {
 	conf *common.Conf
 }
@@ -230,11 +249,22 @@ func (h *helperApp) Draining() bool {
 	return false
 }
 
+func (h *helperApp) WriteMetrics(w io.Writer) error {
+	return nil
+}
+
+func (h *helperApp) Tracer() common.Tracer {
+	return common.NoopTracer{}
+}
+
+func (h *helperApp) HookLimited(ctx context.Context, abuse common.HookAbuse) {}
+
 type helperSession struct {
 	inst   *helperInstance
 	app    *helperApp
 	ctx    context.Context
 	cancel context.CancelFunc
+	tags   map[string]string
 }
 
 func (h *helperSession) Logger() *slog.Logger {
@@ -265,6 +295,17 @@ func (h *helperSession) Expire(d time.Duration) {
 	h.inst.expire = d
 }
 
+func (h *helperSession) Tag(key string, value string) {
+	if h.tags == nil {
+		h.tags = make(map[string]string)
+	}
+	h.tags[key] = value
+}
+
+func (h *helperSession) Tags() map[string]string {
+	return maps.Clone(h.tags)
+}
+
 func (h *helperSession) Kill() {
 	h.cancel()
 }